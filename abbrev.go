@@ -0,0 +1,128 @@
+package geobed
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// abbrevTokens returns the standalone 2-3 letter tokens of n that may be
+// region or country codes ("TX", "NSW", "US"), as scored by DefaultRanker.
+// Short tokens collide with ordinary words ("in", "or", "de", "la"), so a
+// token only counts where codes are written:
+//
+//   - at the start or end of the query, or next to a comma ("Paris, TX, US");
+//     mid-query words as in "Walk in the park" are skipped;
+//   - lowercase tokens in a query that has capitals elsewhere ("Eugene or
+//     Portland") count only next to a comma;
+//   - fragments of longer words around non-ASCII letters ("Qu" in "Québec")
+//     never count.
+func abbrevTokens(n string) []string {
+	hasUpper := strings.IndexFunc(n, unicode.IsUpper) >= 0
+
+	var tokens []string
+	for _, loc := range abbrevRegex().FindAllStringIndex(n, -1) {
+		start, end := loc[0], loc[1]
+		before, after := strings.TrimRight(n[:start], " "), strings.TrimLeft(n[end:], " ")
+		if r, _ := utf8.DecodeLastRuneInString(n[:start]); unicode.IsLetter(r) {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(n[end:]); unicode.IsLetter(r) {
+			continue
+		}
+
+		byComma := strings.HasSuffix(before, ",") || strings.HasPrefix(after, ",")
+		atEdge := before == "" || after == "" || strings.Trim(after, ".") == ""
+		if !byComma && !atEdge {
+			continue
+		}
+		tok := n[start:end]
+		if hasUpper && !byComma && tok == toLower(tok) {
+			continue
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+// preferCountryCode reports whether code, matched as a US state code next to
+// name, should rather be read as the ISO country code it also is. That is
+// the case when name is a city of that country but not of the state, as in
+// "Córdoba, AR" (Argentina, not Arkansas); otherwise the state reading
+// stands.
+func (g *GeoBed) preferCountryCode(name, code string) bool {
+	if _, ok := g.countryISO[code]; !ok {
+		return false
+	}
+	return g.hasCityIn(name, code, "") && !g.hasCityIn(name, "US", code)
+}
+
+// trailingCountryCode splits a trailing ISO 3166 alpha-2 or alpha-3 code off
+// n, returning the country and the rest of the query. Without a comma the
+// code must be written in capitals ("Paris FR") and n must not itself be a
+// known name, so place names ending in a short word are left intact.
+func (g *GeoBed) trailingCountryCode(n string) (country, rest string, ok bool) {
+	i := strings.LastIndexAny(n, " ,")
+	if i < 0 {
+		return "", "", false
+	}
+	tok := n[i+1:]
+	rest = strings.TrimRight(n[:i+1], " ,")
+	if rest == "" || len(tok) < 2 || len(tok) > 3 {
+		return "", "", false
+	}
+	code := toUpper(tok)
+	if !strings.Contains(n[len(rest):i+1], ",") {
+		if tok != code {
+			return "", "", false
+		}
+		if _, known := g.nameIndex[g.nameKey(n)]; known {
+			return "", "", false
+		}
+	}
+	if country, ok = g.countryCode(code, rest); !ok {
+		return "", "", false
+	}
+	return country, rest, true
+}
+
+// countryCode resolves an uppercase ISO 3166 alpha-2 or alpha-3 code written
+// next to the city name. A code that is also an admin division of another
+// country ("FR" is Fribourg in Switzerland) is only read as a country when
+// the city is not found in that division alone.
+func (g *GeoBed) countryCode(code, name string) (string, bool) {
+	idx, found := g.countryISO[code]
+	if !found {
+		return "", false
+	}
+	country := g.Countries[idx].ISO
+	if admin := g.getAdminDivisionCountry(code); admin != "" && admin != country &&
+		g.hasCityIn(name, admin, code) && !g.hasCityIn(name, country, "") {
+		return "", false
+	}
+	return country, true
+}
+
+// hasCityIn reports whether name is a city or alternate name in the given
+// country and, unless region is empty, region.
+func (g *GeoBed) hasCityIn(name, country, region string) bool {
+	for _, i := range g.nameIndex[g.nameKey(strings.Trim(name, " ,"))] {
+		c := g.Cities[i]
+		if c.Country() == country && (region == "" || c.Region() == region) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutUSDefault stops US state codes and names in queries from implying
+// country US. By default "Springfield, IL" is read as Springfield in
+// Illinois, United States, and candidates elsewhere are penalized; with this
+// option the state still counts as a region hint but foreign candidates are
+// not penalized, which suits international data where two-letter tokens are
+// more often foreign region or country codes.
+func WithoutUSDefault() Option {
+	return func(c *GeobedConfig) {
+		c.NoUSDefault = true
+	}
+}
@@ -0,0 +1,196 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAbbrevTokens(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Austin, TX", []string{"TX"}},
+		{"Austin TX", []string{"TX"}},
+		{"TX Austin", []string{"TX"}},
+		{"Paris, TX, US", []string{"TX", "US"}},
+		{"Sydney NSW", []string{"NSW"}},
+		{"LA", []string{"LA"}},
+		{"Walk in the park", nil},        // Mid-query words
+		{"Eugene or Portland", nil},      // Lowercase mid-query word
+		{"Portland, or", []string{"or"}}, // Lowercase but comma-separated
+		{"portland or", []string{"or"}},  // All lowercase: position decides
+		{"Québec", nil},                  // "Qu" is a fragment of a word
+		{"São Paulo, SP", []string{"SP"}},
+		{"St. Louis, MO", []string{"St", "MO"}},
+	}
+	for _, tt := range tests {
+		if got := abbrevTokens(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("abbrevTokens(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestAbbrevCollisions(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query      string
+		wantCity   string
+		wantRegion string
+	}{
+		{"LA", "Los Angeles", "CA"},
+		{"Baton Rouge, LA", "Baton Rouge", "LA"},
+		{"Lafayette LA", "Lafayette", "LA"},
+		{"Lafayette, IN", "Lafayette", "IN"},
+		{"Indianapolis IN", "Indianapolis", "IN"},
+		{"Portland OR", "Portland", "OR"},
+		{"Salem, OR", "Salem", "OR"},
+		{"Dover DE", "Dover", "DE"},
+		{"Newark, DE", "Newark", "DE"},
+	}
+	for _, tt := range tests {
+		got := g.Geocode(tt.query)
+		if got.City != tt.wantCity || got.Region() != tt.wantRegion {
+			t.Errorf("Geocode(%q) = %s, %s; want %s, %s", tt.query, got.City, got.Region(), tt.wantCity, tt.wantRegion)
+		}
+	}
+
+	// Common English words must not pull results into the state they spell.
+	for _, query := range []string{"Walk in the park", "Eugene or Portland"} {
+		if got := g.Geocode(query); got.Region() == "IN" {
+			t.Errorf("Geocode(%q) = %s, IN; the word \"in\" was taken for Indiana", query, got.City)
+		}
+	}
+}
+
+func TestStateCodeVsCountryCode(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query       string
+		wantCity    string
+		wantCountry string
+	}{
+		{"Córdoba, AR", "Córdoba", "AR"}, // Argentina: no Córdoba in Arkansas
+		{"Rosario, AR", "Rosario", "AR"},
+		{"Little Rock, AR", "Little Rock", "US"}, // Arkansas
+		{"Hamburg, DE", "Hamburg", "DE"},         // Germany
+		{"Dover, DE", "Dover", "US"},             // Delaware
+		{"Toronto, CA", "Toronto", "CA"},         // Canada
+		{"San Jose, CA", "San Jose", "US"},       // California
+		{"Mumbai, IN", "Mumbai", "IN"},           // India
+		{"Indianapolis, IN", "Indianapolis", "US"},
+		{"Austin, Texas", "Austin", "US"}, // State names always mean the US
+	}
+	for _, tt := range tests {
+		if got := g.Geocode(tt.query); got.City != tt.wantCity || got.Country() != tt.wantCountry {
+			t.Errorf("Geocode(%q) = %s, %s; want %s, %s", tt.query, got.City, got.Country(), tt.wantCity, tt.wantCountry)
+		}
+	}
+}
+
+func TestWithoutUSDefault(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Perth", Country: "AU", Region: "08", Latitude: -31.95224, Longitude: 115.8614, Population: 1896548},
+		{City: "Perth Amboy", Country: "US", Region: "NJ", Latitude: 40.50677, Longitude: -74.26542, Population: 55436},
+		{City: "Paris", Country: "US", Region: "TX", Latitude: 33.66094, Longitude: -95.55551, Population: 24782},
+		{City: "Springfield", Country: "US", Region: "IL", Latitude: 39.80172, Longitude: -89.64371, Population: 116565},
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	}, nil, WithoutUSDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WA is only a state code, so by default Perth Amboy, NJ beats Perth,
+	// Western Australia; without the US default the larger Perth wins.
+	if got := g.Geocode("Perth, WA"); got.City != "Perth" || got.Country() != "AU" {
+		t.Errorf("Geocode(Perth, WA) = %s, %s; want Perth, AU", got.City, got.Country())
+	}
+	// The state still counts as a region hint.
+	if got := g.Geocode("Paris, TX"); got.Region() != "TX" {
+		t.Errorf("Geocode(Paris, TX) = %s, %s; want region TX", got.City, got.Region())
+	}
+	if co, st, _, _ := g.parseLocationPieces("Springfield, IL"); co != "" || st != "IL" {
+		t.Errorf("parseLocationPieces(Springfield, IL) = %q, %q; want no country, state IL", co, st)
+	}
+	// A misspelled state name is no US hint either.
+	if co, st, _ := g.fuzzyLocationSuffix("", "", []string{"Austin,", "Texsa"}, 2); co != "" || st != "TX" {
+		t.Errorf("fuzzyLocationSuffix(Austin, Texsa) = %q, %q; want no country, state TX", co, st)
+	}
+	if got := g.Geocode("Austin, Texsa", GeocodeOptions{FuzzyDistance: 2}); got.City != "Austin" || got.Region() != "TX" {
+		t.Errorf("Geocode(Austin, Texsa, fuzzy=2) = %s, %s; want Austin, TX", got.City, got.Region())
+	}
+}
+
+func TestTrailingCountryCode(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query       string
+		wantCountry string // "" = not read as a country code
+		wantRest    string
+	}{
+		{"Paris, FR", "FR", "Paris"},
+		{"Paris FR", "FR", "Paris"},
+		{"paris, fr", "FR", "paris"},
+		{"Paris, FRA", "FR", "Paris"}, // ISO3
+		{"Berlin DEU", "DE", "Berlin"},
+		{"Tbilisi, GE", "GE", "Tbilisi"},
+		{"Geneva, GE", "", ""},   // Canton of Geneva, not Georgia
+		{"Fribourg, FR", "", ""}, // Canton of Fribourg, not France
+		{"Paris fr", "", ""},     // Lowercase without a comma is a word
+		{"Cu Chi", "", ""},       // Not a country code
+		{"FR", "", ""},           // Nothing left for the city
+	}
+	for _, tt := range tests {
+		co, rest, ok := g.trailingCountryCode(tt.query)
+		if ok != (tt.wantCountry != "") || co != tt.wantCountry || rest != tt.wantRest {
+			t.Errorf("trailingCountryCode(%q) = %q, %q, %v; want %q, %q", tt.query, co, rest, ok, tt.wantCountry, tt.wantRest)
+		}
+	}
+}
+
+func TestGeocode_CountryCodes(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query       string
+		wantCity    string
+		wantCountry string
+	}{
+		{"Paris, FR", "Paris", "FR"},
+		{"Berlin, DE", "Berlin", "DE"},
+		{"London, GBR", "London", "GB"},
+		{"Sydney AUS", "Sydney", "AU"},
+		{"Moscow, RU", "Moscow", "RU"},
+		{"Moscow, ID", "Moscow", "US"}, // Idaho: US state codes come first
+		{"Geneva, GE", "Genève", "CH"},
+	}
+	for _, tt := range tests {
+		if got := g.Geocode(tt.query); got.City != tt.wantCity || got.Country() != tt.wantCountry {
+			t.Errorf("Geocode(%q) = %s, %s; want %s, %s", tt.query, got.City, got.Country(), tt.wantCity, tt.wantCountry)
+		}
+	}
+
+	// A code is as strong as the full country name.
+	for _, pair := range [][2]string{{"Paris, FR", "Paris, France"}, {"Berlin, DE", "Berlin, Germany"}} {
+		co1, _, _, names1 := g.parseLocationPieces(pair[0])
+		co2, _, _, names2 := g.parseLocationPieces(pair[1])
+		if co1 != co2 || !slices.Equal(names1, names2) {
+			t.Errorf("parseLocationPieces(%q) = %q %q, (%q) = %q %q; want equal", pair[0], co1, names1, pair[1], co2, names2)
+		}
+	}
+}
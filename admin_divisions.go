@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
 // AdminDivision represents a first-level administrative division (state, province, etc.)
@@ -15,21 +16,43 @@ type AdminDivision struct {
 
 // adminDivisionsCache caches loaded admin divisions per data directory.
 // This avoids re-loading from disk for each GeoBed instance with the same directory.
+// Entries remember the file's size and modification time, so an instance
+// created after the file was replaced (e.g. by a fresh download) loads the new
+// divisions instead of those cached for an earlier instance.
 var (
-	adminDivisionsCache   = make(map[string]map[string]map[string]AdminDivision) // dataDir -> country -> code -> division
+	adminDivisionsCache   = make(map[string]adminDivisionsEntry) // dataDir -> entry
 	adminDivisionsCacheMu sync.RWMutex
 )
 
+// adminDivisionsEntry is a cached admin1 file: country -> code -> division.
+type adminDivisionsEntry struct {
+	size      int64
+	modTime   time.Time
+	divisions map[string]map[string]AdminDivision
+}
+
+// matches reports whether the entry was loaded from the file described by fi.
+func (e adminDivisionsEntry) matches(fi os.FileInfo) bool {
+	return e.size == fi.Size() && e.modTime.Equal(fi.ModTime())
+}
+
 // loadAdminDivisionsForDir loads admin1 codes from the specified data directory.
 // Returns a map of country code -> division code -> AdminDivision.
 // Thread-safe: uses a per-directory cache to avoid redundant loading.
 // Format: CC.CODE<tab>Name<tab>AsciiName<tab>GeonameId
 func loadAdminDivisionsForDir(dataDir string) map[string]map[string]AdminDivision {
+	divisions := make(map[string]map[string]AdminDivision)
+	path := dataDir + "/admin1CodesASCII.txt"
+	info, err := os.Stat(path)
+	if err != nil {
+		return divisions // not cached, see below
+	}
+
 	// Fast path: check cache with read lock
 	adminDivisionsCacheMu.RLock()
-	if cached, ok := adminDivisionsCache[dataDir]; ok {
+	if cached, ok := adminDivisionsCache[dataDir]; ok && cached.matches(info) {
 		adminDivisionsCacheMu.RUnlock()
-		return cached
+		return cached.divisions
 	}
 	adminDivisionsCacheMu.RUnlock()
 
@@ -38,14 +61,12 @@ func loadAdminDivisionsForDir(dataDir string) map[string]map[string]AdminDivisio
 	defer adminDivisionsCacheMu.Unlock()
 
 	// Double-check after acquiring write lock
-	if cached, ok := adminDivisionsCache[dataDir]; ok {
-		return cached
+	if cached, ok := adminDivisionsCache[dataDir]; ok && cached.matches(info) {
+		return cached.divisions
 	}
 
-	divisions := make(map[string]map[string]AdminDivision)
-
 	// Try to load from file
-	fi, err := os.Open(dataDir + "/admin1CodesASCII.txt")
+	fi, err := os.Open(path)
 	if err != nil {
 		// DO NOT cache failures - allows retry on next call
 		// This handles transient I/O errors (file being written during
@@ -91,14 +112,33 @@ func loadAdminDivisionsForDir(dataDir string) map[string]map[string]AdminDivisio
 	if err := scanner.Err(); err != nil {
 		return divisions // don't cache partial data
 	}
-	adminDivisionsCache[dataDir] = divisions
+	adminDivisionsCache[dataDir] = adminDivisionsEntry{size: info.Size(), modTime: info.ModTime(), divisions: divisions}
 	return divisions
 }
 
+// adminDivisions returns the admin divisions of the instance's data
+// directory. The first successful load is kept for the lifetime of the
+// instance, so its answers stay consistent with its cities even when the
+// file is later replaced for other instances.
+func (g *GeoBed) adminDivisions() map[string]map[string]AdminDivision {
+	if d := g.admin1.Load(); d != nil {
+		return *d
+	}
+	d := loadAdminDivisionsForDir(g.config.DataDir)
+	if len(d) > 0 {
+		g.admin1.Store(&d)
+	}
+	return d
+}
+
 // isAdminDivision checks if a code is a valid admin division for a specific country.
-// Returns true if the code exists for that country.
+// Returns true if the code exists for that country and its CountryRules
+// allow admin codes.
 func (g *GeoBed) isAdminDivision(countryCode, divisionCode string) bool {
-	divisions := loadAdminDivisionsForDir(g.config.DataDir)
+	if g.countryRules(countryCode).NoAdminCodes {
+		return false
+	}
+	divisions := g.adminDivisions()
 	divisionCode = toUpper(divisionCode)
 	if countryDivisions, ok := divisions[countryCode]; ok {
 		_, exists := countryDivisions[divisionCode]
@@ -112,13 +152,13 @@ func (g *GeoBed) isAdminDivision(countryCode, divisionCode string) bool {
 // Use isAdminDivision with a known country for precise matching.
 // Examples: "TX" -> "US", "ON" -> "CA", "NSW" -> "AU"
 func (g *GeoBed) getAdminDivisionCountry(code string) string {
-	divisions := loadAdminDivisionsForDir(g.config.DataDir)
+	divisions := g.adminDivisions()
 	code = toUpper(code)
 
 	// Collect all countries that have this division code
 	var matches []string
 	for countryCode, countryDivisions := range divisions {
-		if _, ok := countryDivisions[code]; ok {
+		if _, ok := countryDivisions[code]; ok && !g.countryRules(countryCode).NoAdminCodes {
 			matches = append(matches, countryCode)
 		}
 	}
@@ -132,7 +172,7 @@ func (g *GeoBed) getAdminDivisionCountry(code string) string {
 
 // getAdminDivisionName returns the name of an admin division given country and division code.
 func (g *GeoBed) getAdminDivisionName(countryCode, divisionCode string) string {
-	divisions := loadAdminDivisionsForDir(g.config.DataDir)
+	divisions := g.adminDivisions()
 	divisionCode = toUpper(divisionCode)
 	if countryDivisions, ok := divisions[countryCode]; ok {
 		if div, exists := countryDivisions[divisionCode]; exists {
@@ -141,3 +181,45 @@ func (g *GeoBed) getAdminDivisionName(countryCode, divisionCode string) string {
 	}
 	return ""
 }
+
+// findAdminDivisionByName returns the country and code of the admin division
+// with the given name, compared case-insensitively (e.g., "Ontario" -> "CA",
+// "08"). With an empty countryCode the name must be unambiguous across
+// countries; otherwise both results are empty. Countries whose CountryRules
+// set NoAdminNames are skipped.
+func (g *GeoBed) findAdminDivisionByName(countryCode, name string) (string, string) {
+	divisions := g.adminDivisions()
+	if countryCode != "" {
+		if g.countryRules(countryCode).NoAdminNames {
+			return "", ""
+		}
+		// Lowest code wins should a country reuse a name.
+		found := ""
+		for code, div := range divisions[countryCode] {
+			if strings.EqualFold(div.Name, name) && (found == "" || code < found) {
+				found = code
+			}
+		}
+		if found == "" {
+			return "", ""
+		}
+		return countryCode, found
+	}
+
+	var country, code string
+	for cc, countryDivisions := range divisions {
+		if g.countryRules(cc).NoAdminNames {
+			continue
+		}
+		for dc, div := range countryDivisions {
+			if !strings.EqualFold(div.Name, name) {
+				continue
+			}
+			if country != "" {
+				return "", ""
+			}
+			country, code = cc, dc
+		}
+	}
+	return country, code
+}
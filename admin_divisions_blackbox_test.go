@@ -7,7 +7,7 @@ import (
 
 // TestIsAdminDivisionValid tests that known valid admin divisions return true.
 func TestIsAdminDivisionValid(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -39,7 +39,7 @@ func TestIsAdminDivisionValid(t *testing.T) {
 
 // TestIsAdminDivisionInvalid tests that invalid admin divisions return false.
 func TestIsAdminDivisionInvalid(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -68,7 +68,7 @@ func TestIsAdminDivisionInvalid(t *testing.T) {
 // TestIsAdminDivisionCaseInsensitive tests that the function handles case insensitivity for division codes.
 // Note: Based on black-box testing, only division codes are case-insensitive, not country codes.
 func TestIsAdminDivisionCaseInsensitive(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -96,7 +96,7 @@ func TestIsAdminDivisionCaseInsensitive(t *testing.T) {
 
 // TestGetAdminDivisionCountryUnique tests unique admin division codes that map to one country.
 func TestGetAdminDivisionCountryUnique(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -123,7 +123,7 @@ func TestGetAdminDivisionCountryUnique(t *testing.T) {
 
 // TestGetAdminDivisionCountryAmbiguous tests ambiguous codes that exist in multiple countries.
 func TestGetAdminDivisionCountryAmbiguous(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -143,7 +143,7 @@ func TestGetAdminDivisionCountryAmbiguous(t *testing.T) {
 
 // TestGetAdminDivisionCountryEmpty tests empty input.
 func TestGetAdminDivisionCountryEmpty(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -156,7 +156,7 @@ func TestGetAdminDivisionCountryEmpty(t *testing.T) {
 
 // TestGetAdminDivisionNameValid tests retrieving names for valid divisions.
 func TestGetAdminDivisionNameValid(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -184,7 +184,7 @@ func TestGetAdminDivisionNameValid(t *testing.T) {
 
 // TestGetAdminDivisionNameInvalid tests that invalid divisions return empty string.
 func TestGetAdminDivisionNameInvalid(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -286,7 +286,7 @@ func TestLoadAdminDivisionsForDirCaching(t *testing.T) {
 
 // TestIsAdminDivisionConcurrent tests concurrent access to isAdminDivision.
 func TestIsAdminDivisionConcurrent(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -339,7 +339,7 @@ func TestIsAdminDivisionConcurrent(t *testing.T) {
 
 // TestGetAdminDivisionCountryConcurrent tests concurrent access to getAdminDivisionCountry.
 func TestGetAdminDivisionCountryConcurrent(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
@@ -388,7 +388,7 @@ func TestGetAdminDivisionCountryConcurrent(t *testing.T) {
 
 // TestGetAdminDivisionNameConcurrent tests concurrent access to getAdminDivisionName.
 func TestGetAdminDivisionNameConcurrent(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create GeoBed: %v", err)
 	}
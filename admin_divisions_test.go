@@ -1,7 +1,10 @@
 package geobed
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadAdminDivisions(t *testing.T) {
@@ -70,7 +73,7 @@ func TestLoadAdminDivisions(t *testing.T) {
 }
 
 func TestIsAdminDivision(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -102,7 +105,7 @@ func TestIsAdminDivision(t *testing.T) {
 }
 
 func TestGetAdminDivisionCountry(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -129,7 +132,7 @@ func TestGetAdminDivisionCountry(t *testing.T) {
 }
 
 func TestGetAdminDivisionName(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -156,7 +159,7 @@ func TestGetAdminDivisionName(t *testing.T) {
 }
 
 func TestInternationalAdminDivisions(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -190,7 +193,7 @@ func TestInternationalAdminDivisions(t *testing.T) {
 }
 
 func TestAmbiguousAdminDivisionCodes(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -218,3 +221,39 @@ func TestAmbiguousAdminDivisionCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminDivisions_ReplacedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "admin1CodesASCII.txt")
+	write := func(name string, mod time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("US.TX\t"+name+"\t"+name+"\t4736286\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records := []CityRecord{{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306}}
+
+	write("Texas", time.Now().Add(-time.Hour))
+	g1, err := NewGeobedFromRecords(records, nil, WithDataDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g1.getAdminDivisionName("US", "TX"); got != "Texas" {
+		t.Fatalf("getAdminDivisionName(US, TX) = %q, want Texas", got)
+	}
+
+	write("Tejas", time.Now())
+	g2, err := NewGeobedFromRecords(records, nil, WithDataDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g2.getAdminDivisionName("US", "TX"); got != "Tejas" {
+		t.Errorf("new instance after replacing the file: getAdminDivisionName(US, TX) = %q, want Tejas", got)
+	}
+	if got := g1.getAdminDivisionName("US", "TX"); got != "Texas" {
+		t.Errorf("existing instance after replacing the file: getAdminDivisionName(US, TX) = %q, want Texas", got)
+	}
+}
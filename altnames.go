@@ -0,0 +1,119 @@
+package geobed
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AltNamePolicy controls which alternate names are added to the name index
+// when a cache is generated from the raw data sets. Every alternate name is
+// still kept in GeobedCity.CityAlt and scored normally; the policy only
+// decides which of them can be used to find a city as an index key.
+//
+// The zero value indexes every alternate name, matching earlier releases.
+//
+// Geonames cities1000 lists alternate names without language tags, so a
+// language whitelist cannot be applied directly. Scripts is the closest
+// available filter: e.g. []*unicode.RangeTable{unicode.Latin} keeps
+// "Wien" and "Vienne" but drops "Вена" and "ウィーン".
+type AltNamePolicy struct {
+	MinLength    int                   // Minimum name length in runes (0 = no minimum)
+	Scripts      []*unicode.RangeTable // Letters must belong to one of these scripts (nil = any)
+	ExcludeCodes bool                  // Skip code-like names such as "LHR", "NYC", or "K1A"
+}
+
+// WithAltNamePolicy sets the alternate-name indexing policy used when the
+// cache is built from raw data (a cold start or RegenerateCache). It has no
+// effect when an existing cache is loaded, because the name index is read
+// from the cache as stored.
+func WithAltNamePolicy(p AltNamePolicy) Option {
+	return func(c *GeobedConfig) {
+		c.AltNames = p
+	}
+}
+
+// maxCodeLength is the longest alternate name ExcludeCodes treats as a code.
+// IATA (3), ICAO (4), and most postal-style abbreviations fit.
+const maxCodeLength = 4
+
+// allows reports whether an alternate name should be indexed.
+func (p AltNamePolicy) allows(alt string) bool {
+	if p.MinLength > 0 && utf8.RuneCountInString(alt) < p.MinLength {
+		return false
+	}
+	if p.ExcludeCodes && isCodeLike(alt) {
+		return false
+	}
+	if len(p.Scripts) > 0 {
+		for _, r := range alt {
+			if unicode.IsLetter(r) && !unicode.In(r, p.Scripts...) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isCodeLike reports whether s looks like an identifier code rather than a
+// place name: short and made only of uppercase ASCII letters and digits.
+func isCodeLike(s string) bool {
+	if len(s) == 0 || len(s) > maxCodeLength {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// maxAltNameLen caps the byte length of a single alternate name kept during
+// cache generation. 99.9% of Geonames alternate names are under 55 bytes;
+// the few longer ones are full official titles nobody queries by.
+const maxAltNameLen = 64
+
+// AltNameStats reports what cache generation did to alternate names.
+type AltNameStats struct {
+	Kept       int // Alternate names kept in CityAlt
+	Duplicates int // Dropped as case-insensitive duplicates within one city
+	TooLong    int // Dropped for exceeding maxAltNameLen bytes
+	BytesIn    int // Total CityAlt bytes before cleanup
+	BytesOut   int // Total CityAlt bytes after cleanup
+}
+
+// cleanAltNames trims a comma-separated alternate-name list, drops empty,
+// over-long, and case-insensitively repeated names (keeping the first
+// spelling), and updates st. Names equal to the primary name are kept
+// because scoring rewards an exact alternate-name match.
+func cleanAltNames(alt string, st *AltNameStats) string {
+	st.BytesIn += len(alt)
+	if alt == "" {
+		return ""
+	}
+	var kept, folded []string
+	for _, raw := range strings.Split(alt, ",") {
+		name := strings.TrimSpace(raw)
+		switch {
+		case name == "":
+			continue
+		case len(name) > maxAltNameLen:
+			st.TooLong++
+			continue
+		}
+		key := toLower(name)
+		if slices.Contains(folded, key) {
+			st.Duplicates++
+			continue
+		}
+		kept = append(kept, name)
+		folded = append(folded, key)
+	}
+	st.Kept += len(kept)
+	out := strings.Join(kept, ",")
+	st.BytesOut += len(out)
+	return out
+}
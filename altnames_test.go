@@ -0,0 +1,131 @@
+package geobed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestAltNamePolicy_Allows(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy AltNamePolicy
+		alt    string
+		want   bool
+	}{
+		{"zero value allows codes", AltNamePolicy{}, "LHR", true},
+		{"zero value allows short", AltNamePolicy{}, "X", true},
+		{"min length rejects", AltNamePolicy{MinLength: 3}, "NY", false},
+		{"min length counts runes", AltNamePolicy{MinLength: 3}, "東京都", true},
+		{"exclude IATA code", AltNamePolicy{ExcludeCodes: true}, "LHR", false},
+		{"exclude ICAO code", AltNamePolicy{ExcludeCodes: true}, "EGLL", false},
+		{"exclude postal-style code", AltNamePolicy{ExcludeCodes: true}, "K1A", false},
+		{"keep short mixed-case name", AltNamePolicy{ExcludeCodes: true}, "Rome", true},
+		{"keep long uppercase name", AltNamePolicy{ExcludeCodes: true}, "PARIS", true},
+		{"latin script keeps accents", AltNamePolicy{Scripts: []*unicode.RangeTable{unicode.Latin}}, "Zürich", true},
+		{"latin script rejects cyrillic", AltNamePolicy{Scripts: []*unicode.RangeTable{unicode.Latin}}, "Вена", false},
+		{"script ignores punctuation", AltNamePolicy{Scripts: []*unicode.RangeTable{unicode.Latin}}, "Saint-Denis 2", true},
+		{"multiple scripts", AltNamePolicy{Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic}}, "Вена", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.allows(tt.alt); got != tt.want {
+				t.Errorf("%+v.allows(%q) = %v, want %v", tt.policy, tt.alt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAltNamePolicy_ShrinksIndex(t *testing.T) {
+	load := func(p AltNamePolicy) *GeoBed {
+		t.Helper()
+		cfg := defaultConfig()
+		cfg.AltNames = p
+		g := &GeoBed{config: cfg}
+		if err := g.loadDataSets(); err != nil {
+			t.Skipf("raw data sets unavailable: %v", err)
+		}
+		return g
+	}
+
+	all := load(AltNamePolicy{})
+	filtered := load(AltNamePolicy{MinLength: 3, ExcludeCodes: true, Scripts: []*unicode.RangeTable{unicode.Latin}})
+
+	t.Logf("index keys: all=%d filtered=%d", len(all.nameIndex), len(filtered.nameIndex))
+	if len(filtered.nameIndex) >= len(all.nameIndex) {
+		t.Errorf("filtered index has %d keys, want fewer than %d", len(filtered.nameIndex), len(all.nameIndex))
+	}
+	for key := range filtered.nameIndex {
+		for _, r := range key {
+			if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+				// Primary names are always indexed; only alt names are filtered.
+				found := false
+				for _, i := range filtered.nameIndex[key] {
+					if toLower(filtered.Cities[i].City) == key {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("non-Latin alt name %q indexed despite Scripts policy", key)
+				}
+				break
+			}
+		}
+	}
+	if len(filtered.Cities) != len(all.Cities) {
+		t.Errorf("policy changed city count: %d vs %d", len(filtered.Cities), len(all.Cities))
+	}
+}
+
+func TestCleanAltNames(t *testing.T) {
+	long := strings.Repeat("x", maxAltNameLen+1)
+	in := " Wien, Vienna,,VIENNA,Wien," + long + ",Austin"
+	var st AltNameStats
+	got := cleanAltNames(in, &st)
+	if want := "Wien,Vienna,Austin"; got != want {
+		t.Errorf("cleanAltNames = %q, want %q", got, want)
+	}
+	want := AltNameStats{Kept: 3, Duplicates: 2, TooLong: 1, BytesIn: len(in), BytesOut: 18}
+	if st != want {
+		t.Errorf("stats = %+v, want %+v", st, want)
+	}
+	if got := cleanAltNames("", &st); got != "" {
+		t.Errorf("cleanAltNames(\"\") = %q", got)
+	}
+}
+
+func TestRegenerateCacheWithStats(t *testing.T) {
+	dataDir, err := filepath.Abs("geobed-data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := t.TempDir()
+	st, err := RegenerateCacheWithStats(WithDataDir(dataDir), WithCacheDir(cacheDir))
+	if err != nil {
+		t.Skipf("raw data sets unavailable: %v", err)
+	}
+	t.Logf("%+v", st)
+
+	if st.Cities < minCityCount || st.IndexKeys == 0 || st.IndexPostings < st.Cities {
+		t.Errorf("implausible build stats: %+v", st)
+	}
+	if st.AltNames.Duplicates == 0 || st.RepeatedKeys == 0 {
+		t.Errorf("expected Geonames alt names to contain duplicates: %+v", st)
+	}
+	if st.AltNames.BytesOut >= st.AltNames.BytesIn {
+		t.Errorf("alt-name bytes did not shrink: %d -> %d", st.AltNames.BytesIn, st.AltNames.BytesOut)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "g.c.dmp")); err != nil {
+		t.Errorf("cache not written to CacheDir: %v", err)
+	}
+	if st.Cells == 0 || st.MaxCellCities == 0 || st.CellOccupancy() <= 0 || st.AvgPostings() < 1 {
+		t.Errorf("implausible index stats: %+v", st)
+	}
+	if st.CacheDir != cacheDir || len(st.Files) != len(cacheFileNames) {
+		t.Errorf("cache files %v in %q; want %v in %q", st.Files, st.CacheDir, cacheFileNames, cacheDir)
+	}
+}
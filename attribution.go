@@ -0,0 +1,41 @@
+package geobed
+
+// Attribution is the credit a data source's license requires wherever its
+// data is shown.
+type Attribution struct {
+	Name    string `json:"name"`    // Data source, e.g. "GeoNames"
+	Text    string `json:"text"`    // Credit line to display as is
+	License string `json:"license"` // SPDX identifier, or a LicenseRef- for licenses SPDX does not list
+	URL     string `json:"url"`     // License terms
+}
+
+var (
+	geonamesAttribution = Attribution{
+		Name:    "GeoNames",
+		Text:    "Contains data from GeoNames (https://www.geonames.org), licensed under CC BY 4.0.",
+		License: "CC-BY-4.0",
+		URL:     "https://creativecommons.org/licenses/by/4.0/",
+	}
+	maxMindAttribution = Attribution{
+		Name:    "MaxMind",
+		Text:    "This product includes data created by MaxMind, available from https://www.maxmind.com.",
+		License: "LicenseRef-MaxMind-WorldCities",
+		URL:     "https://www.maxmind.com",
+	}
+)
+
+// Attribution returns the credits g's data requires, so applications can
+// render compliant attribution without hardcoding it. GeoNames is always
+// listed, as country metadata and admin division names come from it even
+// for instances built from records; MaxMind only when MaxMind cities are
+// loaded. Supplemental places and records are the caller's own data.
+func (g *GeoBed) Attribution() []Attribution {
+	credits := []Attribution{geonamesAttribution}
+	for _, c := range g.Cities {
+		if c.source == SourceMaxMind {
+			credits = append(credits, maxMindAttribution)
+			break
+		}
+	}
+	return credits
+}
@@ -0,0 +1,18 @@
+package geobed
+
+import "testing"
+
+func TestAttribution(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Attribution(); len(got) != 1 || got[0].License != "CC-BY-4.0" || got[0].Text == "" {
+		t.Errorf("Attribution() = %+v, want the GeoNames credit only", got)
+	}
+
+	g.Cities = append(g.Cities, GeobedCity{City: "Springfield", source: SourceMaxMind})
+	if got := g.Attribution(); len(got) != 2 || got[1].Name != "MaxMind" {
+		t.Errorf("Attribution() with MaxMind cities = %+v, want GeoNames and MaxMind", got)
+	}
+}
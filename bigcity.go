@@ -0,0 +1,201 @@
+package geobed
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// bigCityMinPop is the population a city needs to answer
+// ApproxReverseGeocode.
+const bigCityMinPop = 100_000
+
+// bigCityLevel is the S2 level of the big-city grid, the coarse cell level.
+// Its cells are 50-100km across, so the grid answers for a cell centre
+// rather than the point, within roughly 20-40km.
+const bigCityLevel = coarseCellLevel
+
+// bigCityCells is the number of cells at bigCityLevel over all six faces.
+const bigCityCells = 6 << (2 * bigCityLevel)
+
+// bigCityShift turns a cell ID into its grid slot: the face bits and the
+// first 2*bigCityLevel position bits of any cell ID identify its ancestor at
+// bigCityLevel.
+const bigCityShift = s2.PosBits - 2*bigCityLevel
+
+// bigCityFileName is the optional cache file written when WithBigCityGrid
+// is set. It is left out of cacheFileNames because caches without it are
+// complete; the grid is then built at load time instead.
+const bigCityFileName = "bigCity.dmp"
+
+// WithBigCityGrid precomputes, for every level-7 S2 cell, the nearest city
+// of at least 100,000 inhabitants, so ApproxReverseGeocode is a single
+// array lookup from the first call. The grid is written with the cache when
+// one is generated and read back from it on later starts; without a stored
+// grid it is built at load time. Without the option ApproxReverseGeocode
+// builds the grid on first use.
+func WithBigCityGrid() Option {
+	return func(c *GeobedConfig) {
+		c.BigCityGrid = true
+	}
+}
+
+// bigCityGridFile is the on-disk form of the big-city grid. Cities records
+// how many cities the cache held, so a grid stored for other data is
+// detected and rebuilt instead of pointing at the wrong cities.
+type bigCityGridFile struct {
+	Cities  int
+	Nearest []int32 // City index per grid slot, -1 for none
+}
+
+// ApproxReverseGeocode returns the city of at least 100,000 inhabitants
+// nearest to the centre of the level-7 S2 cell holding lat/lng. It trades
+// accuracy for speed: the answer is the same for every point of a cell, and
+// the grid is a raster approximation, so a city slightly farther than the
+// true nearest can win near the boundary between two cities. Use it for
+// workloads that accept 20-40km resolution.
+//
+// Points more than 100 km from that city and invalid coordinates return
+// the zero city. ReverseGeocode with MinPopulation answers for the point
+// itself but only searches the level-10 S2 cells around it, some 10-30km,
+// so it returns nothing for many points ApproxReverseGeocode places near a
+// big city. The grid comes from WithBigCityGrid or is built on the first
+// call.
+func (g *GeoBed) ApproxReverseGeocode(lat, lng float64) GeobedCity {
+	if !validCoordinates(lat, lng) {
+		return GeobedCity{}
+	}
+	g.bigCityOnce.Do(func() {
+		if g.bigCity == nil {
+			g.bigCity = g.buildBigCityGrid()
+		}
+	})
+
+	ll := s2.LatLngFromDegrees(lat, lng)
+	i := g.bigCity[uint64(s2.CellIDFromLatLng(ll))>>bigCityShift]
+	if i < 0 || distanceKm(ll, cityLatLng(g.Cities[i])) > maxReverseGeocodeKm {
+		return GeobedCity{}
+	}
+	return g.Cities[i]
+}
+
+// buildBigCityGrid assigns every grid cell the reverse-geocodable city of
+// at least bigCityMinPop nearest to the cell centre. Cells holding such a
+// city are seeded with the nearest of them, and assignments then spread to
+// neighbouring cells for as long as they bring a cell a nearer city, which
+// makes the grid a raster Voronoi diagram of the big cities.
+func (g *GeoBed) buildBigCityGrid() []int32 {
+	nearest := make([]int32, bigCityCells)
+	dist := make([]s1.ChordAngle, bigCityCells)
+	for i := range nearest {
+		nearest[i] = -1
+	}
+	// better reports whether city i is nearer to slot's centre than its
+	// current city, with ties going to the lower city index.
+	better := func(slot int, i int32, d s1.ChordAngle) bool {
+		cur := nearest[slot]
+		return cur < 0 || d < dist[slot] || (d == dist[slot] && i < cur)
+	}
+
+	var queue []int
+	for i, c := range g.Cities {
+		if c.Population < bigCityMinPop || (c.source == SourceCustom && !g.config.ReversePlaces) {
+			continue
+		}
+		ll := cityLatLng(c)
+		p := s2.PointFromLatLng(ll)
+		slot := int(uint64(s2.CellIDFromLatLng(ll)) >> bigCityShift)
+		d := s2.ChordAngleBetweenPoints(bigCitySlotCell(slot).Point(), p)
+		if better(slot, int32(i), d) {
+			if nearest[slot] < 0 {
+				queue = append(queue, slot)
+			}
+			nearest[slot], dist[slot] = int32(i), d
+		}
+	}
+
+	// A cell is queued again whenever it gets a nearer city, so the
+	// spread settles once no cell improves. Big cities are sparse enough
+	// that few cells are revisited.
+	for len(queue) > 0 {
+		slot := queue[0]
+		queue = queue[1:]
+		i := nearest[slot]
+		c := g.Cities[i]
+		p := s2.PointFromLatLng(cityLatLng(c))
+		for _, n := range bigCitySlotCell(slot).AllNeighbors(bigCityLevel) {
+			ns := int(uint64(n) >> bigCityShift)
+			d := s2.ChordAngleBetweenPoints(n.Point(), p)
+			if nearest[ns] != i && better(ns, i, d) {
+				nearest[ns], dist[ns] = i, d
+				queue = append(queue, ns)
+			}
+		}
+	}
+	return nearest
+}
+
+// bigCitySlotCell returns the cell of a grid slot.
+func bigCitySlotCell(slot int) s2.CellID {
+	return s2.CellID(uint64(slot)<<bigCityShift | 1<<(bigCityShift-1))
+}
+
+// storeBigCityGrid writes the big-city grid next to the other cache files
+// when WithBigCityGrid is set, and otherwise removes a grid left by an
+// earlier build, which no longer matches the cities just written.
+func (g *GeoBed) storeBigCityGrid(cacheDir string) error {
+	path := filepath.Join(cacheDir, bigCityFileName)
+	if !g.config.BigCityGrid {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if g.bigCity == nil {
+		g.bigCity = g.buildBigCityGrid()
+	}
+
+	b := new(bytes.Buffer)
+	if err := gob.NewEncoder(b).Encode(bigCityGridFile{Cities: len(g.Cities), Nearest: g.bigCity}); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b.Bytes(), 0644)
+}
+
+// loadBigCityGrid reads the big-city grid stored in dir, or in the
+// embedded cache, for a cache of the given number of cities. A missing grid
+// reports ErrCacheMissing.
+func loadBigCityGrid(dir string, cities int) ([]int32, error) {
+	fh, cleanup, err := openOptionallyBzippedFile(dir, bigCityFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return decodeBigCityGrid(fh, cities)
+}
+
+// decodeBigCityGrid decodes a bigCity.dmp stream. A grid for another number
+// of cities or with city indices out of range reports ErrCacheCorrupt.
+func decodeBigCityGrid(r io.Reader, cities int) ([]int32, error) {
+	var f bigCityGridFile
+	if err := gob.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("%w: decoding %s: %w", ErrCacheCorrupt, bigCityFileName, err)
+	}
+	if f.Cities != cities || len(f.Nearest) != bigCityCells {
+		return nil, fmt.Errorf("%w: %s was built for other data", ErrCacheCorrupt, bigCityFileName)
+	}
+	for _, i := range f.Nearest {
+		if i < -1 || int(i) >= cities {
+			return nil, fmt.Errorf("%w: %s city index %d out of range", ErrCacheCorrupt, bigCityFileName, i)
+		}
+	}
+	return f.Nearest, nil
+}
@@ -0,0 +1,123 @@
+package geobed
+
+import (
+	"cmp"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+func bigCityFixture(t *testing.T, opts ...Option) *GeoBed {
+	t.Helper()
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Lyon", Country: "FR", Region: "84", Latitude: 45.74846, Longitude: 4.84671, Population: 522969},
+		{City: "Saint-Étienne", Country: "FR", Region: "84", Latitude: 45.43389, Longitude: 4.39, Population: 172565},
+		{City: "Vienne", Country: "FR", Region: "84", Latitude: 45.52569, Longitude: 4.87484, Population: 29306},
+		{City: "Grenoble", Country: "FR", Region: "84", Latitude: 45.17155, Longitude: 5.72239, Population: 158552},
+		{City: "Geneva", Country: "CH", Region: "GE", Latitude: 46.20222, Longitude: 6.14569, Population: 183981},
+		{City: "Marseille", Country: "FR", Region: "93", Latitude: 43.29695, Longitude: 5.38107, Population: 870731},
+	}, nil, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestApproxReverseGeocode(t *testing.T) {
+	g := bigCityFixture(t)
+
+	tests := []struct {
+		name     string
+		lat, lng float64
+		want     string
+	}{
+		{"in the city", 45.75, 4.85, "Lyon"},
+		{"small town answers with a big city", 45.52569, 4.87484, "Lyon"},
+		{"between cities", 45.2, 5.7, "Grenoble"},
+		{"across a border", 46.2, 6.15, "Geneva"},
+		{"89km from Lyon", 46.55, 4.85, "Lyon"},
+		{"beyond 100km of the cell's city", 46.85, 4.85, ""},
+		{"far inland from any city", 20, 80, ""},
+		{"at sea", 40, -30, ""},
+		{"invalid", 91, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := g.ApproxReverseGeocode(tt.lat, tt.lng).City; got != tt.want {
+			t.Errorf("%s: ApproxReverseGeocode(%v, %v) = %q; want %q", tt.name, tt.lat, tt.lng, got, tt.want)
+		}
+	}
+}
+
+func TestBuildBigCityGrid(t *testing.T) {
+	g := bigCityFixture(t)
+	grid := g.buildBigCityGrid()
+
+	var big []int32
+	for i, c := range g.Cities {
+		if c.Population >= bigCityMinPop {
+			big = append(big, int32(i))
+		}
+	}
+	dist := func(slot int, i int32) float64 {
+		return bigCitySlotCell(slot).LatLng().Distance(cityLatLng(g.Cities[i])).Radians()
+	}
+	// The spread between neighbouring cells can miss the exact nearest city
+	// near the boundary between two cities, but never by more than a cell.
+	slack := s2.MaxDiagMetric.Value(bigCityLevel)
+	for slot, got := range grid {
+		want := slices.MinFunc(big, func(a, b int32) int {
+			return cmp.Compare(dist(slot, a), dist(slot, b))
+		})
+		if got < 0 || dist(slot, got) > dist(slot, want)+slack {
+			t.Fatalf("slot %d (%v): city %d; want %d", slot, bigCitySlotCell(slot), got, want)
+		}
+	}
+}
+
+func TestBigCityGrid_StoreAndLoad(t *testing.T) {
+	g := bigCityFixture(t, WithBigCityGrid())
+	if g.bigCity == nil {
+		t.Fatal("WithBigCityGrid did not build the grid at load")
+	}
+
+	dir := t.TempDir()
+	if err := g.storeBigCityGrid(dir); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadBigCityGrid(dir, len(g.Cities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, g.bigCity) {
+		t.Error("loaded grid differs from the stored one")
+	}
+
+	if _, err := loadBigCityGrid(dir, len(g.Cities)+1); !errors.Is(err, ErrCacheCorrupt) {
+		t.Errorf("grid for other data: err = %v; want ErrCacheCorrupt", err)
+	}
+
+	// Without the option a stored grid would be stale, so it is removed.
+	g.config.BigCityGrid = false
+	if err := g.storeBigCityGrid(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, bigCityFileName)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stale grid kept: %v", err)
+	}
+}
+
+func BenchmarkApproxReverseGeocode(b *testing.B) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		b.Fatal(err)
+	}
+	g.ApproxReverseGeocode(0, 0)
+	b.ResetTimer()
+	for b.Loop() {
+		g.ApproxReverseGeocode(48.8566, 2.3522)
+	}
+}
@@ -0,0 +1,163 @@
+package geobed
+
+import "slices"
+
+// boundsMarginDeg widens region bounding boxes when checking a result
+// against the query's hints, so cities on a border are never flagged.
+const boundsMarginDeg = 0.5
+
+// hintScoreMargin is how many points an in-region candidate may trail the
+// selected one by and still replace it, the bonus of an exact-name match:
+// enough for a neighbouring-country candidate to beat a same-name city
+// across the world ("Geneva, France"), not enough to overturn a lead built
+// on several name matches. A candidate ahead by more keeps its place and is
+// flagged.
+const hintScoreMargin = 7
+
+// bbox is a latitude/longitude bounding box in degrees. A box crossing the
+// antimeridian has minLng > maxLng and spans eastwards from minLng to maxLng.
+type bbox struct {
+	minLat, maxLat, minLng, maxLng float32
+}
+
+// extend grows b to include (lat, lng).
+func (b *bbox) extend(lat, lng float32) {
+	b.minLat = min(b.minLat, lat)
+	b.maxLat = max(b.maxLat, lat)
+	b.minLng = min(b.minLng, lng)
+	b.maxLng = max(b.maxLng, lng)
+}
+
+// contains reports whether (lat, lng) lies within b widened by margin degrees.
+func (b bbox) contains(lat, lng, margin float32) bool {
+	if lat < b.minLat-margin || lat > b.maxLat+margin {
+		return false
+	}
+	width := b.maxLng - b.minLng
+	if width < 0 {
+		width += 360
+	}
+	if width+2*margin >= 360 {
+		return true
+	}
+	from, to := wrapLng(b.minLng-margin), wrapLng(b.maxLng+margin)
+	if from <= to {
+		return lng >= from && lng <= to
+	}
+	return lng >= from || lng <= to
+}
+
+// wrapLng maps a longitude into [-180, 180).
+func wrapLng(lng float32) float32 {
+	for lng >= 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
+
+// narrowLng sets b's longitude range to the shortest arc covering lngs,
+// crossing the antimeridian when the widest gap between them does not.
+func (b *bbox) narrowLng(lngs []float32) {
+	slices.Sort(lngs)
+	n := len(lngs)
+	gap, east := lngs[0]+360-lngs[n-1], 0
+	for i := 1; i < n; i++ {
+		if d := lngs[i] - lngs[i-1]; d > gap {
+			gap, east = d, i
+		}
+	}
+	b.minLng = lngs[east]
+	b.maxLng = lngs[(east+n-1)%n]
+}
+
+// boundsKey returns the key of a country ("FR") or region ("US.TX") in the
+// bounding box table.
+func boundsKey(country, region string) string {
+	if region == "" {
+		return country
+	}
+	return country + "." + region
+}
+
+// hintBounds returns the bounding box of the region (or, without a region,
+// the country) named by a query's hints. The boxes span every loaded city of
+// the region, taking the shorter way around for regions such as Fiji that
+// straddle the antimeridian, and are computed on first use.
+func (g *GeoBed) hintBounds(country, region string) (bbox, bool) {
+	if country == "" {
+		return bbox{}, false
+	}
+	g.boundsOnce.Do(func() {
+		g.bounds = make(map[string]bbox)
+		lngs := make(map[string][]float32)
+		for _, c := range g.Cities {
+			co := c.Country()
+			if co == "" {
+				continue
+			}
+			keys := []string{co}
+			if r := c.Region(); r != "" {
+				keys = append(keys, boundsKey(co, r))
+			}
+			for _, k := range keys {
+				b, ok := g.bounds[k]
+				if !ok {
+					b = bbox{c.Latitude, c.Latitude, c.Longitude, c.Longitude}
+				}
+				b.extend(c.Latitude, c.Longitude)
+				g.bounds[k] = b
+				lngs[k] = append(lngs[k], c.Longitude)
+			}
+		}
+		for k, b := range g.bounds {
+			b.narrowLng(lngs[k])
+			g.bounds[k] = b
+		}
+	})
+	b, ok := g.bounds[boundsKey(country, region)]
+	return b, ok
+}
+
+// checkHints is a sanity check of the selected candidate against the
+// country and state found in the query. Scoring bonuses can add up for a
+// record far away from the named region (an alternate-name match for
+// "Geneva, Switzerland" lifting Geneva, NY above Genève); such a candidate
+// is demoted in favour of the best-scoring one inside the region's bounding
+// box, provided that one trails it by at most hintScoreMargin. It returns
+// the index to use and whether that city still lies outside the hinted
+// region, which happens when no candidate inside it is close enough.
+func (g *GeoBed) checkHints(nCo, nSt string, scores map[int]int, selected int) (int, bool) {
+	if selected < 0 {
+		return selected, false
+	}
+	b, ok := g.hintBounds(nCo, nSt)
+	if !ok {
+		return selected, false
+	}
+	inside := func(k int) bool {
+		c := g.Cities[k]
+		return b.contains(c.Latitude, c.Longitude, boundsMarginDeg)
+	}
+	if inside(selected) {
+		return selected, false
+	}
+
+	best := -1
+	for k, v := range scores {
+		if v <= 0 || !inside(k) {
+			continue
+		}
+		if best < 0 || v > scores[best] ||
+			(v == scores[best] && (g.Cities[k].Population > g.Cities[best].Population ||
+				(g.Cities[k].Population == g.Cities[best].Population && k < best))) {
+			best = k
+		}
+	}
+	if best < 0 || scores[selected]-scores[best] > hintScoreMargin {
+		return selected, true
+	}
+	return best, false
+}
@@ -0,0 +1,125 @@
+package geobed
+
+import "testing"
+
+func TestBBoxContains(t *testing.T) {
+	b := bbox{10, 10, 20, 20}
+	b.extend(12, 18)
+	if b != (bbox{10, 12, 18, 20}) {
+		t.Fatalf("extend = %+v, want {10 12 18 20}", b)
+	}
+
+	tests := []struct {
+		lat, lng, margin float32
+		want             bool
+	}{
+		{11, 19, 0, true},
+		{10, 18, 0, true}, // On the edge
+		{9.8, 19, 0, false},
+		{9.8, 19, 0.5, true},
+		{11, 21, 0.5, false},
+	}
+	for _, tt := range tests {
+		if got := b.contains(tt.lat, tt.lng, tt.margin); got != tt.want {
+			t.Errorf("contains(%v, %v, %v) = %v, want %v", tt.lat, tt.lng, tt.margin, got, tt.want)
+		}
+	}
+}
+
+func TestBBoxAntimeridian(t *testing.T) {
+	var b bbox
+	b.narrowLng([]float32{178.4, 177.1, -179.9, -178.8})
+	if b.minLng != 177.1 || b.maxLng != -178.8 {
+		t.Fatalf("narrowLng = [%v, %v], want [177.1, -178.8]", b.minLng, b.maxLng)
+	}
+	b.minLat, b.maxLat = -19, -16
+	tests := []struct {
+		lng  float32
+		want bool
+	}{
+		{178.4, true},
+		{-179.5, true},
+		{-178.5, true}, // Within the margin
+		{0, false},
+		{170, false},
+	}
+	for _, tt := range tests {
+		if got := b.contains(-17, tt.lng, boundsMarginDeg); got != tt.want {
+			t.Errorf("contains(-17, %v) = %v, want %v", tt.lng, got, tt.want)
+		}
+	}
+
+	b.narrowLng([]float32{10, 20, 30})
+	if b.minLng != 10 || b.maxLng != 30 {
+		t.Errorf("narrowLng without crossing = [%v, %v], want [10, 30]", b.minLng, b.maxLng)
+	}
+	if !(bbox{0, 1, 179.8, 179.9}).contains(0.5, -179.9, boundsMarginDeg) {
+		t.Error("margin does not wrap across the antimeridian")
+	}
+}
+
+func TestCheckHints_ScoreMargin(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Genève", CityAlt: "Geneva", Country: "CH", Latitude: 46.20222, Longitude: 6.14569, Population: 183981},
+		{City: "Zürich", Country: "CH", Latitude: 47.36667, Longitude: 8.55, Population: 341730},
+		{City: "Geneva", CityAlt: "Geneva,Geneva", Country: "US", Region: "NY", Latitude: 42.8689, Longitude: -76.97757, Population: 13261},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two alternate-name matches put Geneva, NY too far ahead of Genève
+	// to demote it; it is kept and flagged.
+	r := g.GeocodeDetailed("Geneva, Switzerland")
+	if r.City.Country() != "US" || !r.HintMismatch {
+		t.Errorf("GeocodeDetailed = %s, %s, mismatch=%v; want Geneva, US, mismatch=true", r.City.City, r.City.Country(), r.HintMismatch)
+	}
+}
+
+func TestCheckHints(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query        string
+		wantCity     string
+		wantCountry  string
+		wantMismatch bool
+	}{
+		// Alternate-name bonuses lift Geneva, NY above Genève; the hint demotes it.
+		{"Geneva, Switzerland", "Genève", "CH", false},
+		{"Paris, Texas", "Paris", "US", false},
+		{"Austin, TX", "Austin", "US", false},
+		// No Springfield in Mexico: the US city is returned but flagged.
+		{"Springfield, Mexico", "Springfield", "US", true},
+		// No hints, nothing to check.
+		{"Tokyo", "Tokyo", "JP", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := g.GeocodeDetailed(tt.query)
+			if r.City.City != tt.wantCity || r.City.Country() != tt.wantCountry || r.HintMismatch != tt.wantMismatch {
+				t.Errorf("GeocodeDetailed(%q) = %s, %s, mismatch=%v; want %s, %s, mismatch=%v",
+					tt.query, r.City.City, r.City.Country(), r.HintMismatch,
+					tt.wantCity, tt.wantCountry, tt.wantMismatch)
+			}
+		})
+	}
+
+	b, ok := g.hintBounds("US", "TX")
+	if !ok {
+		t.Fatal("hintBounds(US, TX) not found")
+	}
+	if !b.contains(30.27, -97.74, 0) || b.contains(40.71, -74.01, boundsMarginDeg) {
+		t.Errorf("hintBounds(US, TX) = %+v, want Austin inside and New York outside", b)
+	}
+	for _, co := range []string{"FJ", "RU", "NZ", "KI"} {
+		if b, _ := g.hintBounds(co, ""); b.contains(b.minLat, 0, boundsMarginDeg) {
+			t.Errorf("hintBounds(%s) = %+v spans the prime meridian, want the arc across the antimeridian", co, b)
+		}
+	}
+	if _, ok := g.hintBounds("", ""); ok {
+		t.Error("hintBounds with no country should not be found")
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func TestBugfixRegressions(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -308,7 +308,7 @@ func TestBugfixRegressions(t *testing.T) {
 
 // TestCodeReviewBugFixes covers the 13 bugs and edge cases identified during code review.
 func TestCodeReviewBugFixes(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
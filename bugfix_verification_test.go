@@ -1,6 +1,7 @@
 package geobed
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -65,7 +66,7 @@ func TestFix_DataRaceDedupVarsAreLocal(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestFix_PartialCacheResetPreventsDataDuplication(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -119,7 +120,7 @@ func TestFix_SortedUsStateCodes(t *testing.T) {
 }
 
 func TestFix_StateCodeDeterminism(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -180,7 +181,7 @@ func TestFix_AbbrevRegexReturnsAllMatches(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestFix_ExactMatchZeroPopulationFallback(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -220,7 +221,7 @@ func TestFix_ExactMatchZeroPopulationFallback(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestFix_FuzzyDistanceCapped(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -244,7 +245,7 @@ func TestFix_FuzzyDistanceCapped(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestFix_ReverseGeocodeNaNInfValidation(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -288,7 +289,7 @@ func TestFix_ReverseGeocodeNaNInfValidation(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestFix_ReverseGeocodeSortDeterminism(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -324,7 +325,7 @@ func TestFix_ReverseGeocodeSortDeterminism(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestFix_ScoringTieBreakDeterminism(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -364,8 +365,8 @@ func TestFix_StringInternerOffByOne(t *testing.T) {
 
 	// Index 0 is reserved for "". Add 255 unique non-empty strings.
 	for i := 0; i < 255; i++ {
-		idx := si.intern(fmt.Sprintf("s%d", i))
-		if idx == 0 {
+		idx, err := si.intern(fmt.Sprintf("s%d", i))
+		if err != nil || idx == 0 {
 			t.Fatalf("expected non-zero index for s%d", i)
 		}
 	}
@@ -375,33 +376,65 @@ func TestFix_StringInternerOffByOne(t *testing.T) {
 		t.Errorf("count = %d, want 256 (255 strings + empty)", si.count())
 	}
 
-	// Now the NEXT intern should panic (exceeds uint8 capacity)
-	defer func() {
-		r := recover()
-		if r == nil {
-			t.Error("expected panic on overflow, but didn't panic")
-			return
-		}
-		msg := fmt.Sprint(r)
-		if !strings.Contains(msg, "capacity exceeded") {
-			t.Errorf("unexpected panic message: %v", r)
+	// Now the NEXT intern should fail (exceeds uint8 capacity); debug
+	// builds panic instead.
+	if debugBuild {
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Error("expected panic on overflow, but didn't panic")
+				return
+			}
+			msg := fmt.Sprint(r)
+			if !strings.Contains(msg, "capacity exceeded") {
+				t.Errorf("unexpected panic message: %v", r)
+			}
+		}()
+	}
+	_, err := si.intern("overflow_trigger")
+	if !errors.Is(err, ErrTooManyCodes) || !strings.Contains(err.Error(), "capacity exceeded") {
+		t.Errorf("overflow error = %v, want ErrTooManyCodes", err)
+	}
+	if si.count() != 256 {
+		t.Errorf("count after overflow = %d, want 256", si.count())
+	}
+}
+
+func TestStringInternerOverflow_LoaderError(t *testing.T) {
+	if debugBuild {
+		t.Skip("debug builds panic on overflow")
+	}
+	// A full region table stands in for a dataset with too many regions.
+	full := newStringInterner[uint16](1 << 16)
+	for i := 1; i < 1<<16; i++ {
+		if _, err := full.intern(fmt.Sprintf("r%d", i)); err != nil {
+			t.Fatal(err)
 		}
-	}()
-	si.intern("overflow_trigger")
+	}
+	saved := regionInterner
+	regionInterner = full
+	t.Cleanup(func() { regionInterner = saved })
+
+	_, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Nowhere", Country: "US", Region: "new region", Latitude: 1, Longitude: 1},
+	}, nil)
+	if !errors.Is(err, ErrTooManyCodes) {
+		t.Errorf("NewGeobedFromRecords err = %v, want ErrTooManyCodes", err)
+	}
 }
 
 func TestFix_StringInternerBasicOperations(t *testing.T) {
 	si := newStringInterner[uint16](10)
 
 	// Index 0 is reserved for empty string
-	idx := si.intern("")
+	idx, _ := si.intern("")
 	if idx != 0 {
 		t.Errorf("empty string index = %d, want 0", idx)
 	}
 
 	// New strings get sequential indices
-	idx1 := si.intern("hello")
-	idx2 := si.intern("world")
+	idx1, _ := si.intern("hello")
+	idx2, _ := si.intern("world")
 	if idx1 == 0 || idx2 == 0 {
 		t.Error("non-empty strings should not get index 0")
 	}
@@ -410,7 +443,7 @@ func TestFix_StringInternerBasicOperations(t *testing.T) {
 	}
 
 	// Same string returns same index (idempotent)
-	idx1b := si.intern("hello")
+	idx1b, _ := si.intern("hello")
 	if idx1b != idx1 {
 		t.Errorf("re-interning 'hello' got %d, want %d", idx1b, idx1)
 	}
@@ -448,7 +481,11 @@ func TestFix_StringInternerConcurrency(t *testing.T) {
 			for i := 0; i < stringsPerGoroutine; i++ {
 				// Some strings overlap between goroutines, some don't
 				s := fmt.Sprintf("str_%d", i)
-				idx := si.intern(s)
+				idx, err := si.intern(s)
+				if err != nil {
+					t.Error(err)
+					return
+				}
 				got := si.get(idx)
 				if got != s {
 					t.Errorf("goroutine %d: intern+get(%q) = %q", id, s, got)
@@ -0,0 +1,34 @@
+package geobed
+
+// Capabilities reports which optional data an instance has loaded, so
+// callers can feature-detect at run time instead of reading empty results
+// from lookups whose data is missing.
+type Capabilities struct {
+	AdminDivisions bool `json:"admin_divisions"` // Admin1 codes and names: queries like "London, Ontario" resolve
+	AltNames       bool `json:"alt_names"`       // Alternate city names (GeobedCity.CityAlt) are available
+	Countries      bool `json:"countries"`       // Country metadata from countryInfo.txt
+	EEZ            bool `json:"eez"`             // Exclusive Economic Zones for points at sea (WithEEZFile)
+	PostalCodes    bool `json:"postal_codes"`    // Postal code lookup; geobed loads no postal data, so always false
+	Timezones      bool `json:"timezones"`       // City time zones; not kept when parsing Geonames, so always false
+	ReadOnlyCache  bool `json:"read_only_cache"` // CacheDir cannot be written, so data parsed from raw files is not cached
+}
+
+// Capabilities reports which optional data g has loaded. Admin divisions
+// come from admin1CodesASCII.txt in the data directory, which instances
+// loaded from the embedded cache or from records may not have.
+// ReadOnlyCache probes CacheDir on each call.
+func (g *GeoBed) Capabilities() Capabilities {
+	c := Capabilities{
+		AdminDivisions: len(g.adminDivisions()) > 0,
+		Countries:      len(g.Countries) > 0,
+		EEZ:            len(g.eez) > 0,
+		ReadOnlyCache:  g.config != nil && cacheDirReadOnly(g.config.CacheDir),
+	}
+	for _, city := range g.Cities {
+		if city.CityAlt != "" {
+			c.AltNames = true
+			break
+		}
+	}
+	return c
+}
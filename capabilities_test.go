@@ -0,0 +1,23 @@
+package geobed
+
+import "testing"
+
+func TestCapabilities(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306}}, nil,
+		WithDataDir(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Capabilities{Countries: true}
+	if got := g.Capabilities(); got != want {
+		t.Errorf("Capabilities() without admin1 file or alternate names = %+v, want %+v", got, want)
+	}
+
+	g, err = GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Capabilities(); !got.AdminDivisions || !got.AltNames || !got.Countries || got.PostalCodes || got.Timezones {
+		t.Errorf("Capabilities() of the default instance = %+v", got)
+	}
+}
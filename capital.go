@@ -0,0 +1,36 @@
+package geobed
+
+// buildCapitals indexes each country's capital. Cities with the Geonames
+// feature code PPLC win, the most populous if a country has several; the
+// embedded cache records the code for nearly every country. Countries
+// without one (all of them with caches generated before feature codes were
+// recorded, and cities from records or places) fall back to resolving
+// CountryInfo.Capital by name, as CapitalCity does.
+func (g *GeoBed) buildCapitals() {
+	g.capitals = make(map[string]GeobedCity)
+	for _, c := range g.Cities {
+		if !c.capital {
+			continue
+		}
+		cc := c.Country()
+		if prev, ok := g.capitals[cc]; !ok || c.Population > prev.Population {
+			g.capitals[cc] = c
+		}
+	}
+	for _, co := range g.Countries {
+		if _, ok := g.capitals[co.ISO]; ok {
+			continue
+		}
+		if c, ok := g.CapitalCity(co); ok {
+			g.capitals[co.ISO] = c
+		}
+	}
+}
+
+// Capital returns the capital of the country with the given ISO code
+// ("FR" → Paris) from an index built at load time, or false if the country
+// is unknown or its capital is not in the dataset.
+func (g *GeoBed) Capital(iso string) (GeobedCity, bool) {
+	c, ok := g.capitals[toUpper(iso)]
+	return c, ok
+}
@@ -0,0 +1,69 @@
+package geobed
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCapital(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for iso, want := range map[string]string{"fr": "Paris", "DE": "Berlin", "CA": "Ottawa"} {
+		if c, ok := g.Capital(iso); !ok || c.City != want {
+			t.Errorf("Capital(%q) = %s, %v; want %s", iso, c.City, ok, want)
+		}
+	}
+	if _, ok := g.Capital("XX"); ok {
+		t.Error("Capital(XX) found a capital for an unknown country")
+	}
+}
+
+// TestCapital_EmbeddedFeatureCodes checks that the embedded cache carries
+// PPLC flags, including for capitals that countryInfo names differently or
+// not at all.
+func TestCapital_EmbeddedFeatureCodes(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for iso, want := range map[string]string{"KZ": "Astana", "PW": "Ngerulmud", "BQ": "Kralendijk", "BR": "Brasília"} {
+		c, ok := g.Capital(iso)
+		if !ok || c.City != want || !c.capital {
+			t.Errorf("Capital(%q) = %s, %v (PPLC %v); want the PPLC city %s", iso, c.City, ok, c.capital, want)
+		}
+	}
+	flagged := 0
+	for _, co := range g.Countries {
+		if c, ok := g.Capital(co.ISO); ok && c.capital {
+			flagged++
+		}
+	}
+	if flagged < 200 {
+		t.Errorf("%d of %d countries have a PPLC capital, want at least 200", flagged, len(g.Countries))
+	}
+}
+
+func TestCapital_FeatureCode(t *testing.T) {
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+	rows := append([]string{
+		// A more populous city that is not the capital, and a capital
+		// whose name differs from countryInfo's "Paris".
+		"2995469\tMarseille\tMarseille\t\t43.29695\t5.38107\tP\tPPLA\tFR\t\t93\t13\t133\t13055\t870018\t\t28\tEurope/Paris\t2023-01-01",
+	}, testCityRows...)
+	rows[2] = "2988507\tParis-Centre\tParis-Centre\t\t48.85341\t2.3488\tP\tPPLC\tFR\t\t11\t75\t751\t75056\t2138551\t\t42\tEurope/Paris\t2023-01-01"
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), rows)
+
+	g, err := NewGeobed(WithDataset(DatasetCities15000), WithOffline(), WithDataDir(dir), WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := g.Capital("FR"); !ok || c.City != "Paris-Centre" {
+		t.Errorf("Capital(FR) = %s, %v; want the PPLC city Paris-Centre", c.City, ok)
+	}
+	if c, ok := g.Capital("GB"); !ok || c.City != "London" {
+		t.Errorf("Capital(GB) = %s, %v; want London", c.City, ok)
+	}
+}
@@ -0,0 +1,190 @@
+package geobed
+
+import (
+	"compress/bzip2"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// movedThresholdKm is how far a city must move between dataset versions to
+// be reported as ChangeMoved. Smaller shifts are coordinate refinements.
+const movedThresholdKm = 1.0
+
+// fallbackMatchKm is how close a city of the same name and country must be
+// to pair cities whose IDs differ between dataset versions.
+const fallbackMatchKm = 10.0
+
+// ChangeKind is a set of changes to one city between two dataset versions.
+type ChangeKind uint8
+
+const (
+	ChangeAdded      ChangeKind = 1 << iota // Present only in the new dataset
+	ChangeRemoved                           // Present only in the old dataset
+	ChangeMoved                             // Coordinates moved more than 1km
+	ChangeRenamed                           // Primary name changed
+	ChangePopulation                        // Population changed
+	ChangeID                                // ID changed; OldID holds the previous one
+)
+
+// Has reports whether k includes all changes in other.
+func (k ChangeKind) Has(other ChangeKind) bool {
+	return k&other == other
+}
+
+// CityChange describes how one city, identified by GeobedCity.ID, differs
+// between two dataset versions. Old is zero for added cities and New is zero
+// for removed ones.
+type CityChange struct {
+	ID              string // ID in the new dataset, or the old one for removed cities
+	OldID           string // ID in the old dataset (ChangeID only)
+	Kind            ChangeKind
+	Old, New        GeobedCity
+	MovedKm         float64 // Distance between Old and New (ChangeMoved only)
+	PopulationDelta int64   // New minus old population (ChangePopulation only)
+}
+
+// DiffCities compares two versions of a dataset and returns one CityChange
+// per city that was added, removed, or modified, sorted by ID. Downstream
+// systems that stored IDs can use it to reconcile their records after a
+// dataset update.
+//
+// Cities are paired by ID first. Cities left unpaired are then paired with
+// the nearest city of the same name and country within 10km, and reported
+// with ChangeID: this covers cities without a Geonames ID, whose hash ID
+// changes when they move, and datasets built before Geonames IDs were
+// stored. Renames of such cities still show up as a removal plus an
+// addition.
+func DiffCities(oldCities, newCities []GeobedCity) []CityChange {
+	oldByID := citiesByID(oldCities)
+	newByID := citiesByID(newCities)
+
+	var changes []CityChange
+	var oldLeft []string
+	for id, o := range oldByID {
+		if n, ok := newByID[id]; ok {
+			if c := diffCity(id, o, n); c.Kind != 0 {
+				changes = append(changes, c)
+			}
+			continue
+		}
+		oldLeft = append(oldLeft, id)
+	}
+
+	// Pair the rest by name, country, and proximity, nearest first.
+	newLeft := make(map[dedupeKey][]string)
+	for id, n := range newByID {
+		if _, ok := oldByID[id]; !ok {
+			k := dedupeKey{n.country, toLower(n.City)}
+			newLeft[k] = append(newLeft[k], id)
+		}
+	}
+	for _, ids := range newLeft {
+		sort.Strings(ids)
+	}
+	sort.Strings(oldLeft)
+	for _, oldID := range oldLeft {
+		o := oldByID[oldID]
+		k := dedupeKey{o.country, toLower(o.City)}
+		best, bestKm := -1, fallbackMatchKm
+		for i, id := range newLeft[k] {
+			if km := cityDistanceKm(o, newByID[id]); km <= bestKm {
+				best, bestKm = i, km
+			}
+		}
+		if best < 0 {
+			changes = append(changes, CityChange{ID: oldID, Kind: ChangeRemoved, Old: o})
+			continue
+		}
+		id := newLeft[k][best]
+		newLeft[k] = slices.Delete(newLeft[k], best, best+1)
+		c := diffCity(id, o, newByID[id])
+		c.Kind |= ChangeID
+		c.OldID = oldID
+		changes = append(changes, c)
+	}
+	for _, ids := range newLeft {
+		for _, id := range ids {
+			changes = append(changes, CityChange{ID: id, Kind: ChangeAdded, New: newByID[id]})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes
+}
+
+// diffCity compares two versions of the city with the given new ID.
+func diffCity(id string, o, n GeobedCity) CityChange {
+	c := CityChange{ID: id, Old: o, New: n}
+	if km := cityDistanceKm(o, n); km > movedThresholdKm {
+		c.Kind |= ChangeMoved
+		c.MovedKm = km
+	}
+	if o.City != n.City {
+		c.Kind |= ChangeRenamed
+	}
+	if o.Population != n.Population {
+		c.Kind |= ChangePopulation
+		c.PopulationDelta = int64(n.Population) - int64(o.Population)
+	}
+	return c
+}
+
+// DiffCaches loads the city caches in oldDir and newDir and compares them
+// with DiffCities. Each directory must contain g.c.dmp or g.c.dmp.bz2; an
+// empty directory name selects the cache NewGeobed would load by default.
+func DiffCaches(oldDir, newDir string) ([]CityChange, error) {
+	oldCities, err := loadCityCacheDir(oldDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading old cache: %w", err)
+	}
+	newCities, err := loadCityCacheDir(newDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading new cache: %w", err)
+	}
+	return DiffCities(oldCities, newCities), nil
+}
+
+// loadCityCacheDir reads the city cache from dir, preferring the
+// uncompressed file like openOptionallyBzippedFile. Unlike the default
+// loader it never falls back to the embedded cache.
+func loadCityCacheDir(dir string) ([]GeobedCity, error) {
+	if dir == "" {
+		return loadGeobedCityData(defaultConfig().CacheDir)
+	}
+	path := filepath.Join(dir, "g.c.dmp")
+	if fh, err := os.Open(path); err == nil {
+		defer fh.Close()
+		return decodeCityCache(fh)
+	}
+	fh, err := os.Open(path + ".bz2")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("opening %s: %w: %w", path, ErrCacheMissing, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer fh.Close()
+	return decodeCityCache(bzip2.NewReader(fh))
+}
+
+// citiesByID indexes cities by ID. If two cities share an ID, the first wins.
+func citiesByID(cities []GeobedCity) map[string]GeobedCity {
+	m := make(map[string]GeobedCity, len(cities))
+	for _, c := range cities {
+		id := c.ID()
+		if _, dup := m[id]; !dup {
+			m[id] = c
+		}
+	}
+	return m
+}
+
+// cityDistanceKm returns the great-circle distance between two cities.
+func cityDistanceKm(a, b GeobedCity) float64 {
+	return distanceKm(cityLatLng(a), cityLatLng(b))
+}
@@ -0,0 +1,123 @@
+package geobed
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffCities(t *testing.T) {
+	city := func(id int32, name string, lat, lng float32, pop int32) GeobedCity {
+		return mustCity(t, geobedCityGob{GeonameID: id, City: name, Country: "US", Latitude: lat, Longitude: lng, Population: pop})
+	}
+
+	oldCities := []GeobedCity{
+		city(1, "Unchanged", 10, 10, 100),
+		city(2, "Gone", 20, 20, 100),
+		city(3, "Old Name", 30, 30, 100),
+		city(4, "Drifter", 40, 40, 100),
+		city(5, "Nudged", 50, 50, 100),
+		city(6, "Grower", 60, 60, 100),
+		city(0, "No ID", 70, 70, 100),
+		city(0, "Far", 71, 71, 100),
+		city(0, "Switched", 75, 75, 100),
+	}
+	newCities := []GeobedCity{
+		city(1, "Unchanged", 10, 10, 100),
+		city(3, "New Name", 30, 30, 100),
+		city(4, "Drifter", 40.1, 40, 150),
+		city(5, "Nudged", 50.001, 50, 100), // ~110m: below the move threshold
+		city(6, "Grower", 60, 60, 90),
+		city(0, "No ID", 70.05, 70, 100), // hash ID changes, paired by name and proximity
+		city(0, "Far", 72, 71, 100),      // too far to pair: removal + addition
+		city(8, "Switched", 75, 75, 100), // hash ID replaced by a Geonames ID
+		city(7, "Fresh", 80, 80, 100),
+	}
+
+	changes := DiffCities(oldCities, newCities)
+	byName := make(map[string]CityChange)
+	for i, c := range changes {
+		if i > 0 && changes[i-1].ID >= c.ID {
+			t.Errorf("changes not sorted by ID at %d", i)
+		}
+		name := c.New.City
+		if c.Kind.Has(ChangeRemoved) {
+			name = "-" + c.Old.City
+		}
+		byName[name] = c
+	}
+
+	want := map[string]ChangeKind{
+		"-Gone":    ChangeRemoved,
+		"New Name": ChangeRenamed,
+		"Drifter":  ChangeMoved | ChangePopulation,
+		"Grower":   ChangePopulation,
+		"No ID":    ChangeMoved | ChangeID,
+		"-Far":     ChangeRemoved,
+		"Far":      ChangeAdded,
+		"Switched": ChangeID,
+		"Fresh":    ChangeAdded,
+	}
+	if len(byName) != len(want) {
+		t.Errorf("got %d changes, want %d: %+v", len(byName), len(want), changes)
+	}
+	for name, kind := range want {
+		c, ok := byName[name]
+		if !ok {
+			t.Errorf("missing change for %q", name)
+			continue
+		}
+		if c.Kind != kind {
+			t.Errorf("%q: Kind = %b, want %b", name, c.Kind, kind)
+		}
+	}
+
+	if d := byName["Drifter"]; d.MovedKm < 10 || d.MovedKm > 12 || d.PopulationDelta != 50 {
+		t.Errorf("Drifter: MovedKm = %.2f, PopulationDelta = %d; want ~11.1, 50", d.MovedKm, d.PopulationDelta)
+	}
+	if d := byName["Grower"].PopulationDelta; d != -10 {
+		t.Errorf("Grower: PopulationDelta = %d, want -10", d)
+	}
+	if got := byName["New Name"]; got.ID != "3" || got.Old.City != "Old Name" {
+		t.Errorf("rename change = %+v", got)
+	}
+
+	if got := byName["Switched"]; got.ID != "8" || got.OldID != oldCities[8].ID() {
+		t.Errorf("switched change = ID %q, OldID %q; want 8, %q", got.ID, got.OldID, oldCities[8].ID())
+	}
+
+	if c := DiffCities(oldCities, oldCities); len(c) != 0 {
+		t.Errorf("DiffCities(x, x) = %d changes, want 0", len(c))
+	}
+}
+
+func TestDiffCaches(t *testing.T) {
+	write := func(dir string, cities ...geobedCityGob) {
+		t.Helper()
+		var b bytes.Buffer
+		if err := gob.NewEncoder(&b).Encode(cities); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "g.c.dmp"), b.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	write(oldDir, geobedCityGob{GeonameID: 1, City: "Austin", Country: "US", Population: 1})
+	write(newDir, geobedCityGob{GeonameID: 1, City: "Austin", Country: "US", Population: 2})
+
+	changes, err := DiffCaches(oldDir, newDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Kind != ChangePopulation || changes[0].PopulationDelta != 1 {
+		t.Errorf("DiffCaches = %+v, want one population change of +1", changes)
+	}
+
+	if _, err := DiffCaches(oldDir, t.TempDir()); !errors.Is(err, ErrCacheMissing) {
+		t.Errorf("DiffCaches with empty dir: err = %v, want ErrCacheMissing", err)
+	}
+}
@@ -0,0 +1,78 @@
+package geobed
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// LatLng is a point in degrees.
+type LatLng struct {
+	Lat, Lng float64
+}
+
+// PointCluster is a city and the points ClusterPoints assigned to it.
+type PointCluster struct {
+	City   GeobedCity
+	Points []int // Indices into the points passed to ClusterPoints, ascending
+}
+
+// ClusterPoints assigns each point to the city ReverseGeocode would return
+// for it and groups the points by city, e.g. to turn millions of GPS fixes
+// into per-city counts for a heatmap. Points farther than maxKm from their
+// city, outside the reverse geocoding range, or with NaN/Inf coordinates are
+// left out. maxKm <= 0 applies only the reverse geocoding range.
+//
+// Candidate cities are looked up once per S2 cell and shared by every point
+// in it, so dense point sets cost far less than one ReverseGeocode per
+// point. Clusters are ordered by size, largest first.
+func (g *GeoBed) ClusterPoints(points []LatLng, maxKm float64) []PointCluster {
+	if maxKm <= 0 {
+		maxKm = math.Inf(1)
+	}
+
+	cellMemo := make(map[s2.CellID][]int)
+	byCity := make(map[int]*PointCluster)
+	for i, p := range points {
+		if math.IsNaN(p.Lat) || math.IsNaN(p.Lng) ||
+			math.IsInf(p.Lat, 0) || math.IsInf(p.Lng, 0) {
+			continue
+		}
+		ll := s2.LatLngFromDegrees(p.Lat, p.Lng)
+		cell := s2.CellIDFromLatLng(ll).Parent(s2CellLevel)
+		indices, ok := cellMemo[cell]
+		if !ok {
+			indices = g.cellCandidates(cell, 0)
+			cellMemo[cell] = indices
+		}
+
+		_, best, ok := g.pickReverse(ll, indices, MetroOverride{})
+		if !ok || best.km > maxKm {
+			continue
+		}
+		c := byCity[best.idx]
+		if c == nil {
+			c = &PointCluster{City: best.city}
+			byCity[best.idx] = c
+		}
+		c.Points = append(c.Points, i)
+	}
+
+	keys := make([]int, 0, len(byCity))
+	for k := range byCity {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b int) int {
+		if c := cmp.Compare(len(byCity[b].Points), len(byCity[a].Points)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	clusters := make([]PointCluster, len(keys))
+	for i, k := range keys {
+		clusters[i] = *byCity[k]
+	}
+	return clusters
+}
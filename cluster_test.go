@@ -0,0 +1,87 @@
+package geobed
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestClusterPoints(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	points := []LatLng{
+		{52.5200, 13.4050},   // 0: Berlin
+		{48.8566, 2.3522},    // 1: Paris
+		{52.5163, 13.3777},   // 2: Berlin, Brandenburg Gate
+		{0, -140},            // 3: Pacific Ocean, out of range
+		{math.NaN(), 0},      // 4: invalid
+		{52.5075, 13.4260},   // 5: Berlin, Kreuzberg
+		{48.8584, 2.2945},    // 6: Paris, Eiffel Tower
+		{52.5308, 13.3847},   // 7: Berlin, Mitte
+		{40.7128, -74.0060},  // 8: New York City
+		{math.Inf(1), 13.40}, // 9: invalid
+	}
+	clusters := g.ClusterPoints(points, 0)
+
+	want := []struct {
+		city   string
+		points []int
+	}{
+		{"Berlin", []int{0, 2, 5, 7}},
+		{"Paris", []int{1, 6}},
+		{"New York City", []int{8}},
+	}
+	if len(clusters) != len(want) {
+		for _, c := range clusters {
+			t.Logf("%s: %v", c.City.City, c.Points)
+		}
+		t.Fatalf("ClusterPoints returned %d clusters, want %d", len(clusters), len(want))
+	}
+	for i, w := range want {
+		c := clusters[i]
+		if c.City.City != w.city || !slices.Equal(c.Points, w.points) {
+			t.Errorf("cluster %d = %s %v, want %s %v", i, c.City.City, c.Points, w.city, w.points)
+		}
+	}
+
+	// Every cluster agrees with ReverseGeocode for its points.
+	for _, c := range clusters {
+		for _, i := range c.Points {
+			if got := g.ReverseGeocode(points[i].Lat, points[i].Lng); got.City != c.City.City {
+				t.Errorf("point %d: ReverseGeocode = %q, cluster city %q", i, got.City, c.City.City)
+			}
+		}
+	}
+}
+
+func TestClusterPoints_MaxKm(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// About 20km outside central Berlin, still within reverse geocoding range.
+	points := []LatLng{{52.5200, 13.4050}, {52.70, 13.40}}
+	all := g.ClusterPoints(points, 0)
+	near := g.ClusterPoints(points, 1)
+
+	total := func(cs []PointCluster) int {
+		n := 0
+		for _, c := range cs {
+			n += len(c.Points)
+		}
+		return n
+	}
+	if total(all) != 2 {
+		t.Errorf("ClusterPoints(maxKm=0) assigned %d points, want 2", total(all))
+	}
+	if total(near) >= total(all) {
+		t.Errorf("ClusterPoints(maxKm=1) assigned %d points, want fewer than %d", total(near), total(all))
+	}
+	if len(g.ClusterPoints(nil, 0)) != 0 {
+		t.Error("ClusterPoints(nil) should return no clusters")
+	}
+}
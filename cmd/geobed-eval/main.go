@@ -0,0 +1,79 @@
+// Command geobed-eval measures forward geocoding accuracy against a labeled
+// dataset, so scoring and tuning changes can be quantified instead of judged
+// from a handful of unit tests.
+//
+// Usage:
+//
+//	go run ./cmd/geobed-eval [-fuzzy N] [-exact] [-min P] [-v] [dataset.tsv]
+//
+// The dataset defaults to ./testdata/gold.tsv. Each line holds a query and the
+// expected Geonames ID separated by a tab. With -min, the command exits with
+// status 1 when precision@1 falls below P, which makes it usable in CI.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/andreiashu/geobed"
+)
+
+func main() {
+	fuzzy := flag.Int("fuzzy", 0, "FuzzyDistance passed to Geocode")
+	exact := flag.Bool("exact", false, "use ExactCity matching")
+	minPrecision := flag.Float64("min", 0, "exit non-zero when precision@1 is below this value")
+	verbose := flag.Bool("v", false, "list every failing case")
+	flag.Parse()
+
+	path := "./testdata/gold.tsv"
+	if flag.NArg() > 0 {
+		path = flag.Arg(0)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening dataset: %v\n", err)
+		os.Exit(1)
+	}
+	cases, err := geobed.ReadEvalCases(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading dataset: %v\n", err)
+		os.Exit(1)
+	}
+
+	g, err := geobed.NewGeobed()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading geobed: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err := g.Evaluate(cases, geobed.GeocodeOptions{ExactCity: *exact, FuzzyDistance: *fuzzy})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error evaluating: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *verbose {
+		for _, fl := range report.Failures {
+			if fl.Got.City == "" {
+				fmt.Printf("MISS  %-30q want %d, got no result\n", fl.Case.Query, fl.Case.GeonameID)
+				continue
+			}
+			fmt.Printf("WRONG %-30q want %d, got %d (%s, %s, %s)\n", fl.Case.Query, fl.Case.GeonameID,
+				fl.GotID, fl.Got.City, fl.Got.Region(), fl.Got.Country())
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Cases:        %d\n", report.Total)
+	fmt.Printf("Correct:      %d\n", report.Correct)
+	fmt.Printf("No result:    %d\n", report.NoResult)
+	fmt.Printf("Precision@1:  %.3f\n", report.PrecisionAt1())
+
+	if report.PrecisionAt1() < *minPrecision {
+		fmt.Fprintf(os.Stderr, "precision@1 %.3f is below -min %.3f\n", report.PrecisionAt1(), *minPrecision)
+		os.Exit(1)
+	}
+}
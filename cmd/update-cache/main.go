@@ -3,29 +3,92 @@
 //
 // Usage:
 //
-//	go run ./cmd/update-cache
+//	go run ./cmd/update-cache [-download-only | -parse-only] [-big-city-grid] [-stats-json file]
 //
-// This reads from ./geobed-data/ and writes to ./geobed-cache/.
+// This reads from the geobed data directory and writes to the cache
+// directory, chosen as for geobed.NewGeobed: GEOBED_DATA_DIR and
+// GEOBED_CACHE_DIR when set, ./geobed-data/ and ./geobed-cache/ when run
+// from the repository root, and the user cache directory otherwise. The
+// cache directory used is printed. Raw files missing from the data
+// directory are downloaded first. Mirrored
+// environments can split the phases: -download-only fetches the raw files
+// on a host with network access, and -parse-only builds the cache from
+// files copied to the build host, never reaching out to Geonames.
+// -big-city-grid also writes the grid behind geobed.ApproxReverseGeocode,
+// so instances created with geobed.WithBigCityGrid load it instead of
+// building it.
+//
+// After regeneration the index sizes and the cache file sizes before and
+// after bzip2 compression are printed; -stats-json also writes them as JSON,
+// for tracking dataset growth across updates.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/andreiashu/geobed"
 )
 
 func main() {
+	downloadOnly := flag.Bool("download-only", false, "download missing raw data files and exit")
+	parseOnly := flag.Bool("parse-only", false, "build the cache from raw data files already present, without downloading")
+	bigCityGrid := flag.Bool("big-city-grid", false, "also precompute the nearest big city per level-7 S2 cell")
+	statsJSON := flag.String("stats-json", "", "also write the index build report as JSON to `file`")
+	flag.Parse()
+	if *downloadOnly && *parseOnly {
+		fmt.Fprintln(os.Stderr, "-download-only and -parse-only are mutually exclusive")
+		os.Exit(2)
+	}
+
 	fmt.Println("=== Geobed Cache Regeneration ===")
 	fmt.Println()
 
+	if !*parseOnly {
+		fmt.Println("Downloading missing raw data...")
+		if err := geobed.DownloadDataSets(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error downloading raw data: %v\n", err)
+			os.Exit(1)
+		}
+		if *downloadOnly {
+			fmt.Println("      Raw data files are in place; build the cache with -parse-only.")
+			return
+		}
+	}
+
 	// Step 1: Regenerate cache
 	fmt.Println("[1/2] Regenerating cache from raw data...")
-	if err := geobed.RegenerateCache(); err != nil {
+	var opts []geobed.Option
+	if *bigCityGrid {
+		opts = append(opts, geobed.WithBigCityGrid())
+	}
+	stats, err := geobed.RegenerateCacheWithStats(opts...)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error regenerating cache: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println("      Cache files written to ./geobed-cache/")
+	fmt.Printf("      Cache files written to %s\n", stats.CacheDir)
+	alt := stats.AltNames
+	fmt.Printf("      Cities: %d\n", stats.Cities)
+	fmt.Printf("      Alt names kept: %d (dropped %d duplicates, %d over-long)\n",
+		alt.Kept, alt.Duplicates, alt.TooLong)
+	fmt.Printf("      Alt name bytes: %d -> %d (%.1f%% smaller)\n",
+		alt.BytesIn, alt.BytesOut, percentSmaller(alt.BytesIn, alt.BytesOut))
+	fmt.Printf("      Name index: %d keys, %d postings (%d repeated names skipped, %.1f%% smaller)\n",
+		stats.IndexKeys, stats.IndexPostings, stats.RepeatedKeys,
+		percentSmaller(stats.IndexPostings+stats.RepeatedKeys, stats.IndexPostings))
+
+	report := newBuildReport(stats)
+	report.print()
+	if *statsJSON != "" {
+		if err := report.writeJSON(*statsJSON); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing build report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("      Build report written to %s\n", *statsJSON)
+	}
 
 	// Step 2: Validate
 	fmt.Println("[2/2] Validating generated cache...")
@@ -39,7 +102,15 @@ func main() {
 	fmt.Println("Cache regenerated and validated.")
 	fmt.Println()
 	fmt.Println("Next steps:")
-	fmt.Println("  1. bzip2 -f geobed-cache/*.dmp")
+	fmt.Printf("  1. bzip2 -f %s\n", filepath.Join(stats.CacheDir, "*.dmp"))
 	fmt.Println("  2. go test ./...")
 	fmt.Println("  3. git add geobed-data geobed-cache")
 }
+
+// percentSmaller returns how much smaller after is than before, in percent.
+func percentSmaller(before, after int) float64 {
+	if before == 0 {
+		return 0
+	}
+	return 100 * float64(before-after) / float64(before)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/andreiashu/geobed"
+)
+
+// buildReport is the index build report printed after regeneration and
+// written by -stats-json.
+type buildReport struct {
+	Cities            int          `json:"cities"`
+	NameIndexKeys     int          `json:"name_index_keys"`
+	NameIndexPostings int          `json:"name_index_postings"`
+	AvgPostings       float64      `json:"avg_postings"`
+	Cells             int          `json:"cells"`
+	CellOccupancy     float64      `json:"cell_occupancy"`
+	AvgCellCities     float64      `json:"avg_cell_cities"`
+	MaxCellCities     int          `json:"max_cell_cities"`
+	Files             []fileReport `json:"files"`
+}
+
+// fileReport gives a cache file's size before and after bzip2 compression.
+// Compressed is omitted when the bzip2 command is not available.
+type fileReport struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	Compressed int64  `json:"compressed,omitempty"`
+}
+
+// newBuildReport gathers the report from the build statistics, measuring
+// compressed sizes with the same bzip2 command the cache is committed with.
+func newBuildReport(stats geobed.CacheBuildStats) buildReport {
+	r := buildReport{
+		Cities:            stats.Cities,
+		NameIndexKeys:     stats.IndexKeys,
+		NameIndexPostings: stats.IndexPostings,
+		AvgPostings:       stats.AvgPostings(),
+		Cells:             stats.Cells,
+		CellOccupancy:     stats.CellOccupancy(),
+		MaxCellCities:     stats.MaxCellCities,
+	}
+	if stats.Cells > 0 {
+		r.AvgCellCities = float64(stats.Cities) / float64(stats.Cells)
+	}
+	for _, f := range stats.Files {
+		fr := fileReport{Name: f.Name, Size: f.Size}
+		if n, err := bzip2Size(filepath.Join(stats.CacheDir, f.Name)); err == nil {
+			fr.Compressed = n
+		}
+		r.Files = append(r.Files, fr)
+	}
+	return r
+}
+
+// print writes the report in the indented style of the other build output.
+func (r buildReport) print() {
+	fmt.Printf("      Average postings per key: %.2f\n", r.AvgPostings)
+	fmt.Printf("      Cell index: %d cells occupied (%.3f%% of all), %.1f cities per cell on average, %d at most\n",
+		r.Cells, 100*r.CellOccupancy, r.AvgCellCities, r.MaxCellCities)
+	for _, f := range r.Files {
+		if f.Compressed == 0 {
+			fmt.Printf("      %s: %d bytes (bzip2 not found, compressed size unknown)\n", f.Name, f.Size)
+			continue
+		}
+		fmt.Printf("      %s: %d bytes -> %d bzip2-compressed (%.1f%% smaller)\n",
+			f.Name, f.Size, f.Compressed, percentSmaller(int(f.Size), int(f.Compressed)))
+	}
+}
+
+// writeJSON writes the report as indented JSON to path.
+func (r buildReport) writeJSON(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// bzip2Size returns the size of path once compressed by the bzip2 command,
+// without writing the compressed file.
+func bzip2Size(path string) (int64, error) {
+	var w countingWriter
+	cmd := exec.Command("bzip2", "-c", path)
+	cmd.Stdout = &w
+	if err := cmd.Run(); err != nil {
+		return 0, err
+	}
+	return int64(w), nil
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
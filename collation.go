@@ -0,0 +1,43 @@
+package geobed
+
+import (
+	"slices"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// WithCollation orders the city lists geobed hands out by the collation
+// rules of locale, a BCP 47 tag such as "sv" or "de", so that "Å", "Ö" and
+// "ü" sort where speakers of that language expect them: after "Z" in
+// Swedish, next to "u" in German. It applies to SortCities and to Suggest
+// with Alphabetical. The order of g.Cities, which index positions depend
+// on, never changes.
+func WithCollation(locale string) Option {
+	return func(c *GeobedConfig) {
+		c.Collation = locale
+	}
+}
+
+// SortCities sorts cities by name, per WithCollation if set and otherwise
+// in the case-insensitive order of g.Cities. Cities with equal names keep
+// their relative order.
+func (g *GeoBed) SortCities(cities []GeobedCity) {
+	compare := g.nameCompare()
+	slices.SortStableFunc(cities, func(a, b GeobedCity) int {
+		return compare(a.City, b.City)
+	})
+}
+
+// nameCompare returns the comparison SortCities orders names by. A
+// collate.Collator is not safe for concurrent use, so each call gets its own.
+func (g *GeoBed) nameCompare() func(a, b string) int {
+	if g.config == nil || g.config.Collation == "" {
+		return compareCaseInsensitive
+	}
+	tag, err := language.Parse(g.config.Collation)
+	if err != nil { // Rejected by NewGeobed; only reachable for hand-built instances
+		return compareCaseInsensitive
+	}
+	return collate.New(tag, collate.IgnoreCase).CompareString
+}
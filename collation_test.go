@@ -0,0 +1,71 @@
+package geobed
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSortCities_Collation(t *testing.T) {
+	names := []string{"Zurich", "Åre", "Ulm", "Überlingen", "Arvika"}
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"", []string{"Arvika", "Ulm", "Zurich", "Åre", "Überlingen"}},
+		{"de", []string{"Åre", "Arvika", "Überlingen", "Ulm", "Zurich"}},
+		{"sv", []string{"Arvika", "Ulm", "Überlingen", "Zurich", "Åre"}}, // Ü sorts as Y
+	}
+	for _, tt := range tests {
+		var records []CityRecord
+		for _, n := range names {
+			records = append(records, CityRecord{City: n, Country: "SE", Region: "07"})
+		}
+		g, err := NewGeobedFromRecords(records, nil, WithCollation(tt.locale))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cities := slices.Clone(g.Cities)
+		g.SortCities(cities)
+		var got []string
+		for _, c := range cities {
+			got = append(got, c.City)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SortCities with collation %q = %v, want %v", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest_Alphabetical(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Ödeshög", Country: "SE", Region: "06", Population: 3000},
+		{City: "Odense", Country: "DK", Region: "21", Population: 180000},
+		{City: "Oslo", Country: "NO", Region: "12", Population: 700000},
+	}, nil, WithCollation("sv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range g.Suggest("o", SuggestOptions{Alphabetical: true}) {
+		got = append(got, c.City)
+	}
+	if want := []string{"Odense", "Oslo"}; !slices.Equal(got, want) {
+		t.Errorf("Suggest(%q, Alphabetical) = %v, want %v", "o", got, want)
+	}
+	got = got[:0]
+	for _, c := range g.Suggest("ö", SuggestOptions{Alphabetical: true}) {
+		got = append(got, c.City)
+	}
+	if want := []string{"Ödeshög"}; !slices.Equal(got, want) {
+		t.Errorf("Suggest(%q, Alphabetical) = %v, want %v", "ö", got, want)
+	}
+}
+
+func TestWithCollation_Invalid(t *testing.T) {
+	_, err := NewGeobedFromRecords(nil, nil, WithCollation("not a locale"))
+	var ce *ConfigError
+	if !errors.As(err, &ce) || ce.Field != "Collation" {
+		t.Errorf("NewGeobedFromRecords(WithCollation(invalid)) error = %v, want ConfigError for Collation", err)
+	}
+}
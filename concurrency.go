@@ -0,0 +1,62 @@
+package geobed
+
+import "time"
+
+// WithMaxConcurrentFuzzy limits how many fuzzy queries (FuzzyDistance > 0)
+// may scan the name index at once. A fuzzy scan touches every index key, so
+// on a server a burst of fuzzy queries can otherwise saturate all CPUs.
+// Queries beyond the limit wait for a slot, or with WithFuzzyFallback run
+// without typo tolerance instead. n <= 0 disables the limit (the default).
+func WithMaxConcurrentFuzzy(n int) Option {
+	return func(c *GeobedConfig) {
+		c.MaxConcurrentFuzzy = n
+	}
+}
+
+// WithFuzzyFallback makes fuzzy queries that exceed WithMaxConcurrentFuzzy
+// run immediately as non-fuzzy queries (FuzzyDistance = 0) instead of
+// waiting for a slot, trading typo tolerance for bounded latency.
+func WithFuzzyFallback() Option {
+	return func(c *GeobedConfig) {
+		c.FuzzyFallback = true
+	}
+}
+
+// acquireFuzzySlot reserves a fuzzy-scan slot for a query with the given
+// options. It returns the options to run the query with, a release function
+// that must be called when the query finishes, and whether the query was
+// degraded to non-fuzzy matching (FuzzyDistance cleared) because no slot
+// became available before the fallback policy or the query deadline applied.
+func (g *GeoBed) acquireFuzzySlot(opts GeocodeOptions) (GeocodeOptions, func(), bool) {
+	if opts.FuzzyDistance <= 0 || g.fuzzySem == nil {
+		return opts, func() {}, false
+	}
+	release := func() { <-g.fuzzySem }
+
+	// Fast path: a slot is free.
+	select {
+	case g.fuzzySem <- struct{}{}:
+		return opts, release, false
+	default:
+	}
+
+	if g.config.FuzzyFallback {
+		opts.FuzzyDistance = 0
+		return opts, func() {}, true
+	}
+	if opts.Deadline.IsZero() {
+		g.fuzzySem <- struct{}{}
+		return opts, release, false
+	}
+
+	timer := time.NewTimer(time.Until(opts.Deadline))
+	defer timer.Stop()
+	select {
+	case g.fuzzySem <- struct{}{}:
+		return opts, release, false
+	case <-timer.C:
+		// Waiting used up the deadline: answer without the fuzzy scan.
+		opts.FuzzyDistance = 0
+		return opts, func() {}, true
+	}
+}
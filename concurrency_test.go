@@ -0,0 +1,59 @@
+package geobed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrentFuzzy(t *testing.T) {
+	g, err := NewGeobedFromRecords(fixtureCities, nil, WithMaxConcurrentFuzzy(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cap(g.fuzzySem) != 1 {
+		t.Fatalf("fuzzySem capacity = %d, want 1", cap(g.fuzzySem))
+	}
+	fuzzy := GeocodeOptions{FuzzyDistance: 1}
+
+	t.Run("queue", func(t *testing.T) {
+		g.fuzzySem <- struct{}{} // occupy the only slot
+
+		done := make(chan GeobedCity)
+		go func() { done <- g.Geocode("Austn", fuzzy) }()
+
+		select {
+		case <-done:
+			t.Fatal("fuzzy query ran while the only slot was taken")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		<-g.fuzzySem
+		if got := <-done; got.City != "Austin" {
+			t.Errorf("queued Geocode(Austn) = %q, want Austin", got.City)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		g.config.FuzzyFallback = true
+		defer func() { g.config.FuzzyFallback = false }()
+
+		g.fuzzySem <- struct{}{}
+		if got := g.Geocode("Austn", fuzzy); got.City == "Austin" {
+			t.Error("over-limit query should fall back to non-fuzzy matching")
+		}
+		<-g.fuzzySem
+
+		if got := g.Geocode("Austn", fuzzy); got.City != "Austin" {
+			t.Errorf("Geocode(Austn) with a free slot = %q, want Austin", got.City)
+		}
+	})
+
+	t.Run("non-fuzzy queries bypass the limit", func(t *testing.T) {
+		g.fuzzySem <- struct{}{}
+		defer func() { <-g.fuzzySem }()
+
+		if got := g.Geocode("Austin, TX"); got.City != "Austin" {
+			t.Errorf("Geocode(Austin, TX) = %q, want Austin", got.City)
+		}
+	})
+}
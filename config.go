@@ -0,0 +1,125 @@
+package geobed
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/text/language"
+)
+
+// ConfigError reports an invalid option value found by NewGeobed before
+// any data is loaded, or by CheckOptions in per-query options. It matches
+// ErrInvalidConfig and the underlying cause (such as ErrDataDirUnwritable)
+// with errors.Is.
+type ConfigError struct {
+	Field string // GeobedConfig field, e.g. "DataDir", or query option, e.g. "GeocodeOptions.Country"
+	Err   error  // What is wrong with it
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("geobed: invalid %s: %v", e.Field, e.Err)
+}
+
+func (e *ConfigError) Unwrap() []error {
+	return []error{ErrInvalidConfig, e.Err}
+}
+
+// validate checks the configuration and resolves the data and cache
+// directories to absolute paths, so a later change of working directory
+// does not move them. The data directory must be writable when the
+// configured dataset has to be downloaded; with the embedded dataset a
+// download only happens if the cache is unusable and is checked then.
+func (c *GeobedConfig) validate() error {
+	if err := c.Territories.validate(); err != nil {
+		return &ConfigError{"Territories", err}
+	}
+	if err := c.Dataset.validate(); err != nil {
+		return &ConfigError{"Dataset", err}
+	}
+	if err := c.validateDatasetDate(); err != nil {
+		return &ConfigError{"DatasetDate", err}
+	}
+	if c.Collation != "" {
+		if _, err := language.Parse(c.Collation); err != nil {
+			return &ConfigError{"Collation", err}
+		}
+	}
+	if err := c.Tuning.validate(); err != nil {
+		return &ConfigError{"Tuning", err}
+	}
+	if c.MissHook != nil && !(c.MissSampleRate > 0 && c.MissSampleRate <= 1) {
+		return &ConfigError{"MissSampleRate", fmt.Errorf("%v is not in (0, 1]", c.MissSampleRate)}
+	}
+
+	download := !c.Offline && (!c.Dataset.embedded() || c.DatasetDate != "")
+	var err error
+	if c.DataDir, err = resolveDir(c.DataDir, download); err != nil {
+		return &ConfigError{"DataDir", err}
+	}
+	if c.CacheDir, err = resolveDir(c.CacheDir, false); err != nil {
+		return &ConfigError{"CacheDir", err}
+	}
+	return nil
+}
+
+// resolveDir returns dir as an absolute path; an empty dir is the working
+// directory. It fails for a path naming a file. With writable, dir must
+// accept new files or, if it does not exist yet, its nearest existing
+// ancestor must be a directory (downloads create the rest).
+func resolveDir(dir string, writable bool) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	fi, err := os.Stat(abs)
+	switch {
+	case err == nil && !fi.IsDir():
+		return "", fmt.Errorf("%s is not a directory", abs)
+	case err == nil && writable:
+		if err := checkWritable(abs); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrDataDirUnwritable, err)
+		}
+	case err != nil && writable:
+		for parent := filepath.Dir(abs); ; parent = filepath.Dir(parent) {
+			if fi, err := os.Stat(parent); err == nil {
+				if !fi.IsDir() {
+					return "", fmt.Errorf("%w: %s is not a directory", ErrDataDirUnwritable, parent)
+				}
+				break
+			}
+			if parent == filepath.Dir(parent) {
+				break
+			}
+		}
+	}
+	return abs, nil
+}
+
+// checkWritable creates and removes a file in dir, which must exist.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".geobed-write-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// cacheDirReadOnly reports whether store would fail to write dir: dir, or
+// the nearest existing ancestor MkdirAll would create it in, does not accept
+// new files. Read-only mounts are the common case.
+func cacheDirReadOnly(dir string) bool {
+	for {
+		fi, err := os.Stat(dir)
+		if err == nil {
+			return !fi.IsDir() || checkWritable(dir) != nil
+		}
+		if parent := filepath.Dir(dir); parent != dir {
+			dir = parent
+			continue
+		}
+		return true
+	}
+}
@@ -1,6 +1,7 @@
 package geobed
 
 import (
+	"path/filepath"
 	"sync"
 	"testing"
 )
@@ -178,6 +179,39 @@ func TestGetDefaultGeobed_Singleton(t *testing.T) {
 	}
 }
 
+// TestResetDefaultGeobed verifies that after a reset GetDefaultGeobed builds
+// a new instance from the current environment.
+func TestResetDefaultGeobed(t *testing.T) {
+	orig, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ResetDefaultGeobed()
+		Swap(orig)
+	})
+
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), testCityRows)
+	t.Setenv(envDataDir, dir)
+	t.Setenv(envCacheDir, t.TempDir())
+	t.Setenv(envDataset, string(DatasetCities15000))
+	t.Setenv(envOffline, "true")
+
+	if g, _ := GetDefaultGeobed(); g != orig {
+		t.Fatal("GetDefaultGeobed() rebuilt the instance without a reset")
+	}
+	ResetDefaultGeobed()
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatalf("GetDefaultGeobed() after reset error = %v", err)
+	}
+	if g == orig || len(g.Cities) != len(testCityRows) {
+		t.Errorf("GetDefaultGeobed() after reset has %d cities, want a new instance with %d", len(g.Cities), len(testCityRows))
+	}
+}
+
 // TestGetDefaultGeobed_Concurrent verifies that GetDefaultGeobed is thread-safe
 // and that all concurrent calls receive the same instance.
 func TestGetDefaultGeobed_Concurrent(t *testing.T) {
@@ -231,7 +265,7 @@ func TestValidateCache(t *testing.T) {
 // TestCountryCount_AfterInitialization verifies that CountryCount returns
 // a positive value after GeoBed initialization.
 func TestCountryCount_AfterInitialization(t *testing.T) {
-	_, err := NewGeobed()
+	_, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("NewGeobed() error = %v", err)
 	}
@@ -248,7 +282,7 @@ func TestCountryCount_AfterInitialization(t *testing.T) {
 // TestRegionCount_AfterInitialization verifies that RegionCount returns
 // a positive value after GeoBed initialization.
 func TestRegionCount_AfterInitialization(t *testing.T) {
-	_, err := NewGeobed()
+	_, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("NewGeobed() error = %v", err)
 	}
@@ -259,9 +293,36 @@ func TestRegionCount_AfterInitialization(t *testing.T) {
 	}
 }
 
+// TestInstanceAccessors verifies that the per-instance count accessors agree
+// with the underlying data and the package-level interner counts.
+func TestInstanceAccessors(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatalf("NewGeobed() error = %v", err)
+	}
+
+	if got := g.CityCount(); got != len(g.Cities) || got < minCityCount {
+		t.Errorf("CityCount() = %d, want %d (>= %d)", got, len(g.Cities), minCityCount)
+	}
+	if got := g.CountryCountLoaded(); got != len(g.Countries) || got < minCountryCount {
+		t.Errorf("CountryCountLoaded() = %d, want %d (>= %d)", got, len(g.Countries), minCountryCount)
+	}
+
+	stats := g.InternerStats()
+	if stats.CountryCodes != CountryCount()-1 {
+		t.Errorf("InternerStats().CountryCodes = %d, want CountryCount()-1 = %d", stats.CountryCodes, CountryCount()-1)
+	}
+	if stats.RegionCodes != RegionCount()-1 {
+		t.Errorf("InternerStats().RegionCodes = %d, want RegionCount()-1 = %d", stats.RegionCodes, RegionCount()-1)
+	}
+	if stats.MaxCodes != 65535 {
+		t.Errorf("InternerStats().MaxCodes = %d, want 65535", stats.MaxCodes)
+	}
+}
+
 // TestDataIntegrity_CityFields verifies that cities have valid field values.
 func TestDataIntegrity_CityFields(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("NewGeobed() error = %v", err)
 	}
@@ -300,7 +361,7 @@ func TestDataIntegrity_CityFields(t *testing.T) {
 
 // TestDataIntegrity_CountryFields verifies that countries have valid field values.
 func TestDataIntegrity_CountryFields(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("NewGeobed() error = %v", err)
 	}
@@ -403,7 +464,7 @@ func TestNewGeobed_OptionsDoNotPanic(t *testing.T) {
 // Note: The sorting may use special collation rules for international characters,
 // so we just verify that some basic ordering exists rather than strict lexicographic ordering.
 func TestGeoBed_CitiesSorted(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("NewGeobed() error = %v", err)
 	}
@@ -440,7 +501,7 @@ func TestGeoBed_CitiesSorted(t *testing.T) {
 // TestCountryCount_RegionCount_Concurrent verifies that CountryCount and RegionCount
 // are safe to call concurrently.
 func TestCountryCount_RegionCount_Concurrent(t *testing.T) {
-	_, err := NewGeobed()
+	_, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("NewGeobed() error = %v", err)
 	}
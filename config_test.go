@@ -0,0 +1,90 @@
+package geobed
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestNewGeobed_InvalidConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		field string
+	}{
+		{"cache dir is a file", []Option{WithCacheDir(file)}, "CacheDir"},
+		{"data dir below a file", []Option{WithDataset(DatasetCities500), WithDataDir(filepath.Join(file, "data"))}, "DataDir"},
+		{"unknown dataset", []Option{WithDataset("cities42")}, "Dataset"},
+		{"bad dataset date", []Option{WithDatasetDate("June"), WithArchiveURL("https://example.org/{date}/{file}")}, "DatasetDate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGeobed(tt.opts...)
+			var ce *ConfigError
+			if !errors.Is(err, ErrInvalidConfig) || !errors.As(err, &ce) || ce.Field != tt.field {
+				t.Errorf("NewGeobed() error = %v, want a ConfigError for %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestResolveDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	wd, _ := os.Getwd()
+	if got, err := resolveDir("data", true); err != nil || got != filepath.Join(wd, "data") {
+		t.Errorf("resolveDir(data) = %q, %v; want %q", got, err, filepath.Join(wd, "data"))
+	}
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("permission bits are not enforced")
+	}
+	ro := filepath.Join(wd, "ro")
+	if err := os.Mkdir(ro, 0555); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveDir(ro, true); !errors.Is(err, ErrDataDirUnwritable) {
+		t.Errorf("resolveDir(read-only, writable) error = %v, want ErrDataDirUnwritable", err)
+	}
+	if _, err := resolveDir(ro, false); err != nil {
+		t.Errorf("resolveDir(read-only) error = %v", err)
+	}
+}
+
+func TestNewGeobed_ReadOnlyCacheDir(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cacheDirReadOnly(filepath.Join(t.TempDir(), "missing", "cache")) {
+		t.Error("cacheDirReadOnly() for a directory MkdirAll can create = true")
+	}
+
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), testCityRows)
+	g, err := NewGeobed(WithDataset(DatasetCities15000), WithOffline(), WithDataDir(dir), WithCacheDir(filepath.Join(file, "cache")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Capabilities().ReadOnlyCache {
+		t.Error("Capabilities().ReadOnlyCache = false for a cache directory below a file")
+	}
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("permission bits are not enforced")
+	}
+	ro := filepath.Join(t.TempDir(), "ro")
+	if err := os.Mkdir(ro, 0555); err != nil {
+		t.Fatal(err)
+	}
+	if !cacheDirReadOnly(ro) || !cacheDirReadOnly(filepath.Join(ro, "cache")) {
+		t.Error("cacheDirReadOnly() for a read-only directory = false")
+	}
+}
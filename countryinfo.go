@@ -0,0 +1,70 @@
+package geobed
+
+import "strings"
+
+// AreaKm2 returns the country's area in square kilometres.
+func (c CountryInfo) AreaKm2() float64 {
+	return float64(c.Area)
+}
+
+// PopulationDensity returns inhabitants per square kilometre, or 0 when
+// the area is unknown (as for some territories in the Geonames data).
+func (c CountryInfo) PopulationDensity() float64 {
+	if c.Area <= 0 {
+		return 0
+	}
+	return float64(c.Population) / float64(c.Area)
+}
+
+// TLDNormalized returns the country-code top-level domain in lower case
+// without the leading dot ("fr" for ".fr"), or "" if the country has none.
+func (c CountryInfo) TLDNormalized() string {
+	return toLower(strings.TrimPrefix(strings.TrimSpace(c.Tld), "."))
+}
+
+// FlagEmoji returns the country's flag as a pair of regional indicator
+// symbols ("🇫🇷" for FR), or "" if ISO is not a two-letter code. Platforms
+// without a flag for the pair (such as XK) show the two letters instead.
+func (c CountryInfo) FlagEmoji() string {
+	iso := strings.ToUpper(c.ISO)
+	if len(iso) != 2 {
+		return ""
+	}
+	var b strings.Builder
+	for i := range 2 {
+		if iso[i] < 'A' || iso[i] > 'Z' {
+			return ""
+		}
+		b.WriteRune(rune(iso[i]-'A') + '\U0001F1E6')
+	}
+	return b.String()
+}
+
+// DialCode returns the international calling code in E.164 prefix form,
+// e.g. "+33". Geonames lists North American Numbering Plan members with
+// their area code ("+1-876" for Jamaica), which is kept ("+1876"); of
+// several codes ("+1-809 and 1-829") the first is returned. It is "" for
+// countries without one.
+func (c CountryInfo) DialCode() string {
+	code, _, _ := strings.Cut(c.Phone, " and ")
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, code)
+	if digits == "" {
+		return ""
+	}
+	return "+" + digits
+}
+
+// CapitalCity resolves c.Capital against the loaded cities of the country,
+// so callers need not match the capital's name themselves. It returns false
+// for countries without a capital or whose capital is not in the dataset.
+func (g *GeoBed) CapitalCity(c CountryInfo) (GeobedCity, bool) {
+	if c.Capital == "" || c.ISO == "" {
+		return GeobedCity{}, false
+	}
+	return g.CityAt(c.Capital, c.ISO, "")
+}
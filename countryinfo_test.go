@@ -0,0 +1,69 @@
+package geobed
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCountryInfo_DerivedFields(t *testing.T) {
+	c := CountryInfo{Area: 1000, Population: 2500, Tld: ".FR"}
+	if c.AreaKm2() != 1000 {
+		t.Errorf("AreaKm2() = %v, want 1000", c.AreaKm2())
+	}
+	if c.PopulationDensity() != 2.5 {
+		t.Errorf("PopulationDensity() = %v, want 2.5", c.PopulationDensity())
+	}
+	if c.TLDNormalized() != "fr" {
+		t.Errorf("TLDNormalized() = %q, want fr", c.TLDNormalized())
+	}
+	if d := (CountryInfo{Population: 10}).PopulationDensity(); d != 0 || math.IsInf(d, 0) {
+		t.Errorf("PopulationDensity() without area = %v, want 0", d)
+	}
+	if tld := (CountryInfo{}).TLDNormalized(); tld != "" {
+		t.Errorf("TLDNormalized() without TLD = %q", tld)
+	}
+}
+
+func TestCountryInfo_FlagAndDialCode(t *testing.T) {
+	tests := []struct {
+		iso, phone string
+		flag, dial string
+	}{
+		{"FR", "33", "🇫🇷", "+33"},
+		{"jm", "+1-876", "🇯🇲", "+1876"},
+		{"DO", "+1-809 and 1-829", "🇩🇴", "+1809"},
+		{"HM", " ", "🇭🇲", ""},
+		{"", "", "", ""},
+		{"F1", "", "", ""},
+	}
+	for _, tt := range tests {
+		c := CountryInfo{ISO: tt.iso, Phone: tt.phone}
+		if got := c.FlagEmoji(); got != tt.flag {
+			t.Errorf("FlagEmoji() for %q = %q, want %q", tt.iso, got, tt.flag)
+		}
+		if got := c.DialCode(); got != tt.dial {
+			t.Errorf("DialCode() for %q = %q, want %q", tt.phone, got, tt.dial)
+		}
+	}
+}
+
+func TestCapitalCity(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byISO := make(map[string]CountryInfo)
+	for _, c := range g.Countries {
+		byISO[c.ISO] = c
+	}
+
+	for iso, want := range map[string]string{"FR": "Paris", "GB": "London", "JP": "Tokyo", "AU": "Canberra"} {
+		c, ok := g.CapitalCity(byISO[iso])
+		if !ok || c.City != want || c.Country() != iso {
+			t.Errorf("CapitalCity(%s) = %s, %s, %v; want %s", iso, c.City, c.Country(), ok, want)
+		}
+	}
+	if _, ok := g.CapitalCity(byISO["AQ"]); ok {
+		t.Error("CapitalCity(AQ) found a capital for Antarctica")
+	}
+}
@@ -0,0 +1,48 @@
+package geobed
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// CoverageCells returns the S2 cells at level (0-30) that contain at least
+// one loaded city, in increasing cell ID order. Drawing them shows where
+// the dataset has places; checking query points against them shows when
+// traffic concentrates where it has few. Level 10 cells, those of the
+// reverse-geocoding index, are about 10km across; level 6 about 150km.
+func (g *GeoBed) CoverageCells(level int) ([]s2.CellID, error) {
+	counts, err := g.cellCounts(level)
+	if err != nil {
+		return nil, err
+	}
+	cells := make([]s2.CellID, 0, len(counts))
+	for cell := range counts {
+		cells = append(cells, cell)
+	}
+	slices.Sort(cells)
+	return cells, nil
+}
+
+// cellStat aggregates the cities in one S2 cell.
+type cellStat struct {
+	cities     int
+	population int64
+}
+
+// cellCounts aggregates the loaded cities by their S2 cell at level.
+func (g *GeoBed) cellCounts(level int) (map[s2.CellID]cellStat, error) {
+	if level < 0 || level > s2.MaxLevel {
+		return nil, fmt.Errorf("coverage: S2 level %d out of range 0-%d", level, s2.MaxLevel)
+	}
+	counts := make(map[s2.CellID]cellStat)
+	for _, c := range g.Cities {
+		cell := s2.CellIDFromLatLng(s2.LatLngFromDegrees(float64(c.Latitude), float64(c.Longitude))).Parent(level)
+		st := counts[cell]
+		st.cities++
+		st.population += int64(c.Population)
+		counts[cell] = st
+	}
+	return counts, nil
+}
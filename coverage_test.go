@@ -0,0 +1,45 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestCoverageCells(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Paris", Country: "FR", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+		{City: "Boulogne-Billancourt", Country: "FR", Latitude: 48.83545, Longitude: 2.24128, Population: 121334},
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cells, err := g.CoverageCells(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != 2 || !slices.IsSorted(cells) {
+		t.Fatalf("CoverageCells(6) = %v, want 2 sorted cells", cells)
+	}
+	paris := s2.CellIDFromLatLng(s2.LatLngFromDegrees(48.85341, 2.3488)).Parent(6)
+	if !slices.Contains(cells, paris) {
+		t.Errorf("CoverageCells(6) = %v, missing the Paris cell %v", cells, paris)
+	}
+	for _, c := range cells {
+		if c.Level() != 6 {
+			t.Errorf("cell %v has level %d, want 6", c, c.Level())
+		}
+	}
+
+	if cells, _ := g.CoverageCells(0); len(cells) != 2 {
+		t.Errorf("CoverageCells(0) = %d cells, want one per cube face (2)", len(cells))
+	}
+	for _, level := range []int{-1, 31} {
+		if _, err := g.CoverageCells(level); err == nil {
+			t.Errorf("CoverageCells(%d) succeeded, want error", level)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package geobed
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ColumnMapping names the columns of a tabular city list for
+// LoadCustomCities. Columns are named by their header; with NoHeader they
+// are named by 1-based position ("1", "2", ...). City, Latitude, and
+// Longitude are required; the other columns are optional ("" = absent).
+type ColumnMapping struct {
+	Comma    rune // Field separator (0 = tab; ',' for CSV)
+	NoHeader bool // The first row is data rather than column names
+
+	City       string // City name
+	CityAlt    string // Alternate names, comma-separated
+	Country    string // ISO 3166-1 alpha-2 country code
+	Region     string // Administrative region code
+	Latitude   string // Latitude in degrees
+	Longitude  string // Longitude in degrees
+	Population string // Population count
+}
+
+// LoadCustomCities reads a TSV or CSV city list, such as an internal POI
+// export, into records for NewGeobedFromRecords, which indexes them like
+// any other dataset. Quoted fields follow RFC 4180. Rows repeating the
+// name, country, region, and location (to 4 decimal places, as for MaxMind
+// data) of an earlier row are dropped. A malformed row fails the whole load
+// with its line number.
+func LoadCustomCities(r io.Reader, mapping ColumnMapping) ([]CityRecord, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = mapping.Comma
+	if cr.Comma == 0 {
+		cr.Comma = '\t'
+	}
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	columns := make(map[string]int)
+	if !mapping.NoHeader {
+		header, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("reading header: %w", err)
+		}
+		for i, name := range header {
+			columns[strings.TrimSpace(name)] = i
+		}
+	}
+	col := func(name string, required bool) (int, error) {
+		if name == "" {
+			if required {
+				return -1, errors.New("column mapping: City, Latitude, and Longitude are required")
+			}
+			return -1, nil
+		}
+		if mapping.NoHeader {
+			n, err := strconv.Atoi(name)
+			if err != nil || n < 1 {
+				return -1, fmt.Errorf("column mapping: %q is not a 1-based column number", name)
+			}
+			return n - 1, nil
+		}
+		i, ok := columns[name]
+		if !ok {
+			return -1, fmt.Errorf("column mapping: no column %q in header", name)
+		}
+		return i, nil
+	}
+
+	var idx [7]int
+	for i, c := range []struct {
+		name     string
+		required bool
+	}{
+		{mapping.City, true}, {mapping.CityAlt, false}, {mapping.Country, false}, {mapping.Region, false},
+		{mapping.Latitude, true}, {mapping.Longitude, true}, {mapping.Population, false},
+	} {
+		var err error
+		if idx[i], err = col(c.name, c.required); err != nil {
+			return nil, err
+		}
+	}
+
+	var records []CityRecord
+	seen := make(map[string]bool)
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		field := func(i int) string {
+			if i < 0 || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		rec := CityRecord{
+			City:    field(idx[0]),
+			CityAlt: field(idx[1]),
+			Country: toUpper(field(idx[2])),
+			Region:  field(idx[3]),
+		}
+		if rec.City == "" {
+			return nil, fmt.Errorf("line %d: empty name", line)
+		}
+		if rec.Latitude, err = strconv.ParseFloat(field(idx[4]), 64); err != nil {
+			return nil, fmt.Errorf("line %d: latitude: %w", line, err)
+		}
+		if rec.Longitude, err = strconv.ParseFloat(field(idx[5]), 64); err != nil {
+			return nil, fmt.Errorf("line %d: longitude: %w", line, err)
+		}
+		if !validCoordinates(rec.Latitude, rec.Longitude) {
+			return nil, fmt.Errorf("line %d: coordinates out of range: %v, %v", line, rec.Latitude, rec.Longitude)
+		}
+		if p := field(idx[6]); p != "" {
+			pop, err := strconv.ParseInt(p, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: population: %w", line, err)
+			}
+			rec.Population = int32(pop)
+		}
+
+		key := fmt.Sprintf("%s\x00%s\x00%s\x00%.4f,%.4f", rec.City, rec.Country, rec.Region, rec.Latitude, rec.Longitude)
+		if !seen[key] {
+			seen[key] = true
+			records = append(records, rec)
+		}
+	}
+}
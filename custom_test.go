@@ -0,0 +1,69 @@
+package geobed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadCustomCities(t *testing.T) {
+	const csvData = `id,label,lat,lon,cc,state,headcount
+1,Acme HQ,30.2672,-97.7431,us,TX,1200
+2,"Acme Lab, North",30.4,-97.7,US,TX,
+3,Acme HQ,30.26721,-97.74309,US,TX,1200
+`
+	records, err := LoadCustomCities(strings.NewReader(csvData), ColumnMapping{
+		Comma: ',', City: "label", Latitude: "lat", Longitude: "lon", Country: "cc", Region: "state", Population: "headcount",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (duplicate dropped): %+v", len(records), records)
+	}
+	if want := (CityRecord{City: "Acme HQ", Country: "US", Region: "TX", Latitude: 30.2672, Longitude: -97.7431, Population: 1200}); records[0] != want {
+		t.Errorf("records[0] = %+v, want %+v", records[0], want)
+	}
+	if records[1].City != "Acme Lab, North" {
+		t.Errorf("records[1].City = %q", records[1].City)
+	}
+
+	g, err := NewGeobedFromRecords(records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := g.Geocode("Acme HQ"); c.City != "Acme HQ" || c.Country() != "US" {
+		t.Errorf("Geocode(Acme HQ) = %s, %s", c.City, c.Country())
+	}
+}
+
+func TestLoadCustomCities_NoHeader(t *testing.T) {
+	records, err := LoadCustomCities(strings.NewReader("Springfield\t39.8\t-89.64\n"), ColumnMapping{
+		NoHeader: true, City: "1", Latitude: "2", Longitude: "3",
+	})
+	if err != nil || len(records) != 1 || records[0].City != "Springfield" {
+		t.Errorf("LoadCustomCities() = %+v, %v", records, err)
+	}
+}
+
+func TestLoadCustomCities_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping ColumnMapping
+		wantErr string
+	}{
+		{"missing required", "name\tlat\tlng\n", ColumnMapping{City: "name", Latitude: "lat"}, "required"},
+		{"unknown column", "name\tlat\tlng\n", ColumnMapping{City: "name", Latitude: "lat", Longitude: "lon"}, `no column "lon"`},
+		{"bad latitude", "name\tlat\tlng\nA\t1\t2\nB\tx\t2\n", ColumnMapping{City: "name", Latitude: "lat", Longitude: "lng"}, "line 3: latitude"},
+		{"out of range", "name\tlat\tlng\nA\t91\t2\n", ColumnMapping{City: "name", Latitude: "lat", Longitude: "lng"}, "line 2: coordinates"},
+		{"bad column number", "A\t1\t2\n", ColumnMapping{NoHeader: true, City: "0", Latitude: "2", Longitude: "3"}, "1-based"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCustomCities(strings.NewReader(tt.data), tt.mapping)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadCustomCities() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package geobed
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Dataset names a Geonames cities dump. The dumps differ in their
+// population threshold: smaller ones load faster and use less memory,
+// larger ones also know small towns and villages.
+type Dataset string
+
+const (
+	DatasetCities500   Dataset = "cities500"   // Population > 500, or seat of an admin division
+	DatasetCities1000  Dataset = "cities1000"  // Population > 1000 (embedded)
+	DatasetCities5000  Dataset = "cities5000"  // Population > 5000
+	DatasetCities15000 Dataset = "cities15000" // Population > 15000, or capital
+)
+
+// WithDataset loads cities from the given Geonames dump instead of the
+// embedded cities1000 data. Other datasets are not embedded: the first
+// NewGeobed downloads the dump into the data directory (see WithOffline)
+// and every NewGeobed parses it, as the cache and warm-start snapshots only
+// hold the embedded dataset.
+func WithDataset(d Dataset) Option {
+	return func(c *GeobedConfig) {
+		c.Dataset = d
+	}
+}
+
+// validate reports an error for names other than the known dumps.
+func (d Dataset) validate() error {
+	switch d {
+	case "", DatasetCities500, DatasetCities1000, DatasetCities5000, DatasetCities15000:
+		return nil
+	}
+	return fmt.Errorf("dataset: unknown Geonames dataset %q", string(d))
+}
+
+// embedded reports whether d is the dataset the embedded cache is built from.
+func (d Dataset) embedded() bool {
+	return d == "" || d == DatasetCities1000
+}
+
+// WithDatasetDate pins the Geonames snapshot of the given date
+// ("2024-06-01"), downloaded from the archive set with WithArchiveURL, so
+// that research pipelines can reproduce results on a fixed data version.
+// Geonames itself only publishes the latest files. The snapshot is kept in
+// a subdirectory of the data directory named after the date, parsed on
+// every NewGeobed like other non-embedded datasets, and reported by
+// DatasetDate and in result metadata.
+func WithDatasetDate(date string) Option {
+	return func(c *GeobedConfig) {
+		c.DatasetDate = date
+	}
+}
+
+// WithArchiveURL sets the URL layout of dated Geonames snapshots for
+// WithDatasetDate. "{date}" is replaced with the date and "{file}" with the
+// file name, e.g. "https://mirror.example.org/geonames/{date}/{file}".
+func WithArchiveURL(layout string) Option {
+	return func(c *GeobedConfig) {
+		c.ArchiveURL = layout
+	}
+}
+
+// validateDatasetDate checks the pinned date and archive layout and moves
+// the data directory to the snapshot's subdirectory.
+func (c *GeobedConfig) validateDatasetDate() error {
+	if c.DatasetDate == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.DateOnly, c.DatasetDate); err != nil {
+		return fmt.Errorf("dataset date: %w", err)
+	}
+	if !strings.Contains(c.ArchiveURL, "{file}") {
+		return fmt.Errorf("dataset date: archive URL %q has no {file} placeholder (see WithArchiveURL)", c.ArchiveURL)
+	}
+	c.DataDir = filepath.Join(c.DataDir, c.DatasetDate)
+	return nil
+}
+
+// embeddedData reports whether the configured data is that of the embedded
+// cache, which neither another dataset nor a pinned snapshot is.
+func (g *GeoBed) embeddedData() bool {
+	return g.config.Dataset.embedded() && g.config.DatasetDate == ""
+}
+
+// dataSources returns dataSetFiles with the cities dump replaced by the
+// configured dataset and, for a pinned date, the URLs pointing into the
+// archive.
+func (g *GeoBed) dataSources() []DataSource {
+	if g.embeddedData() {
+		return dataSetFiles
+	}
+	sources := make([]DataSource, len(dataSetFiles))
+	copy(sources, dataSetFiles)
+	for i, f := range sources {
+		if f.ID == DataSourceGeonamesCities && !g.config.Dataset.embedded() {
+			file := string(g.config.Dataset) + ".zip"
+			sources[i].URL = strings.TrimSuffix(f.URL, path.Base(f.URL)) + file
+			sources[i].Path = strings.TrimSuffix(f.Path, path.Base(f.Path)) + file
+		}
+		if g.config.DatasetDate != "" {
+			sources[i].URL = strings.NewReplacer("{date}", g.config.DatasetDate, "{file}", path.Base(sources[i].Path)).Replace(g.config.ArchiveURL)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,155 @@
+package geobed
+
+import (
+	"archive/zip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDataSources(t *testing.T) {
+	g := &GeoBed{config: &GeobedConfig{Dataset: DatasetCities15000}}
+	var found bool
+	for _, f := range g.dataSources() {
+		if f.ID != DataSourceGeonamesCities {
+			continue
+		}
+		found = true
+		if f.URL != "https://download.geonames.org/export/dump/cities15000.zip" {
+			t.Errorf("cities URL = %q", f.URL)
+		}
+		if filepath.Base(f.Path) != "cities15000.zip" {
+			t.Errorf("cities path = %q", f.Path)
+		}
+	}
+	if !found {
+		t.Fatal("dataSources() has no cities source")
+	}
+	if dataSetFiles[0].URL != "https://download.geonames.org/export/dump/cities1000.zip" {
+		t.Errorf("dataSources() modified dataSetFiles: %q", dataSetFiles[0].URL)
+	}
+}
+
+func TestNewGeobed_UnknownDataset(t *testing.T) {
+	if _, err := NewGeobed(WithDataset("cities42")); err == nil {
+		t.Error("NewGeobed(WithDataset(cities42)) succeeded, want error")
+	}
+}
+
+func TestNewGeobed_OfflineMissingDataset(t *testing.T) {
+	_, err := NewGeobed(WithDataset(DatasetCities5000), WithOffline(), WithDataDir(t.TempDir()))
+	if !errors.Is(err, ErrDownloadFailed) || !errors.Is(err, ErrCacheMissing) {
+		t.Errorf("NewGeobed() error = %v, want ErrDownloadFailed and ErrCacheMissing", err)
+	}
+}
+
+func TestNewGeobed_OfflineDataset(t *testing.T) {
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), testCityRows)
+
+	cacheDir := t.TempDir()
+	g, err := NewGeobed(WithDataset(DatasetCities15000), WithOffline(), WithDataDir(dir), WithCacheDir(cacheDir))
+	if err != nil {
+		t.Fatalf("NewGeobed() error = %v", err)
+	}
+	if len(g.Cities) != len(testCityRows) {
+		t.Errorf("loaded %d cities, want %d", len(g.Cities), len(testCityRows))
+	}
+	if c := g.Geocode("Paris"); c.Country() != "FR" {
+		t.Errorf("Geocode(Paris) = %s, %s; want FR", c.City, c.Country())
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+		t.Errorf("cache written for a non-embedded dataset: %v", entries)
+	}
+}
+
+// testCityRows are Geonames cities dump lines for writeCitiesZip.
+var testCityRows = []string{
+	"2643743\tLondon\tLondon\t\t51.50853\t-0.12574\tP\tPPLC\tGB\t\tENG\tGLA\t\t\t8961989\t\t25\tEurope/London\t2023-01-01",
+	"2988507\tParis\tParis\t\t48.85341\t2.3488\tP\tPPLC\tFR\t\t11\t75\t751\t75056\t2138551\t\t42\tEurope/Paris\t2023-01-01",
+}
+
+// copyMetadataFiles copies the country and admin1 files from ./geobed-data
+// into dir.
+func copyMetadataFiles(t *testing.T, dir string) {
+	t.Helper()
+	for _, name := range []string{"countryInfo.txt", "admin1CodesASCII.txt"} {
+		b, err := os.ReadFile(filepath.Join("geobed-data", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeCitiesZip writes rows as a Geonames cities dump zip archive.
+func writeCitiesZip(t *testing.T, path string, rows []string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create(strings.TrimSuffix(filepath.Base(path), ".zip") + ".txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(strings.Join(rows, "\n") + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewGeobed_DatasetDate(t *testing.T) {
+	archive := t.TempDir()
+	snapshot := filepath.Join(archive, "2024-06-01")
+	if err := os.Mkdir(snapshot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	copyMetadataFiles(t, snapshot)
+	writeCitiesZip(t, filepath.Join(snapshot, "cities1000.zip"), testCityRows)
+	srv := httptest.NewServer(http.FileServer(http.Dir(archive)))
+	defer srv.Close()
+
+	dataDir := t.TempDir()
+	g, err := NewGeobed(WithDatasetDate("2024-06-01"), WithArchiveURL(srv.URL+"/{date}/{file}"), WithDataDir(dataDir), WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewGeobed() error = %v", err)
+	}
+	want := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	if !g.DatasetDate().Equal(want) {
+		t.Errorf("DatasetDate() = %v, want %v", g.DatasetDate(), want)
+	}
+	if len(g.Cities) != len(testCityRows) {
+		t.Errorf("loaded %d cities, want %d", len(g.Cities), len(testCityRows))
+	}
+	if r := g.GeocodeDetailed("London"); r.City.Country() != "GB" || !r.DatasetDate.Equal(want) {
+		t.Errorf("GeocodeDetailed(London) = %s, %v; want GB from %v", r.City.Country(), r.DatasetDate, want)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "2024-06-01", "cities1000.zip")); err != nil {
+		t.Errorf("snapshot not kept in a dated subdirectory: %v", err)
+	}
+
+	for _, opts := range [][]Option{
+		{WithDatasetDate("June 2024"), WithArchiveURL(srv.URL + "/{date}/{file}")},
+		{WithDatasetDate("2024-06-01")},
+	} {
+		if _, err := NewGeobed(append(opts, WithDataDir(t.TempDir()))...); err == nil {
+			t.Error("NewGeobed() with an invalid date or archive succeeded")
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build geobed_debug
+
+package geobed
+
+// debugBuild enables internal consistency panics, such as on interner
+// overflow, that otherwise surface as errors. Build with -tags geobed_debug
+// to get a stack trace at the point of failure.
+const debugBuild = true
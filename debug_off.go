@@ -0,0 +1,6 @@
+//go:build !geobed_debug
+
+package geobed
+
+// debugBuild is false in normal builds; see debug.go.
+const debugBuild = false
@@ -0,0 +1,80 @@
+package geobed
+
+import "strings"
+
+// WithDedupe drops near-duplicate records from the lists returned by
+// LargestCities, CitiesInRegion, MajorCitiesNear, Suggest, and GeocodeAll:
+// a city is left out when an earlier (higher-ranked) result has the same
+// name, case-insensitively, and country and lies within km kilometres. Geonames
+// sometimes lists one town twice a few hundred metres apart; 1-2 km removes
+// those without merging distinct towns. km <= 0 keeps every record (the
+// default).
+func WithDedupe(km float64) Option {
+	return func(c *GeobedConfig) {
+		c.DedupeKm = km
+	}
+}
+
+// DedupeCities returns cities without near-duplicates as described for
+// WithDedupe, keeping the first of each group in the given order.
+func DedupeCities(cities []GeobedCity, km float64) []GeobedCity {
+	d := newDeduper(km)
+	out := make([]GeobedCity, 0, len(cities))
+	for _, c := range cities {
+		if d.keep(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// dedupeKey groups cities that may duplicate each other.
+type dedupeKey struct {
+	country uint16
+	name    string
+}
+
+// deduper remembers kept cities and reports whether another one is new.
+type deduper struct {
+	km   float64
+	kept map[dedupeKey][]GeobedCity
+}
+
+// newDeduper returns a deduper for the threshold km, or nil (keeping
+// everything) when km <= 0.
+func newDeduper(km float64) *deduper {
+	if km <= 0 {
+		return nil
+	}
+	return &deduper{km: km, kept: make(map[dedupeKey][]GeobedCity)}
+}
+
+// keep reports whether c is not a near-duplicate of a city kept before,
+// and remembers it if so. A nil deduper keeps every city.
+func (d *deduper) keep(c GeobedCity) bool {
+	if d == nil {
+		return true
+	}
+	k := dedupeKey{c.country, toLower(c.City)}
+	for _, prev := range d.kept[k] {
+		if nearDuplicate(prev, c, d.km) {
+			return false
+		}
+	}
+	d.kept[k] = append(d.kept[k], c)
+	return true
+}
+
+// nearDuplicate reports whether a and b share name and country and lie
+// within km of each other.
+func nearDuplicate(a, b GeobedCity, km float64) bool {
+	return a.country == b.country && strings.EqualFold(a.City, b.City) && cityDistanceKm(a, b) <= km
+}
+
+// deduper returns a deduper for the configured WithDedupe threshold.
+func (g *GeoBed) deduper() *deduper {
+	if g.config == nil {
+		return nil
+	}
+	return newDeduper(g.config.DedupeKm)
+}
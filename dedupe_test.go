@@ -0,0 +1,55 @@
+package geobed
+
+import "testing"
+
+// dedupeRecords holds a town listed twice 300 m apart and a distinct town
+// of the same name.
+var dedupeRecords = []CityRecord{
+	{City: "Springfield", Country: "US", Region: "IL", Latitude: 39.80172, Longitude: -89.64371, Population: 116_000},
+	{City: "springfield", Country: "US", Region: "IL", Latitude: 39.80442, Longitude: -89.64371, Population: 115_000},
+	{City: "Springfield", Country: "US", Region: "MO", Latitude: 37.21533, Longitude: -93.29824, Population: 169_000},
+}
+
+func TestDedupeCities(t *testing.T) {
+	g, err := NewGeobedFromRecords(dedupeRecords, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := DedupeCities(g.Cities, 1); len(got) != 2 {
+		t.Errorf("DedupeCities(1 km) kept %d cities, want 2: %v", len(got), got)
+	}
+	if got := DedupeCities(g.Cities, 0); len(got) != 3 {
+		t.Errorf("DedupeCities(0) kept %d cities, want all 3", len(got))
+	}
+}
+
+func TestWithDedupe(t *testing.T) {
+	for _, tt := range []struct {
+		km   float64
+		want int
+	}{{0, 3}, {1, 2}} {
+		g, err := NewGeobedFromRecords(dedupeRecords, nil, WithDedupe(tt.km))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := g.LargestCities("US", 10); len(got) != tt.want {
+			t.Errorf("WithDedupe(%v): LargestCities() = %d cities, want %d", tt.km, len(got), tt.want)
+		}
+		if got := g.CitiesInRegion("US", "IL"); len(got) != tt.want-1 {
+			t.Errorf("WithDedupe(%v): CitiesInRegion(US, IL) = %d cities, want %d", tt.km, len(got), tt.want-1)
+		}
+		if got := g.Suggest("springf", SuggestOptions{}); len(got) != tt.want {
+			t.Errorf("WithDedupe(%v): Suggest() = %d cities, want %d", tt.km, len(got), tt.want)
+		}
+		if got := g.GeocodeAll("Springfield"); len(got) != tt.want {
+			t.Errorf("WithDedupe(%v): GeocodeAll() = %d cities, want %d", tt.km, len(got), tt.want)
+		}
+		got := g.MajorCitiesNear(39.8, -89.6, 10, 0)
+		if len(got) != tt.want {
+			t.Errorf("WithDedupe(%v): MajorCitiesNear() = %d cities, want %d", tt.km, len(got), tt.want)
+		}
+		if got[0].City.Population != 116_000 {
+			t.Errorf("WithDedupe(%v): MajorCitiesNear()[0] = %+v, want the more populous record", tt.km, got[0])
+		}
+	}
+}
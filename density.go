@@ -0,0 +1,107 @@
+package geobed
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/golang/geo/s2"
+)
+
+// CellDensity is the number and total population of the loaded cities in
+// one S2 cell.
+type CellDensity struct {
+	Cell       s2.CellID
+	Cities     int
+	Population int64
+}
+
+// Density aggregates the loaded cities by their S2 cell at level (0-30),
+// in increasing cell ID order, for dashboards about the dataset's
+// composition. Write the result with WriteDensityCSV or
+// WriteDensityGeoJSON.
+func (g *GeoBed) Density(level int) ([]CellDensity, error) {
+	counts, err := g.cellCounts(level)
+	if err != nil {
+		return nil, err
+	}
+	cells := make([]CellDensity, 0, len(counts))
+	for cell, st := range counts {
+		cells = append(cells, CellDensity{Cell: cell, Cities: st.cities, Population: st.population})
+	}
+	slices.SortFunc(cells, func(a, b CellDensity) int {
+		return cmp.Compare(a.Cell, b.Cell)
+	})
+	return cells, nil
+}
+
+// WriteDensityCSV writes cells as CSV with a header row and the columns
+// cell (S2 token), level, lat, lng (cell center), cities, and population.
+func WriteDensityCSV(w io.Writer, cells []CellDensity) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"cell", "level", "lat", "lng", "cities", "population"}); err != nil {
+		return err
+	}
+	for _, d := range cells {
+		center := d.Cell.LatLng()
+		if err := cw.Write([]string{
+			d.Cell.ToToken(),
+			strconv.Itoa(d.Cell.Level()),
+			strconv.FormatFloat(center.Lat.Degrees(), 'f', 6, 64),
+			strconv.FormatFloat(center.Lng.Degrees(), 'f', 6, 64),
+			strconv.Itoa(d.Cities),
+			strconv.FormatInt(d.Population, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// densityFeature is one GeoJSON feature written by WriteDensityGeoJSON.
+type densityFeature struct {
+	Type       string `json:"type"`
+	Properties struct {
+		Cell       string `json:"cell"`
+		Level      int    `json:"level"`
+		Cities     int    `json:"cities"`
+		Population int64  `json:"population"`
+	} `json:"properties"`
+	Geometry struct {
+		Type        string         `json:"type"`
+		Coordinates [][][2]float64 `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// WriteDensityGeoJSON writes cells as a GeoJSON FeatureCollection with one
+// Polygon feature per cell, its four vertices joined by straight edges,
+// and the properties cell (S2 token), level, cities, and population.
+func WriteDensityGeoJSON(w io.Writer, cells []CellDensity) error {
+	features := make([]densityFeature, len(cells))
+	for i, d := range cells {
+		f := &features[i]
+		f.Type = "Feature"
+		f.Properties.Cell = d.Cell.ToToken()
+		f.Properties.Level = d.Cell.Level()
+		f.Properties.Cities = d.Cities
+		f.Properties.Population = d.Population
+
+		cell := s2.CellFromCellID(d.Cell)
+		ring := make([][2]float64, 0, 5)
+		for k := 0; k < 4; k++ {
+			ll := s2.LatLngFromPoint(cell.Vertex(k))
+			ring = append(ring, [2]float64{ll.Lng.Degrees(), ll.Lat.Degrees()})
+		}
+		ring = append(ring, ring[0])
+		f.Geometry.Type = "Polygon"
+		f.Geometry.Coordinates = [][][2]float64{ring}
+	}
+	return json.NewEncoder(w).Encode(struct {
+		Type     string           `json:"type"`
+		Features []densityFeature `json:"features"`
+	}{"FeatureCollection", features})
+}
@@ -0,0 +1,71 @@
+package geobed
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+)
+
+func TestDensity(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Paris", Country: "FR", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+		{City: "Boulogne-Billancourt", Country: "FR", Latitude: 48.83545, Longitude: 2.24128, Population: 121334},
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cells, err := g.Density(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != 2 {
+		t.Fatalf("Density(6) = %d cells, want 2", len(cells))
+	}
+	var total int64
+	for _, d := range cells {
+		total += d.Population
+		if d.Cities == 2 && d.Population != 2138551+121334 {
+			t.Errorf("Paris cell population = %d", d.Population)
+		}
+	}
+	if total != 2138551+121334+961855 {
+		t.Errorf("total population = %d", total)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDensityCSV(&buf, cells); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "cell" || rows[1][1] != "6" {
+		t.Errorf("CSV = %q", rows)
+	}
+
+	buf.Reset()
+	if err := WriteDensityGeoJSON(&buf, cells); err != nil {
+		t.Fatal(err)
+	}
+	var fc struct {
+		Type     string
+		Features []densityFeature
+	}
+	if err := json.Unmarshal(buf.Bytes(), &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 2 {
+		t.Fatalf("GeoJSON = %s", buf.String())
+	}
+	ring := fc.Features[0].Geometry.Coordinates[0]
+	if len(ring) != 5 || ring[0] != ring[4] {
+		t.Errorf("cell ring = %v, want 4 vertices closed", ring)
+	}
+	if fc.Features[0].Properties.Cell != cells[0].Cell.ToToken() {
+		t.Errorf("feature cell = %q, want %q", fc.Features[0].Properties.Cell, cells[0].Cell.ToToken())
+	}
+}
@@ -0,0 +1,32 @@
+package geobed
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultDir picks the default location of the data ("data") or cache
+// ("cache") directory. In order of preference:
+//
+//  1. the directory named by the environment variable env;
+//  2. legacy ("./geobed-data" or "./geobed-cache") when it already exists,
+//     so checkouts and deployments that ship the directories keep working;
+//  3. geobed/<sub> under os.UserCacheDir (e.g. ~/.cache/geobed/data);
+//  4. geobed/<sub> under os.TempDir when there is no user cache directory.
+//
+// The directories are not created here; downloads and cache regeneration
+// create them on first write, so binaries run from read-only locations never
+// need the working directory to be writable.
+func defaultDir(env, legacy, sub string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	if fi, err := os.Stat(legacy); err == nil && fi.IsDir() {
+		return legacy
+	}
+	base, err := os.UserCacheDir()
+	if err != nil || base == "" {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "geobed", sub)
+}
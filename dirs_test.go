@@ -0,0 +1,80 @@
+package geobed
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDefaultDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("relies on XDG_CACHE_HOME, which os.UserCacheDir only honours on Linux")
+	}
+	xdg := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdg)
+	t.Setenv(envCacheDir, "")
+	t.Chdir(t.TempDir())
+
+	if got, want := defaultDir(envCacheDir, "./geobed-cache", "cache"), filepath.Join(xdg, "geobed", "cache"); got != want {
+		t.Errorf("defaultDir() without legacy dir = %q, want %q", got, want)
+	}
+
+	if err := os.Mkdir("geobed-cache", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := defaultDir(envCacheDir, "./geobed-cache", "cache"); got != "./geobed-cache" {
+		t.Errorf("defaultDir() with legacy dir = %q, want ./geobed-cache", got)
+	}
+
+	t.Setenv(envCacheDir, "/srv/geobed")
+	if got := defaultDir(envCacheDir, "./geobed-cache", "cache"); got != "/srv/geobed" {
+		t.Errorf("defaultDir() with %s set = %q, want /srv/geobed", envCacheDir, got)
+	}
+}
+
+func TestDefaultConfig_Overrides(t *testing.T) {
+	t.Setenv(envDataDir, "/env/data")
+	t.Setenv(envCacheDir, "/env/cache")
+
+	cfg := defaultConfig()
+	if cfg.DataDir != "/env/data" || cfg.CacheDir != "/env/cache" {
+		t.Errorf("defaultConfig() dirs = %q, %q, want the environment values", cfg.DataDir, cfg.CacheDir)
+	}
+
+	for _, opt := range []Option{WithDataDir("/opt/data"), WithCacheDir("/opt/cache")} {
+		opt(cfg)
+	}
+	if cfg.DataDir != "/opt/data" || cfg.CacheDir != "/opt/cache" {
+		t.Errorf("options did not override environment: %q, %q", cfg.DataDir, cfg.CacheDir)
+	}
+}
+
+func TestCacheDirEnv_RoundTrip(t *testing.T) {
+	// A cache stored into GEOBED_CACHE_DIR must be the one a later
+	// NewGeobed reads, not the embedded copy.
+	t.Setenv(envCacheDir, t.TempDir())
+	t.Chdir(t.TempDir())
+
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+		{City: "Round Rock", Country: "US", Region: "TX", Latitude: 30.50826, Longitude: -97.6789, Population: 119468},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.store(); err != nil {
+		t.Fatalf("store() error: %v", err)
+	}
+
+	g2, err := NewGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g2.Cities) != len(g.Cities) {
+		t.Fatalf("NewGeobed() loaded %d cities, want the %d stored in %s", len(g2.Cities), len(g.Cities), envCacheDir)
+	}
+	if c := g2.Geocode("Round Rock, TX"); c.City != "Round Rock" {
+		t.Errorf("Geocode(Round Rock, TX) = %q, want Round Rock", c.City)
+	}
+}
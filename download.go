@@ -0,0 +1,159 @@
+package geobed
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultDownloadTimeout bounds each download attempt unless
+// WithDownloadTimeout overrides it. Generous enough for the larger Geonames
+// dumps on slow links.
+const defaultDownloadTimeout = 10 * time.Minute
+
+// downloadRetryDelay is the wait before the first retry; it doubles for
+// each further attempt. A variable so tests can shorten it.
+var downloadRetryDelay = time.Second
+
+// WithHTTPClient downloads data files with client instead of the shared
+// default client, e.g. to configure proxies, TLS roots, or client
+// certificates. Set timeouts with WithDownloadTimeout rather than
+// client.Timeout, which would also cut off slow but progressing downloads.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *GeobedConfig) {
+		c.HTTPClient = client
+	}
+}
+
+// WithDownloadTimeout limits each attempt to download a data file to d.
+// The default is 10 minutes.
+func WithDownloadTimeout(d time.Duration) Option {
+	return func(c *GeobedConfig) {
+		c.DownloadTimeout = d
+	}
+}
+
+// WithDownloadRetries retries a failed download up to n more times, waiting
+// one second before the first retry and twice as long before each next one.
+// Only network errors, timeouts, and 429 or 5xx responses are retried.
+func WithDownloadRetries(n int) Option {
+	return func(c *GeobedConfig) {
+		c.DownloadRetries = n
+	}
+}
+
+// WithOffline never downloads data files. When the cache is unusable,
+// NewGeobed then loads the raw data files already in the data directory and
+// fails with ErrDownloadFailed if any is missing, instead of reaching out to
+// Geonames. Useful in sandboxes and air-gapped deployments.
+func WithOffline() Option {
+	return func(c *GeobedConfig) {
+		c.Offline = true
+	}
+}
+
+// DownloadDataSets downloads the raw data files missing from the data
+// directory, without parsing them or touching the cache. With
+// RegenerateCache it lets mirrored environments fetch on a host with
+// network access and build the cache elsewhere. Files already present are
+// kept; remove them to fetch fresh copies.
+func DownloadDataSets(opts ...Option) error {
+	g, err := newGeobed(opts)
+	if err != nil {
+		return err
+	}
+	return g.downloadDataSets()
+}
+
+// downloadStatusError is an unsuccessful HTTP response to a download.
+type downloadStatusError struct {
+	code int
+}
+
+func (e *downloadStatusError) Error() string {
+	return fmt.Sprintf("status %d", e.code)
+}
+
+// download fetches url into path with the configured client, timeout, and
+// retries.
+func (g *GeoBed) download(url, path string) error {
+	client, timeout := g.downloadClient(), g.downloadTimeout()
+	delay := downloadRetryDelay
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := downloadFile(ctx, client, url, path, g.progressReporter(ProgressDownload, filepath.Base(path)))
+		cancel()
+		if err == nil || attempt >= g.config.DownloadRetries || !retryableDownload(err) {
+			return err
+		}
+		log.Printf("info: retrying download in %s: %v", delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// downloadClient returns the client set with WithHTTPClient, or the shared
+// default client.
+func (g *GeoBed) downloadClient() *http.Client {
+	if g.config.HTTPClient != nil {
+		return g.config.HTTPClient
+	}
+	return httpClient
+}
+
+// downloadTimeout returns the limit for each download attempt.
+func (g *GeoBed) downloadTimeout() time.Duration {
+	if g.config.DownloadTimeout > 0 {
+		return g.config.DownloadTimeout
+	}
+	return defaultDownloadTimeout
+}
+
+// retryableDownload reports whether a failed download may succeed if tried
+// again.
+func retryableDownload(err error) bool {
+	if errors.Is(err, ErrDataDirUnwritable) {
+		return false
+	}
+	var status *downloadStatusError
+	if errors.As(err, &status) {
+		return status.code == http.StatusTooManyRequests || status.code >= 500
+	}
+	return errors.Is(err, ErrDownloadFailed)
+}
+
+// acceptEncoding lists the transfer encodings decodeContent handles.
+const acceptEncoding = "gzip, deflate"
+
+// compressibleDownload reports whether url names a file worth requesting
+// compressed: the plain-text Geonames files, not the zip archives. Setting
+// Accept-Encoding ourselves also covers clients whose transport has
+// compression disabled, but stops net/http from decoding the response.
+func compressibleDownload(url string) bool {
+	return !strings.HasSuffix(strings.ToLower(path.Base(url)), ".zip")
+}
+
+// decodeContent returns a reader that undoes the response's
+// Content-Encoding while streaming it to disk.
+func decodeContent(r io.Reader, encoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return r, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(r)
+	case "deflate":
+		// HTTP's "deflate" is the zlib format (RFC 9110, section 8.4.1.2).
+		return zlib.NewReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
@@ -0,0 +1,187 @@
+package geobed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownload_Retries(t *testing.T) {
+	defer func(d time.Duration) { downloadRetryDelay = d }(downloadRetryDelay)
+	downloadRetryDelay = time.Millisecond
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case calls.Add(1) < 3:
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("data"))
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cities1000.zip")
+	g := &GeoBed{config: &GeobedConfig{DownloadRetries: 1}}
+	if err := g.download(srv.URL, path); !errors.Is(err, ErrDownloadFailed) {
+		t.Errorf("download() with 1 retry error = %v, want ErrDownloadFailed", err)
+	}
+
+	calls.Store(0)
+	g.config.DownloadRetries = 2
+	if err := g.download(srv.URL, path); err != nil {
+		t.Fatalf("download() with 2 retries error = %v", err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "data" || calls.Load() != 3 {
+		t.Errorf("downloaded %q in %d requests, want data in 3", b, calls.Load())
+	}
+
+	calls.Store(0)
+	if err := g.download(srv.URL+"/missing", path); !errors.Is(err, ErrDownloadFailed) || calls.Load() != 0 {
+		t.Errorf("download(404) error = %v after %d counted requests; want one unretried failure", err, calls.Load())
+	}
+}
+
+func TestDownload_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	g := &GeoBed{config: &GeobedConfig{DownloadTimeout: 50 * time.Millisecond}}
+	start := time.Now()
+	err := g.download(srv.URL, filepath.Join(t.TempDir(), "f"))
+	if !errors.Is(err, ErrDownloadFailed) || time.Since(start) > 3*time.Second {
+		t.Errorf("download() = %v after %s, want ErrDownloadFailed after the 50ms timeout", err, time.Since(start))
+	}
+}
+
+func TestDownload_HTTPClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "f")
+	g := &GeoBed{config: &GeobedConfig{}}
+	if err := g.download(srv.URL, path); !errors.Is(err, ErrDownloadFailed) {
+		t.Errorf("download() with the default client = %v, want a certificate failure", err)
+	}
+	WithHTTPClient(srv.Client())(g.config)
+	if err := g.download(srv.URL, path); err != nil {
+		t.Errorf("download() with the server's client = %v", err)
+	}
+}
+
+func TestDownload_Interrupted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("truncated"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cities1000.zip")
+	g := &GeoBed{config: &GeobedConfig{}}
+	if err := g.download(srv.URL, path); !errors.Is(err, ErrDownloadFailed) {
+		t.Errorf("download() of a truncated body error = %v, want ErrDownloadFailed", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("interrupted download left %d files, want none", len(entries))
+	}
+}
+
+func TestRemovePartialDownloads(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cities1000.zip.part", "countryInfo.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removePartialDownloads(dir)
+	if _, err := os.Stat(filepath.Join(dir, "cities1000.zip.part")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("orphaned .part file not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "countryInfo.txt")); err != nil {
+		t.Errorf("complete download removed: %v", err)
+	}
+}
+
+func TestDownload_Compressed(t *testing.T) {
+	const body = "AD\tAND\t020\tAN\tAndorra\n"
+	var zipEncoding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cities1000.zip" {
+			zipEncoding = r.Header.Get("Accept-Encoding")
+			w.Write([]byte(body))
+			return
+		}
+		var buf bytes.Buffer
+		var zw io.WriteCloser
+		switch r.URL.Path {
+		case "/gzip.txt":
+			zw = gzip.NewWriter(&buf)
+			w.Header().Set("Content-Encoding", "gzip")
+		case "/deflate.txt":
+			zw = zlib.NewWriter(&buf)
+			w.Header().Set("Content-Encoding", "deflate")
+		}
+		zw.Write([]byte(body))
+		zw.Close()
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	// Compression disabled, as in some proxies' clients, would otherwise
+	// keep net/http from asking for it.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	g := &GeoBed{config: &GeobedConfig{HTTPClient: client}}
+	for _, name := range []string{"gzip.txt", "deflate.txt", "cities1000.zip"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := g.download(srv.URL+"/"+name, path); err != nil {
+			t.Fatalf("download(%s) error = %v", name, err)
+		}
+		if b, _ := os.ReadFile(path); string(b) != body {
+			t.Errorf("download(%s) wrote %q, want the decoded body", name, b)
+		}
+	}
+	if zipEncoding != "" {
+		t.Errorf("zip download sent Accept-Encoding %q, want none", zipEncoding)
+	}
+}
+
+func TestDownloadDataSets(t *testing.T) {
+	archive := t.TempDir()
+	snapshot := filepath.Join(archive, "2024-06-01")
+	if err := os.Mkdir(snapshot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	copyMetadataFiles(t, snapshot)
+	writeCitiesZip(t, filepath.Join(snapshot, "cities1000.zip"), testCityRows)
+	srv := httptest.NewServer(http.FileServer(http.Dir(archive)))
+	defer srv.Close()
+
+	dataDir := t.TempDir()
+	if err := DownloadDataSets(WithDatasetDate("2024-06-01"), WithArchiveURL(srv.URL+"/{date}/{file}"), WithDataDir(dataDir)); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range dataSetFiles {
+		if _, err := os.Stat(filepath.Join(dataDir, "2024-06-01", filepath.Base(f.Path))); err != nil {
+			t.Errorf("%s not downloaded: %v", f.ID, err)
+		}
+	}
+}
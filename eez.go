@@ -0,0 +1,170 @@
+package geobed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// WithEEZFile loads Exclusive Economic Zones so that remote points (see
+// ReverseGeocodeResult.Remote) are attributed to the country whose waters
+// they lie in; see ReverseGeocodeResult.Waters.
+//
+// Unlike the city data, EEZ boundaries are not embedded: Geonames, which
+// the embedded cache is built from, has none, and bundling a third-party
+// boundary set is a licensing and size decision not yet taken. Until one
+// is shipped, maritime attribution needs a file supplied by the caller;
+// without it Waters is never set.
+//
+// The file is GeoJSON: a FeatureCollection of Polygon or MultiPolygon
+// features, as exported by Marine Regions (https://www.marineregions.org).
+// Each feature names its country in an ISO 3166 alpha-2 or alpha-3
+// property, the first of ISO_TER1, ISO_SOV1, and iso_a2 that is set, and
+// its zone in GEONAME or name. Features of unknown countries are skipped.
+// Zones crossing the antimeridian must be split there into separate
+// polygons, as Marine Regions does; a file with an unsplit zone is
+// rejected, since planar point-in-polygon tests would give wrong answers.
+// Simplify the boundaries before loading; lookups test every zone whose
+// bounding box contains the point.
+func WithEEZFile(path string) Option {
+	return func(c *GeobedConfig) {
+		c.EEZFile = path
+	}
+}
+
+// eezZone is one loaded Exclusive Economic Zone.
+type eezZone struct {
+	name     string
+	country  int          // Index in Countries
+	polygons [][][]lngLat // Polygons, each an outer ring followed by holes
+
+	minLat, maxLat, minLng, maxLng float64 // Bounding box of the outer rings
+}
+
+// lngLat is a GeoJSON position.
+type lngLat [2]float64
+
+// eezFeatureCollection is the subset of GeoJSON read by loadEEZFile.
+type eezFeatureCollection struct {
+	Features []struct {
+		Properties map[string]any `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// loadEEZFile reads the zones of a GeoJSON file. Countries must be loaded.
+func (g *GeoBed) loadEEZFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var fc eezFeatureCollection
+	if err := json.Unmarshal(b, &fc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i, f := range fc.Features {
+		country, ok := -1, false
+		for _, key := range []string{"ISO_TER1", "ISO_SOV1", "iso_a2"} {
+			if code, _ := f.Properties[key].(string); code != "" {
+				country, ok = g.countryISO[toUpper(code)]
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		z := eezZone{country: country}
+		for _, key := range []string{"GEONAME", "name"} {
+			if name, _ := f.Properties[key].(string); name != "" {
+				z.name = name
+				break
+			}
+		}
+
+		switch f.Geometry.Type {
+		case "Polygon":
+			var p [][]lngLat
+			err = json.Unmarshal(f.Geometry.Coordinates, &p)
+			z.polygons = [][][]lngLat{p}
+		case "MultiPolygon":
+			err = json.Unmarshal(f.Geometry.Coordinates, &z.polygons)
+		default:
+			err = fmt.Errorf("unsupported geometry %q", f.Geometry.Type)
+		}
+		if err == nil && crossesAntimeridian(z.polygons) {
+			err = errors.New("zone crosses the antimeridian; split it at ±180°")
+		}
+		if err != nil {
+			return fmt.Errorf("%s: feature %d: %w", path, i, err)
+		}
+
+		z.minLat, z.maxLat = math.Inf(1), math.Inf(-1)
+		z.minLng, z.maxLng = math.Inf(1), math.Inf(-1)
+		for _, p := range z.polygons {
+			if len(p) == 0 {
+				continue
+			}
+			for _, pt := range p[0] {
+				z.minLng, z.maxLng = min(z.minLng, pt[0]), max(z.maxLng, pt[0])
+				z.minLat, z.maxLat = min(z.minLat, pt[1]), max(z.maxLat, pt[1])
+			}
+		}
+		g.eez = append(g.eez, z)
+	}
+	return nil
+}
+
+// eezAt returns the zone containing lat/lng. Where zones overlap, as
+// disputed ones do, the first in the file wins.
+func (g *GeoBed) eezAt(lat, lng float64) (eezZone, bool) {
+	for _, z := range g.eez {
+		if lat < z.minLat || lat > z.maxLat || lng < z.minLng || lng > z.maxLng {
+			continue
+		}
+		for _, p := range z.polygons {
+			if polygonContains(p, lat, lng) {
+				return z, true
+			}
+		}
+	}
+	return eezZone{}, false
+}
+
+// crossesAntimeridian reports whether any ring of the polygons has an edge
+// spanning more than 180° of longitude, which in planar coordinates means
+// it wraps around the antimeridian.
+func crossesAntimeridian(polygons [][][]lngLat) bool {
+	for _, p := range polygons {
+		for _, ring := range p {
+			for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+				if math.Abs(ring[i][0]-ring[j][0]) > 180 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// polygonContains reports whether lat/lng lies inside the polygon, an outer
+// ring followed by holes, by the even-odd rule on planar coordinates. Zones
+// crossing the antimeridian must be split in two, as Marine Regions does;
+// loadEEZFile rejects those that are not.
+func polygonContains(rings [][]lngLat, lat, lng float64) bool {
+	inside := false
+	for _, ring := range rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[i], ring[j]
+			if (a[1] > lat) != (b[1] > lat) && lng < (b[0]-a[0])*(lat-a[1])/(b[1]-a[1])+a[0] {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
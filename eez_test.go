@@ -0,0 +1,106 @@
+package geobed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolygonContains(t *testing.T) {
+	square := [][]lngLat{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}, // hole
+	}
+	for _, tt := range []struct {
+		lat, lng float64
+		want     bool
+	}{
+		{1, 1, true},
+		{5, 5, false},
+		{5, 8, true},
+		{11, 5, false},
+		{-1, -1, false},
+	} {
+		if got := polygonContains(square, tt.lat, tt.lng); got != tt.want {
+			t.Errorf("polygonContains(%v, %v) = %v, want %v", tt.lat, tt.lng, got, tt.want)
+		}
+	}
+}
+
+func TestEEZFile_Antimeridian(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{{City: "Suva", Country: "FJ", Latitude: -18.14161, Longitude: 178.44149}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(coords string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "eez.geojson")
+		geojson := `{"type": "FeatureCollection", "features": [{"type": "Feature", "properties": {"GEONAME": "Fiji", "ISO_TER1": "FJI"},
+			"geometry": {"type": "MultiPolygon", "coordinates": ` + coords + `}}]}`
+		if err := os.WriteFile(path, []byte(geojson), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	unsplit := write(`[[[[175, -22], [-177, -22], [-177, -12], [175, -12], [175, -22]]]]`)
+	if err := g.loadEEZFile(unsplit); err == nil || !strings.Contains(err.Error(), "antimeridian") {
+		t.Errorf("loadEEZFile with an unsplit zone: err = %v, want antimeridian error", err)
+	}
+
+	split := write(`[[[[175, -22], [180, -22], [180, -12], [175, -12], [175, -22]]],
+		[[[-180, -22], [-177, -22], [-177, -12], [-180, -12], [-180, -22]]]]`)
+	if err := g.loadEEZFile(split); err != nil {
+		t.Fatal(err)
+	}
+	for _, lng := range []float64{178, -179} {
+		if z, ok := g.eezAt(-17, lng); !ok || z.name != "Fiji" {
+			t.Errorf("eezAt(-17, %v) = %q, %v; want Fiji", lng, z.name, ok)
+		}
+	}
+}
+
+func TestEEZFile(t *testing.T) {
+	const geojson = `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"GEONAME": "Test Zone (Azores)", "ISO_TER1": "PRT"},
+		 "geometry": {"type": "MultiPolygon", "coordinates": [[[[-45, 25], [-35, 25], [-35, 35], [-45, 35], [-45, 25]]]]}},
+		{"type": "Feature", "properties": {"name": "Nowhere", "iso_a2": "ZZ"},
+		 "geometry": {"type": "Polygon", "coordinates": [[[-30, -40], [-20, -40], [-20, -20], [-30, -20], [-30, -40]]]}}
+	]}`
+	path := filepath.Join(t.TempDir(), "eez.geojson")
+	if err := os.WriteFile(path, []byte(geojson), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records := []CityRecord{
+		{City: "Paris", Country: "FR", Region: "11", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+		{City: "Lisbon", Country: "PT", Region: "14", Latitude: 38.71667, Longitude: -9.13333, Population: 517802},
+	}
+	g, err := NewGeobedFromRecords(records, nil, WithEEZFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.eez) != 1 {
+		t.Fatalf("loaded %d zones, want 1 (unknown countries skipped)", len(g.eez))
+	}
+
+	r := g.ReverseGeocodeDetailed(30, -40)
+	if !r.Remote || !r.Marine || r.Waters.ISO != "PT" || r.Zone != "Test Zone (Azores)" {
+		t.Errorf("ReverseGeocodeDetailed(30, -40) = Remote %v, Marine %v, Waters %q, Zone %q; want PT waters", r.Remote, r.Marine, r.Waters.ISO, r.Zone)
+	}
+	if r := g.ReverseGeocodeDetailed(-30, -25); !r.Remote || r.Marine {
+		t.Errorf("ReverseGeocodeDetailed(-30, -25) = Remote %v, Marine %v; want remote outside any zone", r.Remote, r.Marine)
+	}
+	if r := g.ReverseGeocodeDetailed(48.8566, 2.3522); r.Marine {
+		t.Error("ReverseGeocodeDetailed(Paris) reported Marine")
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.geojson")
+	if err := os.WriteFile(bad, []byte(strings.Replace(geojson, "MultiPolygon", "LineString", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGeobedFromRecords(records, nil, WithEEZFile(bad)); err == nil {
+		t.Error("NewGeobedFromRecords with a LineString zone succeeded, want error")
+	}
+}
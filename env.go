@@ -0,0 +1,34 @@
+package geobed
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by defaultConfig, so deployments such as
+// containers can configure geobed without changes to the embedding
+// application. Options passed to NewGeobed take precedence over them.
+const (
+	envDataDir  = "GEOBED_DATA_DIR"  // like WithDataDir
+	envCacheDir = "GEOBED_CACHE_DIR" // like WithCacheDir
+	envOffline  = "GEOBED_OFFLINE"   // a strconv.ParseBool value; like WithOffline
+	envDataset  = "GEOBED_DATASET"   // a Dataset name; like WithDataset
+)
+
+// applyEnv sets the fields of c that have no directory default from the
+// environment. Unparseable GEOBED_OFFLINE values are logged and ignored;
+// unknown dataset names are left for NewGeobed to reject.
+func applyEnv(c *GeobedConfig) {
+	if v := os.Getenv(envOffline); v != "" {
+		offline, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Printf("warning: ignoring %s=%q: %v", envOffline, v, err)
+		} else {
+			c.Offline = offline
+		}
+	}
+	if v := os.Getenv(envDataset); v != "" {
+		c.Dataset = Dataset(v)
+	}
+}
@@ -0,0 +1,27 @@
+package geobed
+
+import "testing"
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv(envOffline, "true")
+	t.Setenv(envDataset, "cities5000")
+
+	var c GeobedConfig
+	applyEnv(&c)
+	if !c.Offline || c.Dataset != DatasetCities5000 {
+		t.Errorf("applyEnv() = Offline %v, Dataset %q; want true, cities5000", c.Offline, c.Dataset)
+	}
+
+	// Options still win over the environment.
+	WithDataset(DatasetCities15000)(&c)
+	if c.Dataset != DatasetCities15000 {
+		t.Errorf("WithDataset after applyEnv: Dataset = %q, want cities15000", c.Dataset)
+	}
+
+	t.Setenv(envOffline, "maybe")
+	c = GeobedConfig{}
+	applyEnv(&c)
+	if c.Offline {
+		t.Error("applyEnv() with unparseable GEOBED_OFFLINE set Offline")
+	}
+}
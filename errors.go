@@ -0,0 +1,48 @@
+package geobed
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by NewGeobed and the loading paths.
+// Use errors.Is to branch on the failure cause:
+//
+//	g, err := geobed.NewGeobed()
+//	if errors.Is(err, geobed.ErrDownloadFailed) {
+//	    // offline and no usable cache: retry later
+//	}
+//
+// A single error may match several sentinels; for example a failed cold
+// start wraps both the cache problem that triggered the download and the
+// download failure itself.
+var (
+	// ErrCacheMissing indicates a cache file was found neither on disk nor
+	// in the embedded data.
+	ErrCacheMissing = errors.New("geobed: cache missing")
+
+	// ErrCacheCorrupt indicates a cache file exists but could not be decoded
+	// or contained no cities.
+	ErrCacheCorrupt = errors.New("geobed: cache corrupt")
+
+	// ErrDownloadFailed indicates a raw data file could not be fetched.
+	ErrDownloadFailed = errors.New("geobed: download failed")
+
+	// ErrDataDirUnwritable indicates the data directory could not be created
+	// or a downloaded file could not be written into it.
+	ErrDataDirUnwritable = errors.New("geobed: data directory not writable")
+
+	// ErrSchemaMismatch indicates a raw data file does not have the layout
+	// the parsers expect, e.g. because Geonames changed its export format.
+	ErrSchemaMismatch = errors.New("geobed: unexpected data file schema")
+
+	// ErrInvalidConfig indicates an option value was rejected before any
+	// data was loaded; the error is a *ConfigError naming the field.
+	ErrInvalidConfig = errors.New("geobed: invalid configuration")
+
+	// ErrTooManyCodes indicates the loaded cities use more distinct country
+	// or region codes than a city can reference, which points to a corrupt
+	// or hostile cache or custom dataset.
+	ErrTooManyCodes = errors.New("geobed: too many distinct country or region codes")
+)
+
+// ErrEmptyQuery is returned by ParseLocation when the query is blank after
+// preprocessing.
+var ErrEmptyQuery = errors.New("geobed: empty query")
@@ -0,0 +1,74 @@
+package geobed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestErrors_DownloadFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := downloadFile(context.Background(), httpClient, srv.URL, filepath.Join(t.TempDir(), "cities1000.zip"), nil)
+	if !errors.Is(err, ErrDownloadFailed) {
+		t.Errorf("downloadFile() error = %v, want ErrDownloadFailed", err)
+	}
+}
+
+func TestErrors_DataDirUnwritable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data"))
+	}))
+	defer srv.Close()
+
+	// Parent directory does not exist, so the file cannot be created.
+	path := filepath.Join(t.TempDir(), "missing", "cities1000.zip")
+	if err := downloadFile(context.Background(), httpClient, srv.URL, path, nil); !errors.Is(err, ErrDataDirUnwritable) {
+		t.Errorf("downloadFile() error = %v, want ErrDataDirUnwritable", err)
+	}
+
+	// A regular file where the data directory should be makes MkdirAll fail.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	g := &GeoBed{config: &GeobedConfig{DataDir: filepath.Join(blocker, "data")}}
+	if err := g.downloadDataSets(); !errors.Is(err, ErrDataDirUnwritable) {
+		t.Errorf("downloadDataSets() error = %v, want ErrDataDirUnwritable", err)
+	}
+}
+
+func TestErrors_CacheMissing(t *testing.T) {
+	_, _, err := openOptionallyBzippedFile(t.TempDir(), "does-not-exist.dmp")
+	if !errors.Is(err, ErrCacheMissing) {
+		t.Errorf("openOptionallyBzippedFile() error = %v, want ErrCacheMissing", err)
+	}
+}
+
+func TestErrors_CacheCorrupt(t *testing.T) {
+	// The loaders read CacheDir before the embedded copy, so garbage files
+	// there shadow the embedded cache.
+	cacheDir := t.TempDir()
+	for _, name := range cacheFileNames {
+		if err := os.WriteFile(filepath.Join(cacheDir, name), []byte("not a gob stream"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := loadGeobedCityData(cacheDir); !errors.Is(err, ErrCacheCorrupt) {
+		t.Errorf("loadGeobedCityData() error = %v, want ErrCacheCorrupt", err)
+	}
+	if _, err := loadGeobedCountryData(cacheDir); !errors.Is(err, ErrCacheCorrupt) {
+		t.Errorf("loadGeobedCountryData() error = %v, want ErrCacheCorrupt", err)
+	}
+	if _, err := loadNameIndex(cacheDir); !errors.Is(err, ErrCacheCorrupt) {
+		t.Errorf("loadNameIndex() error = %v, want ErrCacheCorrupt", err)
+	}
+}
@@ -0,0 +1,165 @@
+package geobed
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EvalCase is one labeled query from a gold dataset.
+type EvalCase struct {
+	Query     string // Raw query passed to Geocode
+	GeonameID int32  // Expected Geonames ID of the top result
+}
+
+// EvalFailure records a case whose top result was not the expected city.
+type EvalFailure struct {
+	Case  EvalCase
+	Got   GeobedCity // Top result (zero value when nothing matched)
+	GotID int32      // Geonames ID of Got, 0 when unknown
+}
+
+// EvalReport summarizes an accuracy evaluation run.
+type EvalReport struct {
+	Total    int           // Number of cases evaluated
+	Correct  int           // Cases whose top result had the expected ID
+	NoResult int           // Cases for which Geocode returned nothing
+	Failures []EvalFailure // Incorrect cases, in dataset order
+}
+
+// PrecisionAt1 returns the fraction of cases whose top result was correct.
+func (r EvalReport) PrecisionAt1() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Total)
+}
+
+// ReadEvalCases parses a gold dataset. Each line holds a query and the
+// expected Geonames ID separated by a tab; further columns are ignored so
+// datasets can carry notes. Blank lines and lines starting with '#' are
+// skipped.
+func ReadEvalCases(r io.Reader) ([]EvalCase, error) {
+	var cases []EvalCase
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: want query<TAB>geonameID, got %q", lineNo, line)
+		}
+		id, err := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid geonameID %q: %w", lineNo, fields[1], err)
+		}
+		cases = append(cases, EvalCase{Query: fields[0], GeonameID: int32(id)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading eval cases: %w", err)
+	}
+	return cases, nil
+}
+
+// Evaluate runs every case through Geocode and reports precision@1, so
+// scoring changes can be measured against a labeled dataset.
+//
+// Results are identified by Geonames ID. Caches generated before IDs were
+// recorded carry none, in which case IDs are recovered by matching results
+// against the raw Geonames dump in the configured data directory.
+func (g *GeoBed) Evaluate(cases []EvalCase, opts ...GeocodeOptions) (EvalReport, error) {
+	resolve, err := g.geonameIDResolver()
+	if err != nil {
+		return EvalReport{}, err
+	}
+
+	var r EvalReport
+	for _, tc := range cases {
+		r.Total++
+		got := g.Geocode(tc.Query, opts...)
+		if got.City == "" {
+			r.NoResult++
+			r.Failures = append(r.Failures, EvalFailure{Case: tc})
+			continue
+		}
+		gotID := resolve(got)
+		if gotID == tc.GeonameID {
+			r.Correct++
+			continue
+		}
+		r.Failures = append(r.Failures, EvalFailure{Case: tc, Got: got, GotID: gotID})
+	}
+	return r, nil
+}
+
+// evalCityKey identifies a city record by the fields stored in the cache.
+type evalCityKey struct {
+	city, country string
+	lat, lng      float32
+}
+
+// geonameIDResolver returns a function mapping a result to its Geonames ID.
+func (g *GeoBed) geonameIDResolver() (func(GeobedCity) int32, error) {
+	for _, c := range g.Cities {
+		if c.geonameID != 0 {
+			return GeobedCity.GeonameID, nil
+		}
+	}
+
+	var path string
+	for _, f := range g.dataSources() {
+		if f.ID == DataSourceGeonamesCities {
+			path = filepath.Join(g.config.DataDir, filepath.Base(f.Path))
+		}
+	}
+	ids, err := readGeonamesIDs(path)
+	if err != nil {
+		return nil, fmt.Errorf("cache has no geoname IDs and raw data is unavailable: %w", err)
+	}
+	return func(c GeobedCity) int32 {
+		return ids[evalCityKey{c.City, c.Country(), c.Latitude, c.Longitude}]
+	}, nil
+}
+
+// readGeonamesIDs indexes the Geonames IDs in a raw cities dump by city key.
+func readGeonamesIDs(path string) (map[evalCityKey]int32, error) {
+	rz, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening zip file: %w", err)
+	}
+	defer rz.Close()
+
+	ids := make(map[evalCityKey]int32)
+	for _, uF := range rz.File {
+		if err := readGeonamesIDsFromEntry(uF, ids); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// readGeonamesIDsFromEntry reads a single zip entry into ids.
+// Extracted to avoid defer-in-loop anti-pattern.
+func readGeonamesIDsFromEntry(uF *zip.File, ids map[evalCityKey]int32) error {
+	fi, err := uF.Open()
+	if err != nil {
+		return fmt.Errorf("opening file in zip: %w", err)
+	}
+	defer fi.Close()
+
+	scanner := bufio.NewScanner(fi)
+	for scanner.Scan() {
+		if gc, ok := parseGeonamesCityLine(scanner.Text()); ok {
+			ids[evalCityKey{gc.City, gc.Country, gc.Latitude, gc.Longitude}] = gc.GeonameID
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,79 @@
+package geobed
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadEvalCases(t *testing.T) {
+	input := "# comment\n\nAustin, TX\t4671654\tnote\nParis\t2988507\n"
+	cases, err := ReadEvalCases(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadEvalCases error: %v", err)
+	}
+	want := []EvalCase{{"Austin, TX", 4671654}, {"Paris", 2988507}}
+	if len(cases) != len(want) {
+		t.Fatalf("got %d cases, want %d", len(cases), len(want))
+	}
+	for i := range want {
+		if cases[i] != want[i] {
+			t.Errorf("case %d = %+v, want %+v", i, cases[i], want[i])
+		}
+	}
+}
+
+func TestReadEvalCases_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing ID column", "Austin, TX\n"},
+		{"non-numeric ID", "Austin, TX\tabc\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadEvalCases(strings.NewReader(tt.input)); err == nil {
+				t.Error("ReadEvalCases error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestEvalReport_PrecisionAt1(t *testing.T) {
+	if got := (EvalReport{}).PrecisionAt1(); got != 0 {
+		t.Errorf("empty report precision = %v, want 0", got)
+	}
+	if got := (EvalReport{Total: 4, Correct: 3}).PrecisionAt1(); got != 0.75 {
+		t.Errorf("precision = %v, want 0.75", got)
+	}
+}
+
+// TestEvaluate_GoldDataset runs the bundled gold dataset. Every case in it
+// is expected to pass; a failure here means a scoring change regressed a
+// well-known query.
+func TestEvaluate_GoldDataset(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open("testdata/gold.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cases, err := ReadEvalCases(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := g.Evaluate(cases)
+	if err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	t.Logf("precision@1 = %.3f (%d/%d)", report.PrecisionAt1(), report.Correct, report.Total)
+	for _, fl := range report.Failures {
+		t.Errorf("%q: want %d, got %d (%s, %s)", fl.Case.Query, fl.Case.GeonameID, fl.GotID, fl.Got.City, fl.Got.Country())
+	}
+}
@@ -0,0 +1,153 @@
+package geobed_test
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/andreiashu/geobed"
+)
+
+func ExampleNewGeobed() {
+	g, err := geobed.NewGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	city := g.Geocode("Austin, TX")
+	fmt.Printf("%s, %s, %s: %.4f, %.4f\n", city.City, city.Region(), city.Country(), city.Latitude, city.Longitude)
+	// Output:
+	// Austin, TX, US: 30.2672, -97.7431
+}
+
+func ExampleGeoBed_Geocode() {
+	g, err := geobed.GetDefaultGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, q := range []string{"Paris", "Paris, TX", "Paris, France", "Sydney NSW", "Toronto, ON"} {
+		c := g.Geocode(q)
+		fmt.Printf("%-14s -> %s, %s, %s\n", q, c.City, c.Region(), c.Country())
+	}
+	// Output:
+	// Paris          -> Paris, 11, FR
+	// Paris, TX      -> Paris, TX, US
+	// Paris, France  -> Paris, 11, FR
+	// Sydney NSW     -> Sydney, 02, AU
+	// Toronto, ON    -> Toronto, 08, CA
+}
+
+func ExampleGeoBed_Geocode_fuzzy() {
+	g, err := geobed.GetDefaultGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Typo tolerance is opt-in: FuzzyDistance is the maximum edit distance.
+	fmt.Println(g.Geocode("Berln", geobed.GeocodeOptions{FuzzyDistance: 1}).City)
+	fmt.Println(g.Geocode("Amsterdm", geobed.GeocodeOptions{FuzzyDistance: 2}).City)
+	// Output:
+	// Berlin
+	// Amsterdam
+}
+
+func ExampleGeoBed_Geocode_exactCity() {
+	g, err := geobed.GetDefaultGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// ExactCity only returns a city when the name is unambiguous, either on
+	// its own or together with the state or country given.
+	exact := geobed.GeocodeOptions{ExactCity: true}
+	fmt.Printf("%q\n", g.Geocode("Springfield", exact).City)
+	c := g.Geocode("Springfield, IL", exact)
+	fmt.Println(c.City, c.Region(), c.Country())
+	// Output:
+	// ""
+	// Springfield IL US
+}
+
+func ExampleGeoBed_ParseLocation() {
+	g, err := geobed.GetDefaultGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	loc, err := g.ParseLocation("Austin, TX 78701")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(loc.City, loc.Admin, loc.Country, loc.PostalCode)
+	// Output:
+	// [Austin] TX US 78701
+}
+
+func ExampleGeoBed_ReverseGeocode() {
+	g, err := geobed.GetDefaultGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	c := g.ReverseGeocode(48.8566, 2.3522)
+	fmt.Printf("%s, %s\n", c.City, c.Country())
+
+	// Small districts resolve to the surrounding metro city by default.
+	fmt.Println(g.ReverseGeocode(52.5200, 13.4050).City)
+	fmt.Println(g.ReverseGeocode(52.5200, 13.4050, geobed.ReverseGeocodeOptions{IncludeNeighborhoods: true}).City)
+	// Output:
+	// Paris, FR
+	// Berlin
+	// Mitte
+}
+
+func ExampleGeoBed_ReverseGeocodeDetailed() {
+	g, err := geobed.GetDefaultGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := g.ReverseGeocodeDetailed(40.6782, -73.9442)
+	fmt.Printf("%s (%s)\n", r.Locality.City, r.Metro.City)
+	// Output:
+	// Bushwick (New York City)
+}
+
+func ExampleWithQueryCache() {
+	g, err := geobed.NewGeobed(geobed.WithQueryCache(1024))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		g.Geocode("Austin, TX")
+	}
+	st := g.QueryCacheStats()
+	fmt.Printf("hits=%d misses=%d\n", st.Hits, st.Misses)
+	// Output:
+	// hits=2 misses=1
+}
+
+// A single GeoBed is safe for concurrent use, so batches can be split
+// across goroutines without copying the dataset.
+func Example_batch() {
+	g, err := geobed.GetDefaultGeobed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	queries := []string{"Tokyo", "Berlin", "Nairobi", "Lima", "Austin, TX"}
+	results := make([]geobed.GeobedCity, len(queries))
+
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = g.Geocode(q)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range results {
+		fmt.Printf("%s -> %s, %s\n", queries[i], c.City, c.Country())
+	}
+	// Output:
+	// Tokyo -> Tokyo, JP
+	// Berlin -> Berlin, DE
+	// Nairobi -> Nairobi, KE
+	// Lima -> Lima, PE
+	// Austin, TX -> Austin, US
+}
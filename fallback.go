@@ -0,0 +1,52 @@
+package geobed
+
+import (
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// FallbackLevel selects how coarse a location ReverseGeocodeDetailed reports
+// for points no city is within range of.
+type FallbackLevel int
+
+const (
+	FallbackNone      FallbackLevel = iota // Empty result (default)
+	FallbackCountry                        // Country of the nearest city within fallbackCountryKm
+	FallbackContinent                      // As FallbackCountry, then continent of the nearest city within fallbackContinentKm
+)
+
+// Reach of the fallbacks. Geobed embeds no border polygons; the nearest
+// city's country stands in for the country containing the point, which is
+// coarse near borders but right for the deserts, ice and mountains that
+// have no city within the usual ~100km.
+const (
+	fallbackCountryKm   = 500
+	fallbackContinentKm = 2000
+)
+
+// fallbackLocation fills r.Fallback, r.Country and r.Continent for a point
+// no city was found for, up to the given level. Marine results and, beyond
+// the continent reach, the open ocean are left empty.
+func (g *GeoBed) fallbackLocation(r *ReverseGeocodeResult, lat, lng float64, level FallbackLevel) {
+	if level == FallbackNone || r.Marine ||
+		math.IsNaN(lat) || math.IsNaN(lng) || math.IsInf(lat, 0) || math.IsInf(lng, 0) {
+		return
+	}
+	ll := s2.LatLngFromDegrees(lat, lng)
+	c := g.nearestCity(ll, 0)
+	if c.City == "" {
+		return
+	}
+	co, ok := g.countryInfo(c.Country())
+	if !ok {
+		return
+	}
+	km := distanceKm(ll, cityLatLng(c))
+	switch {
+	case km <= fallbackCountryKm:
+		r.Fallback, r.Country, r.Continent = FallbackCountry, co, co.Continent
+	case km <= fallbackContinentKm && level >= FallbackContinent:
+		r.Fallback, r.Continent = FallbackContinent, co.Continent
+	}
+}
@@ -0,0 +1,36 @@
+package geobed
+
+import "testing"
+
+func TestReverseGeocodeDetailed_Fallback(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Tamanrasset", Country: "DZ", Region: "53", Latitude: 22.785, Longitude: 5.52278, Population: 73128},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		lat, lng  float64
+		level     FallbackLevel
+		want      FallbackLevel
+		country   string
+		continent string
+	}{
+		{"in range", 22.8, 5.5, FallbackContinent, FallbackNone, "", ""},
+		{"no fallback", 25, 3, FallbackNone, FallbackNone, "", ""},
+		{"country", 25, 3, FallbackCountry, FallbackCountry, "DZ", "AF"},
+		{"country within continent", 25, 3, FallbackContinent, FallbackCountry, "DZ", "AF"},
+		{"beyond country reach", 15, 10, FallbackCountry, FallbackNone, "", ""},
+		{"continent", 15, 10, FallbackContinent, FallbackContinent, "", "AF"},
+		{"open ocean", -30, -25, FallbackContinent, FallbackNone, "", ""},
+	}
+	for _, tt := range tests {
+		r := g.ReverseGeocodeDetailed(tt.lat, tt.lng, ReverseGeocodeOptions{FallbackLevel: tt.level})
+		if r.Fallback != tt.want || r.Country.ISO != tt.country || r.Continent != tt.continent {
+			t.Errorf("%s: Fallback %v, Country %q, Continent %q; want %v, %q, %q",
+				tt.name, r.Fallback, r.Country.ISO, r.Continent, tt.want, tt.country, tt.continent)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package geobed
+
+// maxFastPathLen bounds the queries taken by exactFastPath so the lowercased
+// name-index key fits a stack buffer.
+const maxFastPathLen = 64
+
+// exactFastPath answers the common query that fuzzyMatchLocation would
+// resolve to a single candidate, without building candidate sets or score
+// maps: one ASCII word of four or more letters ("Reykjavik") that is not a
+// country name and whose name-index bucket holds exactly one city. Such a
+// query has no abbreviation, state, or country part to parse, so that city
+// is the only candidate and scores positively. The key is lowercased into
+// a stack buffer, so the lookup does not allocate.
+//
+// Custom normalizers, rankers, and score traces need the full path and
+// disable it, as do exact-city, fuzzy, and country options.
+func (g *GeoBed) exactFastPath(n string, opts GeocodeOptions) (GeobedCity, bool) {
+	if len(n) < 4 || len(n) > maxFastPathLen || opts.ExactCity || opts.FuzzyDistance > 0 || opts.Country != "" {
+		return GeobedCity{}, false
+	}
+	if cfg := g.config; cfg != nil && (cfg.Normalizer != nil || cfg.Ranker != nil || cfg.ScoreTrace != nil) {
+		return GeobedCity{}, false
+	}
+
+	var buf [maxFastPathLen]byte
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		switch {
+		case 'a' <= c && c <= 'z':
+		case 'A' <= c && c <= 'Z':
+			c += 'a' - 'A'
+		default:
+			return GeobedCity{}, false
+		}
+		buf[i] = c
+	}
+	key := buf[:len(n)]
+
+	// "Singapore" is both; the full path adds the country to the query.
+	if _, ok := g.countryKeys[string(key)]; ok {
+		return GeobedCity{}, false
+	}
+	indices := g.nameIndex[string(key)]
+	if len(indices) != 1 {
+		return GeobedCity{}, false
+	}
+	c := g.Cities[indices[0]]
+	if g.config != nil && g.config.ResultFilter != nil && !g.config.ResultFilter(c) {
+		return GeobedCity{}, false
+	}
+	return c, true
+}
@@ -0,0 +1,77 @@
+package geobed
+
+import (
+	"strings"
+	"testing"
+)
+
+// fastPathQueries returns up to limit city names that exactFastPath answers.
+func fastPathQueries(g *GeoBed, limit int) []string {
+	var queries []string
+	for _, c := range g.Cities {
+		if _, ok := g.exactFastPath(c.City, GeocodeOptions{}); ok {
+			queries = append(queries, c.City)
+			if len(queries) == limit {
+				break
+			}
+		}
+	}
+	return queries
+}
+
+func TestExactFastPath(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := fastPathQueries(g, 500)
+	if len(queries) < 500 {
+		t.Fatalf("only %d fast-path queries in the dataset", len(queries))
+	}
+	queries = append(queries, strings.ToUpper(queries[0]), strings.ToLower(queries[1]))
+	for _, q := range queries {
+		fast, _ := g.exactFastPath(q, GeocodeOptions{})
+		if full := g.fuzzyMatchLocation(q, GeocodeOptions{}); full.City != fast {
+			t.Errorf("%q: fast path = %s, %s; full path = %s, %s", q, fast.City, fast.Country(), full.City.City, full.City.Country())
+		}
+	}
+
+	for _, q := range []string{"Austin", "Singapore", "Austin, TX", "New York", "Zürich", "Rome"} {
+		if _, ok := g.exactFastPath(q, GeocodeOptions{}); ok {
+			t.Errorf("exactFastPath(%q) taken, want full path", q)
+		}
+	}
+	if _, ok := g.exactFastPath(queries[0], GeocodeOptions{FuzzyDistance: 1}); ok {
+		t.Error("exactFastPath taken with FuzzyDistance set")
+	}
+
+	q := queries[0]
+	if allocs := testing.AllocsPerRun(100, func() { g.Geocode(q) }); allocs != 0 {
+		t.Errorf("Geocode(%q) allocates %v times per call, want 0", q, allocs)
+	}
+}
+
+func TestExactFastPath_ResultFilter(t *testing.T) {
+	cities := []CityRecord{{City: "Reykjavik", Country: "IS", Latitude: 64.1355, Longitude: -21.8954, Population: 118918}}
+	g, err := NewGeobedFromRecords(cities, nil, WithResultFilter(func(GeobedCity) bool { return false }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := g.Geocode("Reykjavik"); c.City != "" {
+		t.Errorf("Geocode(Reykjavik) with a rejecting filter = %q, want no match", c.City)
+	}
+}
+
+func BenchmarkGeocodeExactFastPath(b *testing.B) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		b.Fatal(err)
+	}
+	q := fastPathQueries(g, 1)[0]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		g.Geocode(q)
+	}
+}
@@ -0,0 +1,77 @@
+package geobed
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lockFileName is the lock file created in the data and cache directories.
+const lockFileName = ".geobed.lock"
+
+// lockDir takes an exclusive lock on dir that is shared by every process on
+// the host, so concurrent downloads or cache generation into the same
+// directory run one at a time; downloadMu only covers one process. It
+// blocks until the lock is free. On platforms without file locking the
+// lock only creates the lock file. dir must exist.
+func lockDir(dir string) (unlock func(), err error) {
+	f, err := os.OpenFile(filepath.Join(dir, lockFileName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking %s: %w", dir, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory that is renamed into place, so readers in other processes never
+// see a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// partSuffix marks a download in progress; downloadFile renames the file
+// once it is complete.
+const partSuffix = ".part"
+
+// removePartialDownloads deletes the .part files in dir. Deferred cleanup
+// in downloadFile does not run when the process is killed, e.g. by a
+// container runtime stopping a slow start, so this runs before each round
+// of downloads. The caller must hold the lock on dir: any .part file is
+// then left by a process that no longer downloads into it.
+func removePartialDownloads(dir string) {
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), partSuffix) || e.IsDir() {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		if err := os.Remove(p); err == nil {
+			log.Printf("info: removed partial download %s", p)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package geobed
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; only downloadMu then
+// serializes downloads, within one process.
+func lockFile(f *os.File) error { return nil }
+
+func unlockFile(f *os.File) error { return nil }
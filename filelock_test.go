@@ -0,0 +1,26 @@
+package geobed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "g.c.dmp")
+	for _, data := range []string{"old", "new"} {
+		if err := writeFileAtomic(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if b, _ := os.ReadFile(path); string(b) != "new" {
+		t.Errorf("content = %q, want new", b)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v, %v; want 0644", fi.Mode().Perm(), err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("%d files in dir, want only the target", len(entries))
+	}
+}
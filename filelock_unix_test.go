@@ -0,0 +1,44 @@
+//go:build unix
+
+package geobed
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockDir(t *testing.T) {
+	dir := t.TempDir()
+	unlock, err := lockDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second lock, as another process would take it, waits for the first.
+	acquired := make(chan func())
+	go func() {
+		u, err := lockDir(dir)
+		if err != nil {
+			t.Error(err)
+			u = func() {}
+		}
+		acquired <- u
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second lock acquired while the first was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case u := <-acquired:
+		u()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lock not acquired after unlock")
+	}
+
+	if _, err := lockDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("lockDir(missing dir) succeeded")
+	}
+}
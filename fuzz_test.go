@@ -0,0 +1,131 @@
+package geobed
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// Fuzz Tests
+//
+// Run the seed corpus as part of the normal test suite, or fuzz a single
+// target with e.g.:
+//
+//	go test -run='^$' -fuzz=FuzzGeocode -fuzztime=60s
+//
+// The targets only assert the absence of panics plus a few cheap invariants;
+// they are meant to catch crashes on malformed input (embedded NULs, huge
+// tokens, invalid UTF-8), not to check geocoding quality.
+// ============================================================================
+
+// fuzzQuerySeeds is the seed corpus shared by the query-level fuzz targets.
+var fuzzQuerySeeds = []string{
+	"Austin, TX",
+	"Paris, France",
+	"France, Paris",
+	"TX Austin",
+	"Toronto, ON",
+	"Sydney NSW",
+	"東京",
+	"São Paulo",
+	"",
+	",",
+	", ,",
+	"TX",
+	"a\x00b",
+	"\xff\xfe\xfd",
+	"Paris\x00, France",
+	"İstanbul, Türkiye",
+	"DenmarK",
+	strings.Repeat("a", 1000),
+	strings.Repeat("Paris, ", 100),
+}
+
+func FuzzGeocode(f *testing.F) {
+	g, err := sharedGeobed()
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, s := range fuzzQuerySeeds {
+		f.Add(s, false, 0)
+	}
+	f.Add("Austn, TX", false, 1)
+	f.Add("Londn", true, 2)
+
+	f.Fuzz(func(t *testing.T, query string, exact bool, dist int) {
+		if dist < 0 || dist > maxFuzzyDistance {
+			dist = 1
+		}
+		c := g.Geocode(query, GeocodeOptions{ExactCity: exact, FuzzyDistance: dist})
+		if c.City == "" {
+			return
+		}
+		if c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+			t.Errorf("Geocode(%q) returned out-of-range coordinates %v,%v", query, c.Latitude, c.Longitude)
+		}
+	})
+}
+
+func FuzzExtractLocationPieces(f *testing.F) {
+	g, err := sharedGeobed()
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, s := range fuzzQuerySeeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, query string) {
+		nCo, nSt, _, nSlice := g.extractLocationPieces(query)
+		if len(nSlice) == 0 {
+			t.Errorf("extractLocationPieces(%q) returned no name pieces", query)
+		}
+		if utf8.ValidString(query) && (!utf8.ValidString(nCo) || !utf8.ValidString(nSt)) {
+			t.Errorf("extractLocationPieces(%q) produced invalid UTF-8 codes %q/%q", query, nCo, nSt)
+		}
+	})
+}
+
+func FuzzParseGeonamesCityLine(f *testing.F) {
+	f.Add("4671654\tAustin\tAustin\tAustin,Ostin\t30.26715\t-97.74306\tP\tPPLA\tUS\t\tTX\t453\t\t\t961855\t149\t158\tAmerica/Chicago\t2019-07-03")
+	f.Add("1\tX\tX\t\tNaN\tInf\tP\tPPL\tUS\t\tTX\t\t\t\t0\t\t\t\t")
+	f.Add("1\tX\tX\t\t91\t181\tP\tPPL\tUS\t\tTX\t\t\t\t0\t\t\t\t")
+	f.Add("\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t")
+	f.Add("1\t\x00\t\t\t0\t0\tP\tPPL\t\xff\t\t\xfe\t\t\t\t99999999999999\t\t\t\t")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		c, ok := parseGeonamesCityLine(line)
+		if !ok {
+			return
+		}
+		if c.City == "" {
+			t.Errorf("parseGeonamesCityLine(%q) accepted a city with no name", line)
+		}
+		if !validCoordinates(float64(c.Latitude), float64(c.Longitude)) {
+			t.Errorf("parseGeonamesCityLine(%q) accepted invalid coordinates %v,%v", line, c.Latitude, c.Longitude)
+		}
+	})
+}
+
+func FuzzParseMaxMindCityFields(f *testing.F) {
+	f.Add("us,austin,Austin,TX,678368,30.2669444,-97.7427778")
+	f.Add("Country,City,AccentCity,Region,Population,Latitude,Longitude")
+	f.Add("us,x,(!),TX,,NaN,-Inf")
+	f.Add("0,,,,,,")
+	f.Add("\xff,\x00,\x00,\xfe,-1,90,180")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		c, ok := parseMaxMindCityFields(strings.Split(line, ","))
+		if !ok {
+			return
+		}
+		if c.City == "" || c.Country == "" {
+			t.Errorf("parseMaxMindCityFields(%q) accepted a city without name or country", line)
+		}
+		if !validCoordinates(float64(c.Latitude), float64(c.Longitude)) {
+			t.Errorf("parseMaxMindCityFields(%q) accepted invalid coordinates %v,%v", line, c.Latitude, c.Longitude)
+		}
+	})
+}
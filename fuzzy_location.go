@@ -0,0 +1,70 @@
+package geobed
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agnivade/levenshtein"
+)
+
+// minFuzzyLocationLen is the shortest trailing segment that is matched
+// fuzzily against state and country names. Shorter segments are codes
+// ("TX", "NSW"), where a single edit makes a different valid code.
+const minFuzzyLocationLen = 4
+
+// fuzzyLocationSuffix retries state and country extraction with typo
+// tolerance for queries like "Austin, Texsa" or "Paris, Frnace", where the
+// exact extraction in extractLocationPieces found nothing. Only the segment
+// after the last comma is considered, so multi-word city names such as
+// "Santa Maria" are never mistaken for a misspelled country ("Malta").
+//
+// A segment of n characters may differ by at most (n-1)/2 edits, capped at
+// maxDist. The closest state or country name wins; on equal distance
+// countries are preferred, matching the exact extraction order. It returns
+// the possibly updated country, state, and name tokens; the input slice is
+// never modified because it may be shared through the query cache.
+func (g *GeoBed) fuzzyLocationSuffix(nCo, nSt string, nSlice []string, maxDist int) (string, string, []string) {
+	cleaned := strings.Join(nSlice, " ")
+	comma := strings.LastIndexByte(cleaned, ',')
+	if comma < 0 {
+		return nCo, nSt, nSlice
+	}
+	rest := strings.Trim(cleaned[:comma], " ,")
+	segment := toLower(strings.TrimSpace(cleaned[comma+1:]))
+	segLen := utf8.RuneCountInString(segment)
+	if rest == "" || segLen < minFuzzyLocationLen {
+		return nCo, nSt, nSlice
+	}
+	limit := min(maxDist, (segLen-1)/2)
+
+	bestDist, bestCountry, bestState := limit+1, -1, -1
+	if nCo == "" {
+		for name, i := range g.countryKeys {
+			d := levenshtein.ComputeDistance(segment, name)
+			if d < bestDist || (d == bestDist && bestCountry >= 0 && i < bestCountry) {
+				bestDist, bestCountry = d, i
+			}
+		}
+	}
+	if nSt == "" && (nCo == "" || nCo == "US") {
+		for name, rank := range usStateNameKeys() {
+			d := levenshtein.ComputeDistance(segment, name)
+			if d < bestDist || (d == bestDist && bestCountry < 0 && bestState >= 0 && rank < bestState) {
+				bestDist, bestState, bestCountry = d, rank, -1
+			}
+		}
+	}
+
+	switch {
+	case bestCountry >= 0:
+		nCo = g.Countries[bestCountry].ISO
+	case bestState >= 0:
+		nSt = sortedUsStateCodes()[bestState]
+		if nCo == "" && !g.config.NoUSDefault {
+			nCo = "US"
+		}
+	default:
+		return nCo, nSt, nSlice
+	}
+	return nCo, nSt, strings.Split(rest, " ")
+}
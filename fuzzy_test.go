@@ -2,10 +2,11 @@ package geobed
 
 import (
 	"testing"
+	"time"
 )
 
 func TestFuzzyGeocode(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -35,7 +36,7 @@ func TestFuzzyGeocode(t *testing.T) {
 }
 
 func TestFuzzyGeocodeDistance2(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -64,7 +65,7 @@ func TestFuzzyGeocodeDistance2(t *testing.T) {
 }
 
 func TestFuzzyMatchDisabled(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -78,7 +79,7 @@ func TestFuzzyMatchDisabled(t *testing.T) {
 }
 
 func TestFuzzyMatchBackwardCompatibility(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -154,8 +155,53 @@ func TestFuzzyMatchFunction(t *testing.T) {
 	}
 }
 
-func BenchmarkFuzzyGeocode(b *testing.B) {
+func TestFuzzyGeocodeDeadline(t *testing.T) {
 	g, err := NewGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("expired deadline returns partial result", func(t *testing.T) {
+		r := g.GeocodeDetailed("Austn", GeocodeOptions{FuzzyDistance: 1, Deadline: time.Now().Add(-time.Second)})
+		if !r.Partial {
+			t.Error("Partial = false, want true for an already expired deadline")
+		}
+	})
+
+	t.Run("generous deadline completes", func(t *testing.T) {
+		r := g.GeocodeDetailed("Austn", GeocodeOptions{FuzzyDistance: 1, Deadline: time.Now().Add(time.Minute)})
+		if r.Partial {
+			t.Error("Partial = true, want false")
+		}
+		if r.City.City != "Austin" {
+			t.Errorf("City = %q, want Austin", r.City.City)
+		}
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		r := g.GeocodeDetailed("Austin, TX")
+		if r.Partial || r.City.City != "Austin" {
+			t.Errorf("GeocodeDetailed(Austin, TX) = %+v, want complete Austin", r)
+		}
+		if got := g.Geocode("Austin, TX"); got != r.City {
+			t.Errorf("Geocode and GeocodeDetailed disagree: %q vs %q", got.City, r.City.City)
+		}
+	})
+
+	t.Run("deadline while queued for a fuzzy slot", func(t *testing.T) {
+		g.fuzzySem = make(chan struct{}, 1)
+		defer func() { g.fuzzySem = nil }()
+		g.fuzzySem <- struct{}{}
+
+		r := g.GeocodeDetailed("Austn", GeocodeOptions{FuzzyDistance: 1, Deadline: time.Now().Add(20 * time.Millisecond)})
+		if !r.Partial {
+			t.Error("Partial = false, want true when the deadline expires while queued")
+		}
+	})
+}
+
+func BenchmarkFuzzyGeocode(b *testing.B) {
+	g, err := sharedGeobed()
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -178,3 +224,72 @@ func BenchmarkFuzzyGeocode(b *testing.B) {
 		}
 	})
 }
+
+func TestFuzzyLocationSuffix(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query       string
+		maxDist     int
+		wantCity    string
+		wantCountry string
+		wantRegion  string
+	}{
+		{"Austin, Texsa", 2, "Austin", "US", "TX"},     // Transposed state name
+		{"Paris, Texsa", 2, "Paris", "US", "TX"},       // Typo picks Paris, TX over Paris, FR
+		{"Austn, Texs", 1, "Austin", "US", "TX"},       // City and state both misspelled
+		{"Paris, Frnace", 2, "Paris", "FR", ""},        // Transposed country name
+		{"Portland, Oregn", 1, "Portland", "US", "OR"}, // Missing letter
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			result := g.Geocode(tt.query, GeocodeOptions{FuzzyDistance: tt.maxDist})
+			if result.City != tt.wantCity || result.Country() != tt.wantCountry ||
+				(tt.wantRegion != "" && result.Region() != tt.wantRegion) {
+				t.Errorf("Geocode(%q, fuzzy=%d) = %s, %s, %s; want %s, %s, %s",
+					tt.query, tt.maxDist, result.City, result.Region(), result.Country(),
+					tt.wantCity, tt.wantRegion, tt.wantCountry)
+			}
+		})
+	}
+
+	// Without typo tolerance the misspelled state is not recognised.
+	if r := g.Geocode("Paris, Texsa"); r.Region() == "TX" {
+		t.Errorf("Geocode(%q, fuzzy=0) = %s, %s; want no state match", "Paris, Texsa", r.City, r.Region())
+	}
+}
+
+func TestFuzzyLocationSuffix_Guards(t *testing.T) {
+	g := &GeoBed{}
+	g.Countries = []CountryInfo{{ISO: "MT", Country: "Malta"}, {ISO: "FR", Country: "France"}}
+	g.buildCountryKeys()
+
+	tests := []struct {
+		name    string
+		nSlice  []string
+		nCo     string
+		wantCo  string
+		wantSt  string
+		wantLen int
+	}{
+		{"no comma", []string{"Santa", "Maria"}, "", "", "", 2},
+		{"short segment", []string{"Austin,", "TZ"}, "", "", "", 2},
+		{"too many edits", []string{"Valletta,", "Mexico"}, "", "", "", 2},
+		{"nothing left", []string{",", "Frnace"}, "", "", "", 2},
+		{"state under foreign country", []string{"Lyon,", "Texsa"}, "FR", "FR", "", 2},
+		{"country typo", []string{"Lyon,", "Frnace"}, "", "FR", "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			co, st, names := g.fuzzyLocationSuffix(tt.nCo, "", tt.nSlice, 2)
+			if co != tt.wantCo || st != tt.wantSt || len(names) != tt.wantLen {
+				t.Errorf("fuzzyLocationSuffix(%q) = %q, %q, %q; want %q, %q, %d names",
+					tt.nSlice, co, st, names, tt.wantCo, tt.wantSt, tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package geobed
+
+// fuzzyScanKey identifies one fuzzy scan of the name index: a normalized
+// query token and the edit distance it was matched at.
+type fuzzyScanKey struct {
+	token    string
+	distance int
+}
+
+// WithFuzzyCache caches the city indices found by fuzzy name-index scans for
+// up to size distinct (token, distance) pairs. A scan compares the token
+// against every index key, so streams of user input that repeat the same
+// typos ("Pittsburg", "Cincinatti") save most of the cost of
+// GeocodeOptions.FuzzyDistance on hits. Scans cut short by a deadline are
+// not cached. Hit rates are reported by FuzzyCacheStats.
+func WithFuzzyCache(size int) Option {
+	return func(c *GeobedConfig) {
+		c.FuzzyCacheSize = size
+	}
+}
+
+// FuzzyCacheStats reports hit/miss counts of the fuzzy scan cache enabled by
+// WithFuzzyCache. All fields are zero when the cache is disabled.
+func (g *GeoBed) FuzzyCacheStats() CacheStats {
+	return g.fuzzyCache.stats()
+}
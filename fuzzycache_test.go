@@ -0,0 +1,37 @@
+package geobed
+
+import "testing"
+
+func TestFuzzyCache(t *testing.T) {
+	g, err := NewGeobedFromRecords(fixtureCities, nil, WithFuzzyCache(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := GeocodeOptions{FuzzyDistance: 1}
+
+	first := g.Geocode("Pittsburg", opts)
+	second := g.Geocode("Pittsburg", opts)
+	if first != second {
+		t.Errorf("cached Geocode(Pittsburg) = %q then %q", first.City, second.City)
+	}
+	if st := g.FuzzyCacheStats(); st.Hits != 1 || st.Misses != 1 || st.Entries != 1 {
+		t.Errorf("FuzzyCacheStats = %+v, want 1 hit, 1 miss, 1 entry", st)
+	}
+
+	// Another distance is another scan.
+	g.Geocode("Pittsburg", GeocodeOptions{FuzzyDistance: 2})
+	if st := g.FuzzyCacheStats(); st.Misses != 2 {
+		t.Errorf("FuzzyCacheStats misses = %d after a new distance, want 2", st.Misses)
+	}
+
+	uncached, err := NewGeobedFromRecords(fixtureCities, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := uncached.Geocode("Pittsburg", opts); c != first {
+		t.Errorf("Geocode(Pittsburg) without cache = %q, with cache %q", c.City, first.City)
+	}
+	if st := uncached.FuzzyCacheStats(); st.Capacity != 0 {
+		t.Errorf("FuzzyCacheStats().Capacity = %d without WithFuzzyCache, want 0", st.Capacity)
+	}
+}
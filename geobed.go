@@ -4,12 +4,16 @@ import (
 	"archive/zip"
 	"bufio"
 	"bytes"
+	"cmp"
 	"compress/bzip2"
 	"compress/gzip"
+	"context"
 	"embed"
 	_ "embed"
 	"encoding/gob"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"io/fs"
 	"log"
@@ -18,11 +22,14 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/agnivade/levenshtein"
 	"github.com/golang/geo/s2"
@@ -35,10 +42,10 @@ var cacheData embed.FS
 type DataSourceID string
 
 const (
-	DataSourceGeonamesCities   DataSourceID = "geonamesCities1000"
-	DataSourceGeonamesCountry  DataSourceID = "geonamesCountryInfo"
-	DataSourceGeonamesAdmin1   DataSourceID = "geonamesAdmin1Codes"
-	DataSourceMaxMindCities    DataSourceID = "maxmindWorldCities"
+	DataSourceGeonamesCities  DataSourceID = "geonamesCities1000"
+	DataSourceGeonamesCountry DataSourceID = "geonamesCountryInfo"
+	DataSourceGeonamesAdmin1  DataSourceID = "geonamesAdmin1Codes"
+	DataSourceMaxMindCities   DataSourceID = "maxmindWorldCities"
 )
 
 // DataSource defines a data source for geocoding data.
@@ -90,6 +97,26 @@ var sortedUsStateCodes = sync.OnceValue(func() []string {
 	return codes
 })
 
+// usStateCodeKeys maps lowercase US state codes to their rank in
+// sortedUsStateCodes, so query parsing can look codes up instead of scanning.
+var usStateCodeKeys = sync.OnceValue(func() map[string]int {
+	keys := make(map[string]int, len(UsStateCodes))
+	for i, sc := range sortedUsStateCodes() {
+		keys[toLower(sc)] = i
+	}
+	return keys
+})
+
+// usStateNameKeys maps lowercase US state names to the rank of their code in
+// sortedUsStateCodes.
+var usStateNameKeys = sync.OnceValue(func() map[string]int {
+	keys := make(map[string]int, len(UsStateCodes))
+	for i, sc := range sortedUsStateCodes() {
+		keys[toLower(UsStateCodes[sc])] = i
+	}
+	return keys
+})
+
 // s2CellLevel determines the granularity of the S2 spatial index for reverse geocoding.
 //
 // S2 cells are a hierarchical spatial indexing system (see https://s2geometry.io/).
@@ -113,7 +140,12 @@ const s2CellLevel = 10
 // Thread Safety: Each stringInterner has its own RWMutex protecting all access:
 //   - Writes (interning new values) acquire the write lock
 //   - Reads (lookup by index) acquire the read lock
-//   - Initialization uses sync.Once for one-time setup
+//   - The interners are created at package initialization and never replaced
+//
+// Multiple Instances: Interning is append-only and keyed by the code string, so
+// an index means the same code for every instance. Instances loading different
+// datasets share the tables without affecting each other's results; only the
+// occupancy reported by InternerStats is combined.
 //
 // Memory Efficiency: By storing string indexes (uint8/uint16) instead of strings
 // in each GeobedCity, we save ~24 bytes per city (two string headers). With ~145K
@@ -141,14 +173,15 @@ func newStringInterner[T ~uint8 | ~uint16](capacity int) *stringInterner[T] {
 
 // intern returns the index for a string, creating it if needed.
 // Thread-safe: uses double-checked locking pattern.
-// Panics if the interner capacity is exceeded (should never happen with uint16
-// and real-world datasets, but protects against silent data corruption).
-func (si *stringInterner[T]) intern(s string) T {
+// Fails with ErrTooManyCodes once the interner capacity is exceeded (never
+// with uint16 and real-world datasets, but a corrupt or hostile dataset must
+// not crash the process); debug builds panic instead.
+func (si *stringInterner[T]) intern(s string) (T, error) {
 	// Fast path: check with read lock
 	si.mu.RLock()
 	if idx, ok := si.index[s]; ok {
 		si.mu.RUnlock()
-		return idx
+		return idx, nil
 	}
 	si.mu.RUnlock()
 
@@ -156,7 +189,7 @@ func (si *stringInterner[T]) intern(s string) T {
 	si.mu.Lock()
 	defer si.mu.Unlock()
 	if idx, ok := si.index[s]; ok {
-		return idx
+		return idx, nil
 	}
 
 	// Overflow protection: check if we've exceeded the type's capacity
@@ -165,13 +198,25 @@ func (si *stringInterner[T]) intern(s string) T {
 	// indices are 1..65535, allowing 65535 unique non-empty strings.
 	maxVal := int(^T(0)) // Maximum value for type T (e.g., 65535 for uint16)
 	if len(si.lookup) > maxVal {
-		panic(fmt.Sprintf("stringInterner capacity exceeded: %d entries (max %d)", len(si.lookup), maxVal))
+		err := fmt.Errorf("%w: interning %q: capacity exceeded: %d entries (max %d)", ErrTooManyCodes, s, len(si.lookup), maxVal)
+		if debugBuild {
+			panic(err)
+		}
+		return 0, err
 	}
 
 	idx := T(len(si.lookup))
 	si.lookup = append(si.lookup, s)
 	si.index[s] = idx
-	return idx
+	return idx, nil
+}
+
+// find returns the index of an interned string without interning it.
+func (si *stringInterner[T]) find(s string) (T, bool) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	idx, ok := si.index[s]
+	return idx, ok
 }
 
 // get returns the string for an index, or empty string if out of bounds.
@@ -196,50 +241,168 @@ var (
 	// Using uint8 (max 255) would be dangerously close to the limit and could
 	// overflow if the dataset grows or custom countries are added. uint16 provides
 	// ample headroom (max 65535) at minimal memory cost due to struct alignment.
-	countryInterner *stringInterner[uint16]
-	regionInterner  *stringInterner[uint16]
-	lookupOnce      sync.Once
+	//
+	// Capacity hints are for the initial allocation; the tables grow as needed.
+	countryInterner = newStringInterner[uint16](300)  // ~252 countries in Geonames
+	regionInterner  = newStringInterner[uint16](8192) // ~4000+ admin regions worldwide
 )
 
 // GeobedConfig contains configuration options for GeoBed initialization.
 type GeobedConfig struct {
-	DataDir  string // Directory for raw data files (default: "./geobed-data")
-	CacheDir string // Directory for cache files (default: "./geobed-cache")
+	DataDir       string        // Directory for raw data files (default: see defaultDir)
+	CacheDir      string        // Directory for cache files (default: see defaultDir)
+	MaxDatasetAge time.Duration // HealthCheck fails for older datasets (0 = no limit)
+
+	MaxConcurrentFuzzy int  // Max simultaneous fuzzy index scans (0 = unlimited)
+	FuzzyFallback      bool // Run over-limit fuzzy queries without fuzziness instead of waiting
+
+	QueryCacheSize int // Parsed-query LRU capacity (0 = disabled)
+	FuzzyCacheSize int // Fuzzy-scan LRU capacity (0 = disabled)
+
+	AltNames AltNamePolicy // Which alternate names to index when building a cache
+
+	PlacesFile    string       // Supplemental places TSV merged in at load time ("" = none)
+	Places        []CityRecord // Supplemental places given in code (WithPlaces)
+	ReversePlaces bool         // Let ReverseGeocode return supplemental places
+	EEZFile       string       // GeoJSON Exclusive Economic Zones for points at sea ("" = none)
+	BigCityGrid   bool         // Precompute ApproxReverseGeocode's grid at load and with the cache
+
+	Metro    MetroOverride // Neighborhood-to-metro override parameters (zero = defaults)
+	DedupeKm float64       // Near-duplicate distance for result lists (0 = keep all)
+
+	Ranker     Ranker     // Forward-geocoding candidate scoring (nil = DefaultRanker)
+	ScoreTrace io.Writer  // Receives JSON-lines scoring traces (nil = disabled)
+	Normalizer Normalizer // Name-index key normalization (nil = DefaultNormalizer)
+	Collation  string     // BCP 47 locale for ordering city lists ("" = lowercased code points)
+
+	QueryPreprocessor func(string) string   // Rewrites forward-geocoding queries before parsing (nil = none)
+	ResultFilter      func(GeobedCity) bool // Forward-geocoding candidates must pass it (nil = all)
+	MissHook          func(Miss)            // Receives a sample of unmatched or doubtful queries (nil = none)
+	MissSampleRate    float64               // Fraction of misses passed to MissHook, in (0, 1]
+
+	Territories TerritoryPolicy // Country labels for disputed territories (nil = Geonames labels)
+	Tuning      CountryTuning   // Per-country query-parsing rules (nil = all heuristics on)
+
+	NoUSDefault bool // US state codes and names in queries do not imply country US
+
+	HTTPClient      *http.Client  // Client for data downloads (nil = shared default client)
+	DownloadTimeout time.Duration // Limit per download attempt (0 = 10 minutes)
+	DownloadRetries int           // Extra attempts after a failed download (0 = none)
+	Offline         bool          // Never download; missing raw data files are an error
+
+	Dataset     Dataset // Geonames cities dump ("" = embedded cities1000)
+	DatasetDate string  // Pinned Geonames snapshot, YYYY-MM-DD ("" = latest)
+	ArchiveURL  string  // URL layout of dated snapshots, with {date} and {file}
+
+	WarmStartDir string      // Directory for pre-decoded cache snapshots ("" = disabled)
+	InitProfile  InitProfile // When reverse-geocoding indexes are built (default Balanced)
+
+	Progress func(ProgressEvent) // Receives cold-path progress events (nil = none)
 }
 
 // Option is a functional option for configuring GeoBed.
 type Option func(*GeobedConfig)
 
-// WithDataDir sets the directory for raw data files.
+// WithDataDir sets the directory for raw data files, overriding
+// GEOBED_DATA_DIR and the default location.
 func WithDataDir(dir string) Option {
 	return func(c *GeobedConfig) {
 		c.DataDir = dir
 	}
 }
 
-// WithCacheDir sets the directory for cache files.
+// WithCacheDir sets the directory for cache files, overriding
+// GEOBED_CACHE_DIR and the default location.
 func WithCacheDir(dir string) Option {
 	return func(c *GeobedConfig) {
 		c.CacheDir = dir
 	}
 }
 
-// defaultConfig returns the default configuration.
+// WithQueryCache caches the parsed form of up to size distinct raw queries.
+// Parsing compares every query against all country names and state codes,
+// so services that see the same queries repeatedly save that work on hits.
+// Hit rates are reported by QueryCacheStats.
+func WithQueryCache(size int) Option {
+	return func(c *GeobedConfig) {
+		c.QueryCacheSize = size
+	}
+}
+
+// defaultConfig returns the default configuration, including any settings
+// from GEOBED_* environment variables.
 func defaultConfig() *GeobedConfig {
-	return &GeobedConfig{
-		DataDir:  "./geobed-data",
-		CacheDir: "./geobed-cache",
+	c := &GeobedConfig{
+		DataDir:  defaultDir(envDataDir, "./geobed-data", "data"),
+		CacheDir: defaultDir(envCacheDir, "./geobed-cache", "cache"),
 	}
+	applyEnv(c)
+	return c
 }
 
 // GeoBed provides offline geocoding using embedded city data.
-// Safe for concurrent use after initialization.
+// Safe for concurrent use after initialization. Queries take no locks, so
+// an instance's data must not change once it is in use: to change it,
+// build a new instance and install it with a Manager.
 type GeoBed struct {
-	Cities      Cities              // All loaded cities, sorted by name
-	Countries   []CountryInfo       // Country metadata from Geonames
-	nameIndex   map[string][]int    // inverted index: lowercase name → city indices
-	cellIndex   map[s2.CellID][]int // S2 cell index for reverse geocoding
-	config      *GeobedConfig       // Configuration options
+	Cities      Cities                            // All loaded cities, sorted by name
+	Countries   []CountryInfo                     // Country metadata from Geonames
+	nameIndex   map[string][]int                  // inverted index: lowercase name → city indices
+	cellIndex   map[s2.CellID][]int               // S2 cell index for reverse geocoding
+	config      *GeobedConfig                     // Configuration options
+	dataset     Dataset                           // Loaded Geonames dump ("" when built from records)
+	datasetDate time.Time                         // When the loaded dataset was produced
+	fuzzySem    chan struct{}                     // Limits concurrent fuzzy scans (nil = unlimited)
+	queryCache  *lruCache[string, locationPieces] // Parsed queries (nil = disabled)
+	fuzzyCache  *lruCache[fuzzyScanKey, []int]    // Fuzzy scan results (nil = disabled)
+	countryKeys map[string]int                    // lowercase country name → index in Countries
+	countryISO  map[string]int                    // ISO or ISO3 country code → index in Countries
+	neighbours  map[string][]string               // ISO country code → ISO codes of bordering countries
+
+	nameKeysOnce sync.Once // Guards nameKeys
+	nameKeys     []string  // Sorted nameIndex keys, built on first prefix query
+
+	keyStartsOnce sync.Once // Guards keyStarts
+	keyStarts     []uint8   // Runes each sorted key shares with the one before, built on first Suggest
+
+	keysByLenOnce sync.Once  // Guards keysByLen
+	keysByLen     [][]string // nameIndex keys by rune count, built on first fuzzy scan
+
+	buildStats CacheBuildStats // Set by loadDataSets when built from raw data
+
+	traceMu sync.Mutex // Serializes writes to config.ScoreTrace
+
+	boundsOnce sync.Once       // Guards bounds
+	bounds     map[string]bbox // Country and region bounding boxes, built on first use
+
+	tierCellIndex [len(popTiers)]map[s2.CellID][]int // cellIndex restricted to each population tier
+
+	cellOnce sync.Once // Guards cellIndex when FastStart defers it to first use
+
+	coarseIndex map[s2.CellID][]int // cellIndex coarsened to coarseCellLevel
+	eez         []eezZone           // Exclusive Economic Zones loaded with WithEEZFile
+
+	bigCityOnce sync.Once // Guards bigCity when WithBigCityGrid did not build it
+	bigCity     []int32   // Nearest big city per level-7 cell, see ApproxReverseGeocode
+
+	byPopOnce sync.Once // Guards byPop
+	byPop     []int     // City indices by descending population, built on first use
+
+	byCountryOnce sync.Once        // Guards byCountry
+	byCountry     map[string][]int // City indices per country by descending population, built on first use
+
+	byRegionOnce sync.Once        // Guards byRegion
+	byRegion     map[string][]int // City indices per "CC.ADMIN1" in name order, built on first use
+
+	byCountryNameOnce sync.Once                // Guards byCountryName
+	byCountryName     map[countryNameKey][]int // nameIndex split by country, built on first Country-scoped query
+
+	capitals map[string]GeobedCity // ISO country code → capital city
+
+	postalOnce  sync.Once                 // Guards postalRegex
+	postalRegex map[string]*regexp.Regexp // ISO country code → compiled postal code pattern, built on first ParseLocation
+
+	admin1 atomic.Pointer[map[string]map[string]AdminDivision] // Admin divisions, pinned on first successful load
 }
 
 // Cities is a sortable slice of GeobedCity.
@@ -276,16 +439,54 @@ func compareCaseInsensitive(a, b string) int {
 	return 0
 }
 
+// compareCities defines a total order over cities: case-insensitive name
+// first (matching Cities.Less), then every remaining field as a tiebreaker.
+// Used when generating the cache so that the resulting order, and therefore
+// every index position stored on disk, does not depend on input order.
+func compareCities(a, b GeobedCity) int {
+	if c := compareCaseInsensitive(a.City, b.City); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.City, b.City); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Country(), b.Country()); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Region(), b.Region()); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Latitude, b.Latitude); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Longitude, b.Longitude); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Population, b.Population); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.CityAlt, b.CityAlt); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.geonameID, b.geonameID); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.source, b.source)
+}
+
 // GeobedCity represents a city with geocoding data.
 // Memory-optimized: uses indexes for Country/Region, float32 for coordinates.
 type GeobedCity struct {
-	City       string  // City name
-	CityAlt    string  // Alternate names (comma-separated)
-	country    uint16  // Index into countryLookup (uint16 to safely handle 252+ countries)
-	region     uint16  // Index into regionLookup
-	Latitude   float32 // Latitude in degrees
-	Longitude  float32 // Longitude in degrees
-	Population int32   // Population count
+	City       string       // City name
+	CityAlt    string       // Alternate names (comma-separated)
+	country    uint16       // Index into countryLookup (uint16 to safely handle 252+ countries)
+	region     uint16       // Index into regionLookup
+	Latitude   float32      // Latitude in degrees
+	Longitude  float32      // Longitude in degrees
+	Population int32        // Population count
+	geonameID  int32        // Geonames record ID (0 for MaxMind records and pre-ID caches)
+	source     RecordSource // Data set the record came from
+	capital    bool         // Geonames feature code PPLC (false in caches generated before it was recorded)
 }
 
 // Country returns the ISO 3166-1 alpha-2 country code (e.g., "US", "FR").
@@ -298,6 +499,38 @@ func (c GeobedCity) Region() string {
 	return regionInterner.get(c.region)
 }
 
+// GeonameID returns the Geonames record ID of the city, or 0 when the city
+// did not come from Geonames or was loaded from a cache generated before IDs
+// were recorded.
+func (c GeobedCity) GeonameID() int32 {
+	return c.geonameID
+}
+
+// ID returns an identifier for the city that is stable across cache
+// rebuilds and dataset refreshes, suitable for persisting references to
+// geocoding results.
+//
+// Geonames cities use their record ID in decimal (e.g., "4671654"). Other
+// cities, and cities from caches generated before record IDs were stored,
+// use "h" followed by a 64-bit FNV-1a hash of the name, country, and
+// coordinates rounded to four decimal places (~11m), so minor coordinate
+// jitter between releases does not change the ID. Every city in the
+// embedded cache has a record ID.
+func (c GeobedCity) ID() string {
+	if c.geonameID > 0 {
+		return strconv.FormatInt(int64(c.geonameID), 10)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(c.City))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Country()))
+	h.Write([]byte{0})
+	h.Write(strconv.AppendFloat(nil, float64(c.Latitude), 'f', 4, 32))
+	h.Write([]byte{0})
+	h.Write(strconv.AppendFloat(nil, float64(c.Longitude), 'f', 4, 32))
+	return "h" + strconv.FormatUint(h.Sum64(), 16)
+}
+
 // CountryCount returns the number of unique country codes in the lookup table.
 // Useful for testing and debugging.
 func CountryCount() int {
@@ -310,6 +543,38 @@ func RegionCount() int {
 	return regionInterner.count()
 }
 
+// CityCount returns the number of cities loaded into this instance.
+func (g *GeoBed) CityCount() int {
+	return len(g.Cities)
+}
+
+// CountryCountLoaded returns the number of countries whose metadata is loaded
+// into this instance. Unlike the package-level CountryCount, it does not
+// count codes interned by other instances or by city records alone.
+func (g *GeoBed) CountryCountLoaded() int {
+	return len(g.Countries)
+}
+
+// InternerStats describes the package-level country and region interners.
+type InternerStats struct {
+	CountryCodes int // Distinct non-empty country codes interned
+	RegionCodes  int // Distinct non-empty region codes interned
+	MaxCodes     int // Capacity of each interner before interning fails
+}
+
+// InternerStats reports interner occupancy. The interners are shared by all
+// instances in the process, so the counts include codes from every dataset
+// loaded so far; monitoring RegionCodes against MaxCodes gives early warning
+// before custom datasets exhaust the index space.
+func (g *GeoBed) InternerStats() InternerStats {
+	// count() includes the reserved empty-string entry at index 0.
+	return InternerStats{
+		CountryCodes: countryInterner.count() - 1,
+		RegionCodes:  regionInterner.count() - 1,
+		MaxCodes:     int(^uint16(0)),
+	}
+}
+
 // geobedCityGob is used for GOB serialization (stores strings, not indexes).
 type geobedCityGob struct {
 	City       string
@@ -319,6 +584,34 @@ type geobedCityGob struct {
 	Latitude   float32
 	Longitude  float32
 	Population int32
+	GeonameID  int32        // absent (decodes as 0) in caches generated before IDs were recorded
+	Source     RecordSource // absent (decodes as SourceUnknown) in older caches
+	Capital    bool         // absent (decodes as false) in older caches
+}
+
+// toCity converts the string-based form into a GeobedCity, interning the
+// country and region codes. It fails only with ErrTooManyCodes.
+func (gc geobedCityGob) toCity() (GeobedCity, error) {
+	country, err := internCountry(gc.Country)
+	if err != nil {
+		return GeobedCity{}, err
+	}
+	region, err := internRegion(gc.Region)
+	if err != nil {
+		return GeobedCity{}, err
+	}
+	return GeobedCity{
+		City:       gc.City,
+		CityAlt:    gc.CityAlt,
+		country:    country,
+		region:     region,
+		Latitude:   gc.Latitude,
+		Longitude:  gc.Longitude,
+		Population: gc.Population,
+		geonameID:  gc.GeonameID,
+		source:     gc.Source,
+		capital:    gc.Capital,
+	}, nil
 }
 
 // maxFuzzyDistance caps FuzzyDistance to prevent expensive O(N) scans
@@ -330,28 +623,49 @@ const maxFuzzyDistance = 3
 var downloadMu sync.Mutex
 
 // Singleton pattern for default GeoBed instance.
-// Uses Mutex instead of sync.Once so transient errors allow retry.
+// Uses Mutex instead of sync.Once so transient errors allow retry; the
+// instance itself lives in a Manager so Swap can replace it.
 var (
-	defaultGeobed   *GeoBed
+	defaultGeobed   Manager
 	defaultGeobedMu sync.Mutex
 )
 
 // GetDefaultGeobed returns a shared GeoBed instance, initializing it on first call.
 // Unlike sync.Once, transient errors (e.g., network down during download) allow retry.
+// After Swap it returns the swapped-in instance.
 func GetDefaultGeobed() (*GeoBed, error) {
+	if g := defaultGeobed.Get(); g != nil {
+		return g, nil
+	}
 	defaultGeobedMu.Lock()
 	defer defaultGeobedMu.Unlock()
-	if defaultGeobed != nil {
-		return defaultGeobed, nil
+	if g := defaultGeobed.Get(); g != nil {
+		return g, nil
 	}
 	g, err := NewGeobed()
 	if err != nil {
 		return nil, err
 	}
-	defaultGeobed = g
+	defaultGeobed.Swap(g)
 	return g, nil
 }
 
+// ResetDefaultGeobed discards the shared instance so that the next
+// GetDefaultGeobed builds a new one, re-reading the environment (see
+// applyEnv) and the admin division files. It is intended for tests that
+// exercise cold starts or environment permutations against the singleton
+// within one process; callers still holding the old instance keep using it.
+// Production code should use Swap or a Manager instead.
+func ResetDefaultGeobed() {
+	defaultGeobedMu.Lock()
+	defer defaultGeobedMu.Unlock()
+	defaultGeobed.Swap(nil)
+
+	adminDivisionsCacheMu.Lock()
+	defer adminDivisionsCacheMu.Unlock()
+	clear(adminDivisionsCache)
+}
+
 // CountryInfo contains metadata about a country from Geonames.
 type CountryInfo struct {
 	Country            string
@@ -377,10 +691,40 @@ type CountryInfo struct {
 
 // GeocodeOptions configures geocoding behavior.
 type GeocodeOptions struct {
-	ExactCity     bool // Require exact city name match
-	FuzzyDistance int  // Max edit distance for typo tolerance (0 = disabled, 1-2 recommended)
+	ExactCity     bool   // Require exact city name match
+	FuzzyDistance int    // Max edit distance for typo tolerance (0 = disabled, 1-2 recommended)
+	Country       string // Only return cities in this ISO country code ("" = any)
+
+	// Deadline bounds the time spent on fuzzy matching (zero = no deadline).
+	// When it passes, the index scan stops and the best candidate found so
+	// far is returned with GeocodeResult.Partial set. Partial results depend
+	// on map iteration order and are therefore not deterministic.
+	Deadline time.Time
+}
+
+// GeocodeResult is the detailed outcome of a forward geocode.
+type GeocodeResult struct {
+	City    GeobedCity // Best match (zero value when nothing matched)
+	Partial bool       // Deadline hit before matching completed; City is best-effort
+
+	// HintMismatch reports that City lies outside the bounding box of the
+	// region or country named in the query, e.g. Springfield, MO for
+	// "Springfield, Mexico". A candidate inside the region is preferred
+	// unless it scores well below City, so this is only set when no such
+	// candidate matched closely enough.
+	HintMismatch bool
+
+	// MissReason says why City is empty; MissNone when a city matched.
+	MissReason MissReason
+
+	Dataset     Dataset   // Dataset City comes from; see GeoBed.Provenance
+	DatasetDate time.Time // Date of the dataset City comes from; see GeoBed.DatasetDate
 }
 
+// deadlineCheckInterval is how many index keys or candidates are processed
+// between deadline checks, keeping time.Now calls off the per-key path.
+const deadlineCheckInterval = 1024
+
 // maxGeocodeInputLen limits input string length to prevent algorithmic complexity
 // attacks on Levenshtein distance calculations. 256 chars accommodates the longest
 // real-world city names while preventing DoS via excessively long inputs.
@@ -401,25 +745,37 @@ const maxGeocodeInputLen = 256
 //	city := g.Geocode("Austin, TX")
 //	fmt.Printf("%s: %f, %f\n", city.City, city.Latitude, city.Longitude)
 func NewGeobed(opts ...Option) (*GeoBed, error) {
-	cfg := defaultConfig()
-	for _, opt := range opts {
-		opt(cfg)
+	g, err := newGeobed(opts)
+	if err != nil {
+		return nil, err
+	}
+	g.dataset = g.config.Dataset
+	if g.dataset == "" {
+		g.dataset = DatasetCities1000
 	}
 
-	g := &GeoBed{config: cfg}
-
-	// Initialize lookup tables (thread-safe, runs once)
-	lookupOnce.Do(initLookupTables)
-
-	var err error
-	g.Cities, err = loadGeobedCityData()
-	if err == nil {
-		g.Countries, err = loadGeobedCountryData()
+	// The cache and warm-start snapshots only hold the embedded dataset.
+	embedded := g.embeddedData()
+	warmPath := ""
+	if embedded {
+		warmPath = g.warmStartPath()
+	}
+	warm := warmPath != "" && g.loadWarmStart(warmPath) == nil
+	if !embedded {
+		err = fmt.Errorf("%w: configured dataset is not embedded", ErrCacheMissing)
+	} else if !warm {
+		err = g.loadCache()
+	}
+	g.datasetDate = cacheDatasetDate(g.config.CacheDir)
+	if err == nil && len(g.Cities) == 0 {
+		err = fmt.Errorf("%w: no cities in cache", ErrCacheCorrupt)
 	}
 	if err == nil {
-		g.nameIndex, err = loadNameIndex()
+		if sortErr := checkCitiesSorted(g.Cities); sortErr != nil {
+			err = fmt.Errorf("%w: %w", ErrCacheCorrupt, sortErr)
+		}
 	}
-	if err != nil || len(g.Cities) == 0 {
+	if err != nil {
 		// Reset any partially loaded data before full reload to prevent
 		// duplication (e.g., cities loaded from cache but nameIndex failed).
 		g.Cities = nil
@@ -427,45 +783,159 @@ func NewGeobed(opts ...Option) (*GeoBed, error) {
 		g.nameIndex = nil
 
 		if downloadErr := g.downloadDataSets(); downloadErr != nil {
-			return nil, fmt.Errorf("failed to download data sets: %w", downloadErr)
+			// Wrap both causes so callers can tell an offline cold start
+			// (ErrDownloadFailed) from why the cache was unusable.
+			return nil, fmt.Errorf("failed to download data sets: %w (cache unusable: %w)", downloadErr, err)
 		}
 		if loadErr := g.loadDataSets(); loadErr != nil {
 			return nil, fmt.Errorf("failed to load data sets: %w", loadErr)
 		}
-		if storeErr := g.store(); storeErr != nil {
-			log.Printf("warning: failed to store cache: %v", storeErr)
+		g.datasetDate = g.rawDatasetDate()
+		// A stored cache of another dataset would shadow the embedded one
+		// for later instances. Read-only cache directories are skipped
+		// rather than warned about on every start; they are only probed
+		// here, so warm starts never touch CacheDir.
+		if embedded && !cacheDirReadOnly(g.config.CacheDir) {
+			g.progress(ProgressEvent{Stage: ProgressStore})
+			if storeErr := g.store(); storeErr != nil {
+				log.Printf("warning: failed to store cache: %v", storeErr)
+			}
+		}
+	} else {
+		if g.config.BigCityGrid {
+			// A missing or stale grid is built by finishInit instead.
+			g.bigCity, _ = loadBigCityGrid(g.config.CacheDir, len(g.Cities))
+		}
+		if warmPath != "" && !warm {
+			if warmErr := g.writeWarmStart(warmPath); warmErr != nil {
+				log.Printf("warning: failed to write warm-start snapshot: %v", warmErr)
+			}
 		}
 	}
 
-	g.buildCellIndex()
+	// Like places, a custom normalizer never affects the stored cache.
+	if g.config.Normalizer != nil {
+		g.rebuildNameIndex()
+	}
+
+	// Places are merged after the cache is stored so they never end up in it.
+	if err := g.finishInit(); err != nil {
+		return nil, err
+	}
 	return g, nil
 }
 
-// initLookupTables initializes the country and region string interners.
-func initLookupTables() {
-	// Capacity hints for initial allocation (will grow if needed)
-	countryInterner = newStringInterner[uint16](300)  // ~252 countries in Geonames
-	regionInterner = newStringInterner[uint16](8192)  // ~4000+ admin regions worldwide
+// newGeobed applies opts and prepares an instance without any data.
+func newGeobed(opts []Option) (*GeoBed, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	g := &GeoBed{config: cfg}
+	if cfg.MaxConcurrentFuzzy > 0 {
+		g.fuzzySem = make(chan struct{}, cfg.MaxConcurrentFuzzy)
+	}
+	g.queryCache = newLRUCache[string, locationPieces](cfg.QueryCacheSize)
+	g.fuzzyCache = newLRUCache[fuzzyScanKey, []int](cfg.FuzzyCacheSize)
+	return g, nil
+}
+
+// finishInit merges places, applies the territory policy, and builds the
+// derived indexes once Cities, Countries, and the name index are loaded.
+func (g *GeoBed) finishInit() error {
+	var places Cities
+	if g.config.PlacesFile != "" {
+		var err error
+		if places, err = readPlacesFile(g.config.PlacesFile); err != nil {
+			return fmt.Errorf("loading places: %w", err)
+		}
+	}
+	for i, r := range g.config.Places {
+		c, err := r.toCity(SourceCustom)
+		if err != nil {
+			return fmt.Errorf("place %d: %w", i, err)
+		}
+		places = append(places, c)
+	}
+	g.mergePlaces(places)
+
+	if len(g.config.Territories) > 0 {
+		if err := g.applyTerritoryPolicy(g.config.Territories); err != nil {
+			return fmt.Errorf("applying territory policy: %w", err)
+		}
+	}
+
+	if g.config.InitProfile != FastStart {
+		g.buildCellIndex()
+	}
+	// Places shift the city indices a stored grid refers to.
+	if g.config.BigCityGrid && (g.bigCity == nil || len(places) > 0) {
+		g.bigCity = g.buildBigCityGrid()
+	}
+	g.buildCountryKeys()
+	g.buildCapitals()
+
+	if g.config.EEZFile != "" {
+		if err := g.loadEEZFile(g.config.EEZFile); err != nil {
+			return fmt.Errorf("loading EEZ file: %w", err)
+		}
+	}
+	return nil
 }
 
 // internCountry returns the index for a country code, creating it if needed.
-func internCountry(code string) uint16 {
+func internCountry(code string) (uint16, error) {
 	return countryInterner.intern(code)
 }
 
 // internRegion returns the index for a region code, creating it if needed.
-func internRegion(code string) uint16 {
+func internRegion(code string) (uint16, error) {
 	return regionInterner.intern(code)
 }
 
-// buildCellIndex creates an S2 cell-based spatial index for fast reverse geocoding.
+// buildCellIndex creates an S2 cell-based spatial index for fast reverse
+// geocoding, plus the population-tier subsets of it.
 func (g *GeoBed) buildCellIndex() {
 	g.cellIndex = make(map[s2.CellID][]int)
 	for i, city := range g.Cities {
+		if city.source == SourceCustom && !g.config.ReversePlaces {
+			continue
+		}
 		ll := s2.LatLngFromDegrees(float64(city.Latitude), float64(city.Longitude))
 		cell := s2.CellIDFromLatLng(ll).Parent(s2CellLevel)
 		g.cellIndex[cell] = append(g.cellIndex[cell], i)
 	}
+	g.buildCoarseIndex()
+	if g.config.InitProfile != LowMemory {
+		g.buildTierIndexes()
+	}
+}
+
+// buildCountryKeys indexes lowercase country names, ISO codes, and land
+// borders for query parsing and country lookups. When two countries share a
+// name, the first in Countries wins.
+func (g *GeoBed) buildCountryKeys() {
+	g.countryKeys = make(map[string]int, len(g.Countries))
+	g.countryISO = make(map[string]int, len(g.Countries))
+	g.neighbours = make(map[string][]string, len(g.Countries))
+	for i, co := range g.Countries {
+		g.countryISO[co.ISO] = i
+		if co.ISO3 != "" {
+			g.countryISO[co.ISO3] = i
+		}
+		key := toLower(co.Country)
+		if _, dup := g.countryKeys[key]; !dup {
+			g.countryKeys[key] = i
+		}
+		if codes := co.NeighbourList(); codes != nil {
+			g.neighbours[co.ISO] = codes
+		}
+	}
 }
 
 // cellAndNeighbors returns the given cell plus its neighboring cells in a
@@ -502,45 +972,79 @@ func (g *GeoBed) downloadDataSets() error {
 	downloadMu.Lock()
 	defer downloadMu.Unlock()
 
+	// Offline instances only check for the files, so a read-only data
+	// directory with everything in place still works.
+	if g.config.Offline {
+		for _, f := range g.dataSources() {
+			if _, err := os.Stat(filepath.Join(g.config.DataDir, filepath.Base(f.Path))); err != nil {
+				return fmt.Errorf("%s missing from %s: %w: offline mode", f.ID, g.config.DataDir, ErrDownloadFailed)
+			}
+		}
+		return nil
+	}
+
 	// WHY 0755: Using restrictive permissions (rwxr-xr-x) instead of world-writable (0777)
 	// to prevent security issues (CWE-732) in shared environments like Kubernetes or
 	// multi-user servers where other users could inject malicious data files.
 	if err := os.MkdirAll(g.config.DataDir, 0755); err != nil {
-		return fmt.Errorf("creating data directory: %w", err)
+		return fmt.Errorf("creating data directory: %w: %w", ErrDataDirUnwritable, err)
+	}
+	// Other processes sharing the data directory may be downloading too.
+	unlock, err := lockDir(g.config.DataDir)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDataDirUnwritable, err)
 	}
+	defer unlock()
+	removePartialDownloads(g.config.DataDir)
 
-	for _, f := range dataSetFiles {
+	for _, f := range g.dataSources() {
 		localPath := g.config.DataDir + "/" + filepath.Base(f.Path)
 		// Re-check existence inside lock (another goroutine may have downloaded)
 		if _, err := os.Stat(localPath); err == nil {
 			continue
 		}
-		if err := downloadFile(f.URL, localPath); err != nil {
+		g.progress(ProgressEvent{Stage: ProgressDownload, File: filepath.Base(f.Path)})
+		if err := g.download(f.URL, localPath); err != nil {
 			return fmt.Errorf("downloading %s: %w", f.ID, err)
 		}
 	}
 	return nil
 }
 
-// httpClient is a shared HTTP client with reasonable timeouts.
-var httpClient = &http.Client{
-	Timeout: 30 * time.Second,
-}
-
-func downloadFile(url, path string) error {
-	resp, err := httpClient.Get(url)
+// httpClient is the shared HTTP client used unless WithHTTPClient is set.
+// It has no overall timeout; each download is bounded by its own context
+// instead (see WithDownloadTimeout).
+var httpClient = &http.Client{}
+
+// downloadFile fetches url into path. Failures to reach the server or read
+// the response wrap ErrDownloadFailed; a *downloadStatusError records the
+// status code of unsuccessful responses. report, if not nil, receives the
+// bytes written and the response's content length (0 if unknown).
+func downloadFile(ctx context.Context, client *http.Client, url, path string, report func(done, total int64)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("HTTP GET %s: %w: %w", url, ErrDownloadFailed, err)
+	}
+	if compressibleDownload(url) {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("HTTP GET %s: %w", url, err)
+		return fmt.Errorf("HTTP GET %s: %w: %w", url, ErrDownloadFailed, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP GET %s: status %d", url, resp.StatusCode)
+		return fmt.Errorf("HTTP GET %s: %w: %w", url, ErrDownloadFailed, &downloadStatusError{resp.StatusCode})
 	}
 
-	out, err := os.Create(path)
+	// The body goes to a .part file that is renamed once complete, so an
+	// interrupted download never leaves a truncated file under the final
+	// name for the next start to parse.
+	part := path + partSuffix
+	out, err := os.Create(part)
 	if err != nil {
-		return fmt.Errorf("creating file %s: %w", path, err)
+		return fmt.Errorf("creating file %s: %w: %w", part, ErrDataDirUnwritable, err)
 	}
 
 	// Use a flag to track success so the deferred cleanup can remove
@@ -550,17 +1054,28 @@ func downloadFile(url, path string) error {
 	defer func() {
 		out.Close()
 		if !success {
-			os.Remove(path) // best-effort cleanup of partial file
+			os.Remove(part) // best-effort cleanup of partial file
 		}
 	}()
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return fmt.Errorf("writing file %s: %w", path, err)
+	// Progress counts the bytes transferred, which the content length
+	// describes, not the decoded ones.
+	body := newProgressReader(resp.Body, max(resp.ContentLength, 0), report)
+	decoded, err := decodeContent(body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w: %w", url, ErrDownloadFailed, err)
 	}
+	if _, err := io.Copy(out, decoded); err != nil {
+		return fmt.Errorf("writing file %s: %w: %w", path, ErrDownloadFailed, err)
+	}
+	body.finish()
 
 	// Explicitly close to catch flush errors (e.g., on NFS)
 	if err := out.Close(); err != nil {
-		return fmt.Errorf("closing file %s: %w", path, err)
+		return fmt.Errorf("closing file %s: %w", part, err)
+	}
+	if err := os.Rename(part, path); err != nil {
+		return fmt.Errorf("renaming %s: %w: %w", part, ErrDataDirUnwritable, err)
 	}
 	success = true
 	return nil
@@ -572,7 +1087,7 @@ func (g *GeoBed) loadDataSets() error {
 	// when multiple goroutines call NewGeobed() concurrently.
 	locationDedupeIdx := make(map[string]bool)
 
-	for _, f := range dataSetFiles {
+	for _, f := range g.dataSources() {
 		localPath := g.config.DataDir + "/" + filepath.Base(f.Path)
 		switch f.ID {
 		case DataSourceGeonamesCities:
@@ -588,33 +1103,97 @@ func (g *GeoBed) loadDataSets() error {
 			if err := g.loadGeonamesCountryInfo(localPath); err != nil {
 				return fmt.Errorf("loading geonames country info: %w", err)
 			}
+		case DataSourceGeonamesAdmin1:
+			// Parsed on first use (see loadAdminDivisionsForDir); only the
+			// layout is checked here.
+			if err := validateRawFile(localPath, admin1Schema); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("loading geonames admin1 codes: %w", err)
+			}
 		}
 	}
 
-	sort.Sort(g.Cities)
+	g.buildStats = CacheBuildStats{}
+	for i := range g.Cities {
+		g.Cities[i].CityAlt = cleanAltNames(g.Cities[i].CityAlt, &g.buildStats.AltNames)
+	}
+
+	// Stable sort with full tiebreakers keeps regenerated caches byte-identical
+	// for identical inputs.
+	sort.SliceStable(g.Cities, func(i, j int) bool {
+		return compareCities(g.Cities[i], g.Cities[j]) < 0
+	})
 
+	// The cache always holds default keys; NewGeobed rebuilds the index for
+	// a custom Normalizer after storing it.
 	g.nameIndex = make(map[string][]int)
+	total := int64(len(g.Cities))
+	g.progress(ProgressEvent{Stage: ProgressIndex, Total: total})
 	for i, city := range g.Cities {
-		// Index primary name
-		key := toLower(city.City)
-		if key != "" {
-			g.nameIndex[key] = append(g.nameIndex[key], i)
-		}
-		// Index each comma-separated alt name
-		if city.CityAlt != "" {
-			for _, raw := range strings.Split(city.CityAlt, ",") {
-				alt := strings.TrimSpace(raw)
-				if alt == "" {
-					continue
-				}
-				altKey := toLower(alt)
-				g.nameIndex[altKey] = append(g.nameIndex[altKey], i)
-			}
+		g.buildStats.RepeatedKeys += g.indexCityNames(i, city, DefaultNormalizer{})
+		if done := int64(i + 1); done%progressCities == 0 && done < total {
+			g.progress(ProgressEvent{Stage: ProgressIndex, Done: done, Total: total})
 		}
 	}
+	g.progress(ProgressEvent{Stage: ProgressIndex, Done: total, Total: total})
+	g.buildStats.Cities = len(g.Cities)
+	g.buildStats.IndexKeys = len(g.nameIndex)
+	for _, idxs := range g.nameIndex {
+		g.buildStats.IndexPostings += len(idxs)
+	}
 	return nil
 }
 
+// rebuildNameIndex replaces the name index with one keyed by the configured
+// normalizer.
+func (g *GeoBed) rebuildNameIndex() {
+	g.nameIndex = make(map[string][]int, len(g.nameIndex))
+	norm := g.normalizer()
+	for i, city := range g.Cities {
+		g.indexCityNames(i, city, norm)
+	}
+}
+
+// indexCityNames adds the city at position i to the name index under each
+// of its name keys and returns how many repeated keys were skipped.
+func (g *GeoBed) indexCityNames(i int, city GeobedCity, norm Normalizer) int {
+	keys, repeats := g.cityNameKeys(city, norm)
+	for _, key := range keys {
+		g.nameIndex[key] = append(g.nameIndex[key], i)
+	}
+	return repeats
+}
+
+// cityNameKeys returns the distinct normalized primary name of a city
+// followed by the alternate names allowed by the AltNames policy. Alternate
+// names repeating the primary name are common in Geonames; listing each key
+// once keeps them from adding duplicate postings; the number of repeats
+// skipped is returned alongside.
+func (g *GeoBed) cityNameKeys(city GeobedCity, norm Normalizer) (keys []string, repeats int) {
+	// Index primary name
+	if key := norm.Normalize(city.City); key != "" {
+		keys = append(keys, key)
+	}
+	// Index each comma-separated alt name
+	if city.CityAlt != "" {
+		for _, raw := range strings.Split(city.CityAlt, ",") {
+			alt := strings.TrimSpace(raw)
+			if alt == "" || !g.config.AltNames.allows(alt) {
+				continue
+			}
+			key := norm.Normalize(alt)
+			if key == "" {
+				continue
+			}
+			if slices.Contains(keys, key) {
+				repeats++
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, repeats
+}
+
 func (g *GeoBed) loadGeonamesCities(path string) error {
 	rz, err := zip.OpenReader(path)
 	if err != nil {
@@ -643,41 +1222,73 @@ func (g *GeoBed) processZipEntry(uF *zip.File) error {
 	}
 	defer fi.Close()
 
-	scanner := bufio.NewScanner(fi)
+	r := newProgressReader(fi, int64(uF.UncompressedSize64), g.progressReporter(ProgressParse, uF.Name))
+	scanner := bufio.NewScanner(r)
 	scanner.Split(bufio.ScanLines)
 
+	schema := newSchemaChecker(citiesSchema, uF.Name)
 	for scanner.Scan() {
-		fields := strings.SplitN(scanner.Text(), "\t", 19)
-		if len(fields) != 19 {
-			continue
-		}
-
-		// Parse coordinates with error handling to avoid "Null Island" (0,0) entries
-		// from malformed data. Skip records with invalid coordinates.
-		lat, errLat := strconv.ParseFloat(fields[4], 32)
-		lng, errLng := strconv.ParseFloat(fields[5], 32)
-		if errLat != nil || errLng != nil {
-			// Skip records with unparseable coordinates rather than
-			// storing them at (0,0) which would be incorrect
-			continue
-		}
-		pop, _ := strconv.Atoi(fields[14]) // Population of 0 is acceptable
-
-		c := GeobedCity{
-			City:       strings.Trim(fields[1], " "),
-			CityAlt:    fields[3],
-			country:    internCountry(fields[8]),
-			region:     internRegion(fields[10]),
-			Latitude:   float32(lat),
-			Longitude:  float32(lng),
-			Population: int32(pop),
+		if err := schema.check(scanner.Text()); err != nil {
+			return err
 		}
-
-		if len(c.City) > 0 {
+		if gc, ok := parseGeonamesCityLine(scanner.Text()); ok {
+			c, err := gc.toCity()
+			if err != nil {
+				return err
+			}
 			g.Cities = append(g.Cities, c)
 		}
 	}
-	return scanner.Err()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	r.finish()
+	return nil
+}
+
+// parseGeonamesCityLine parses one tab-separated line of a Geonames cities
+// dump. Returns false for lines that are malformed or describe no usable city.
+// The result holds plain strings so parsing has no side effects on the
+// package-level interners.
+func parseGeonamesCityLine(line string) (geobedCityGob, bool) {
+	fields := strings.SplitN(line, "\t", 19)
+	if len(fields) != 19 {
+		return geobedCityGob{}, false
+	}
+
+	// Parse coordinates with error handling to avoid "Null Island" (0,0) entries
+	// from malformed data. Skip records with invalid coordinates.
+	lat, errLat := strconv.ParseFloat(fields[4], 32)
+	lng, errLng := strconv.ParseFloat(fields[5], 32)
+	if errLat != nil || errLng != nil || !validCoordinates(lat, lng) {
+		// Skip records with unparseable coordinates rather than
+		// storing them at (0,0) which would be incorrect
+		return geobedCityGob{}, false
+	}
+	pop, _ := strconv.Atoi(fields[14]) // Population of 0 is acceptable
+	gid, _ := strconv.ParseInt(fields[0], 10, 32)
+
+	c := geobedCityGob{
+		City:       strings.Trim(fields[1], " "),
+		CityAlt:    fields[3],
+		Country:    fields[8],
+		Region:     fields[10],
+		Latitude:   float32(lat),
+		Longitude:  float32(lng),
+		Population: int32(pop),
+		GeonameID:  int32(gid),
+		Source:     SourceGeonames,
+		Capital:    fields[7] == "PPLC",
+	}
+	return c, len(c.City) > 0
+}
+
+// validCoordinates reports whether lat/lng are finite and within range.
+// strconv.ParseFloat accepts "NaN" and "Inf", which would otherwise produce
+// cities that poison the S2 cell index.
+func validCoordinates(lat, lng float64) bool {
+	return !math.IsNaN(lat) && !math.IsNaN(lng) &&
+		lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
 }
 
 func (g *GeoBed) loadMaxMindCities(path string, locationDedupeIdx map[string]bool) error {
@@ -715,49 +1326,70 @@ func (g *GeoBed) loadMaxMindCities(path string, locationDedupeIdx map[string]boo
 		return fmt.Errorf("scanning maxmind data: %w", err)
 	}
 
-	for _, fields := range maxMindCityDedupeIdx {
-		if fields[0] == "" || fields[0] == "0" || fields[2] == "AccentCity" {
-			continue
-		}
-
-		pop, _ := strconv.Atoi(fields[4])
-		// Parse coordinates with error handling to avoid "Null Island" (0,0) entries
-		lat, errLat := strconv.ParseFloat(fields[5], 32)
-		lng, errLng := strconv.ParseFloat(fields[6], 32)
-		if errLat != nil || errLng != nil {
-			continue // Skip records with unparseable coordinates
-		}
-
-		cn := strings.Trim(fields[2], " ")
-		cn = strings.Trim(cn, "( )")
+	// Iterate in key order so the first-wins location dedupe below picks the
+	// same record on every run.
+	keys := make([]string, 0, len(maxMindCityDedupeIdx))
+	for k := range maxMindCityDedupeIdx {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 
-		if strings.Contains(cn, "!") || strings.Contains(cn, "@") {
+	for _, k := range keys {
+		gc, ok := parseMaxMindCityFields(maxMindCityDedupeIdx[k])
+		if !ok {
 			continue
 		}
+		c, err := gc.toCity()
+		if err != nil {
+			return err
+		}
 
 		// Use lat/lng as dedup key instead of geohash
-		dedupeKey := fmt.Sprintf("%.4f,%.4f", lat, lng)
+		dedupeKey := fmt.Sprintf("%.4f,%.4f", c.Latitude, c.Longitude)
 		if _, ok := locationDedupeIdx[dedupeKey]; !ok {
 			locationDedupeIdx[dedupeKey] = true
-
-			c := GeobedCity{
-				City:       cn,
-				country:    internCountry(toUpper(fields[0])),
-				region:     internRegion(fields[3]),
-				Latitude:   float32(lat),
-				Longitude:  float32(lng),
-				Population: int32(pop),
-			}
-
-			if len(c.City) > 0 && c.country != 0 {
-				g.Cities = append(g.Cities, c)
-			}
+			g.Cities = append(g.Cities, c)
 		}
 	}
 
 	return nil
 }
 
+// parseMaxMindCityFields converts the seven comma-separated fields of a
+// MaxMind world cities line into a city. Returns false for header rows,
+// unparseable coordinates, and names containing junk characters.
+func parseMaxMindCityFields(fields []string) (geobedCityGob, bool) {
+	if len(fields) != 7 || fields[0] == "" || fields[0] == "0" || fields[2] == "AccentCity" {
+		return geobedCityGob{}, false
+	}
+
+	pop, _ := strconv.Atoi(fields[4])
+	// Parse coordinates with error handling to avoid "Null Island" (0,0) entries
+	lat, errLat := strconv.ParseFloat(fields[5], 32)
+	lng, errLng := strconv.ParseFloat(fields[6], 32)
+	if errLat != nil || errLng != nil || !validCoordinates(lat, lng) {
+		return geobedCityGob{}, false // Skip records with unparseable coordinates
+	}
+
+	cn := strings.Trim(fields[2], " ")
+	cn = strings.Trim(cn, "( )")
+
+	if strings.Contains(cn, "!") || strings.Contains(cn, "@") {
+		return geobedCityGob{}, false
+	}
+
+	c := geobedCityGob{
+		City:       cn,
+		Country:    toUpper(fields[0]),
+		Region:     fields[3],
+		Latitude:   float32(lat),
+		Longitude:  float32(lng),
+		Population: int32(pop),
+		Source:     SourceMaxMind,
+	}
+	return c, len(c.City) > 0 && c.Country != ""
+}
+
 func (g *GeoBed) loadGeonamesCountryInfo(path string) error {
 	fi, err := os.Open(path)
 	if err != nil {
@@ -768,8 +1400,12 @@ func (g *GeoBed) loadGeonamesCountryInfo(path string) error {
 	scanner := bufio.NewScanner(fi)
 	scanner.Split(bufio.ScanLines)
 
+	schema := newSchemaChecker(countryInfoSchema, path)
 	for scanner.Scan() {
 		t := scanner.Text()
+		if err := schema.check(t); err != nil {
+			return err
+		}
 		if len(t) == 0 || t[0] == '#' {
 			continue
 		}
@@ -836,56 +1472,101 @@ func fuzzyMatch(query, candidate string, maxDist int) bool {
 
 // Geocode performs forward geocoding, converting a location string to coordinates.
 func (g *GeoBed) Geocode(n string, opts ...GeocodeOptions) GeobedCity {
-	var c GeobedCity
-	n = strings.TrimSpace(n)
+	return g.GeocodeDetailed(n, opts...).City
+}
+
+// GeocodeDetailed is like Geocode but also reports how the result was
+// obtained, e.g. whether GeocodeOptions.Deadline cut matching short, or
+// why nothing matched.
+func (g *GeoBed) GeocodeDetailed(n string, opts ...GeocodeOptions) (r GeocodeResult) {
+	r = GeocodeResult{Dataset: g.dataset, DatasetDate: g.datasetDate}
+	n, truncated := g.cleanQuery(n)
 	if n == "" {
-		return c
+		r.MissReason = MissEmptyQuery
+		return r
 	}
-
-	// Truncate excessively long inputs to prevent algorithmic complexity attacks
-	// on Levenshtein distance calculations. Use runes to avoid breaking UTF-8.
-	if runes := []rune(n); len(runes) > maxGeocodeInputLen {
-		n = string(runes[:maxGeocodeInputLen])
+	if g.config != nil && g.config.MissHook != nil {
+		defer func() { g.reportMiss(n, r) }()
+	}
+	if truncated {
+		defer func() {
+			if r.City.City == "" {
+				r.MissReason = MissInputTruncated
+			}
+		}()
 	}
 
 	options := GeocodeOptions{}
 	if len(opts) > 0 {
 		options = opts[0]
 	}
+	options.Country = toUpper(strings.TrimSpace(options.Country))
+	if c, ok := g.exactFastPath(n, options); ok {
+		r.City = c
+		return r
+	}
 
 	// Cap FuzzyDistance to prevent excessive O(N) scans of the name index.
 	if options.FuzzyDistance > maxFuzzyDistance {
 		options.FuzzyDistance = maxFuzzyDistance
 	}
 
+	options, release, degraded := g.acquireFuzzySlot(options)
+	defer release()
+
 	if options.ExactCity {
-		c = g.exactMatchCity(n)
+		r.City, r.MissReason = g.exactMatchCity(n, options.Country)
 	} else {
-		c = g.fuzzyMatchLocation(n, options)
+		r = g.fuzzyMatchLocation(n, options)
+		r.Dataset, r.DatasetDate = g.dataset, g.datasetDate
 	}
-	return c
+	r.Partial = r.Partial || degraded
+	return r
+}
+
+// cleanQuery applies the query preprocessor, trims surrounding space, and
+// truncates excessively long inputs, reporting whether it did, to prevent algorithmic complexity
+// attacks on Levenshtein distance calculations. Truncation counts runes to
+// avoid breaking UTF-8.
+func (g *GeoBed) cleanQuery(n string) (string, bool) {
+	n = strings.TrimSpace(g.preprocessQuery(n))
+	if utf8.RuneCountInString(n) > maxGeocodeInputLen {
+		return string([]rune(n)[:maxGeocodeInputLen]), true
+	}
+	return n, false
 }
 
-func (g *GeoBed) exactMatchCity(n string) GeobedCity {
+func (g *GeoBed) exactMatchCity(n, country string) (GeobedCity, MissReason) {
 	var c GeobedCity
 	nCo, nSt, _, nSlice := g.extractLocationPieces(n)
 	nWithoutAbbrev := strings.Join(nSlice, " ")
+	if nCo == "" {
+		nCo = country
+	}
+	lookup := g.nameLookup(country)
 
 	// Collect candidates from inverted index.
 	// First lookup uses full original query `n` as a fallback for queries
 	// without location context (e.g., just "Austin").
 	candidateSet := make(map[int]bool)
-	if indices, ok := g.nameIndex[toLower(n)]; ok {
-		for _, idx := range indices {
+	for _, idx := range lookup(g.nameKey(n)) {
+		candidateSet[idx] = true
+	}
+	if nWithoutAbbrev != n {
+		for _, idx := range lookup(g.nameKey(nWithoutAbbrev)) {
 			candidateSet[idx] = true
 		}
 	}
-	if nWithoutAbbrev != n {
-		if indices, ok := g.nameIndex[toLower(nWithoutAbbrev)]; ok {
-			for _, idx := range indices {
-				candidateSet[idx] = true
-			}
+	if len(candidateSet) == 0 {
+		if country != "" && g.hitsElsewhere(n, nWithoutAbbrev) {
+			return c, MissCountryFiltered
 		}
+		return c, MissNoIndexHit
+	}
+
+	g.filterCandidates(candidateSet)
+	if len(candidateSet) == 0 {
+		return c, MissResultFiltered
 	}
 
 	matchingCities := []GeobedCity{}
@@ -897,7 +1578,7 @@ func (g *GeoBed) exactMatchCity(n string) GeobedCity {
 	}
 
 	if len(matchingCities) == 1 {
-		return matchingCities[0]
+		return matchingCities[0], MissNone
 	} else if len(matchingCities) > 1 {
 		// Find best match by region, using population as tie-breaker
 		for _, city := range matchingCities {
@@ -943,141 +1624,202 @@ func (g *GeoBed) exactMatchCity(n string) GeobedCity {
 			}
 		}
 	}
-	return c
+	if c.City == "" {
+		return c, MissNoMatch
+	}
+	return c, MissNone
+}
+
+func (g *GeoBed) fuzzyMatchLocation(n string, opts GeocodeOptions) GeocodeResult {
+	rk := g.rankLocation(n, opts, false)
+	if rk.best < 0 {
+		return GeocodeResult{Partial: rk.partial, MissReason: rk.miss}
+	}
+	return GeocodeResult{City: g.Cities[rk.best], Partial: rk.partial, HintMismatch: rk.mismatch}
+}
+
+// locationRanking is the outcome of ranking the candidates for a query.
+type locationRanking struct {
+	scores   map[int]int // City index → final score, for positively scored candidates
+	best     int         // City index Geocode returns, -1 for none
+	partial  bool        // Deadline cut matching short
+	mismatch bool        // best lies outside the named region or country
+	miss     MissReason  // Why best is -1
 }
 
-func (g *GeoBed) fuzzyMatchLocation(n string, opts GeocodeOptions) GeobedCity {
+// rankLocation collects and scores the candidates for query n and picks
+// the best. Unless all is set, a "City, ST" query matching a city exactly
+// is resolved without scoring, leaving scores nil; with all every candidate
+// is scored and that city is still the pick.
+func (g *GeoBed) rankLocation(n string, opts GeocodeOptions, all bool) locationRanking {
 	nCo, nSt, abbrevSlice, nSlice := g.extractLocationPieces(n)
+	if opts.FuzzyDistance > 0 && (nCo == "" || nSt == "") {
+		nCo, nSt, nSlice = g.fuzzyLocationSuffix(nCo, nSt, nSlice, opts.FuzzyDistance)
+	}
+	if nCo == "" {
+		nCo = opts.Country
+	}
+	lookup := g.nameLookup(opts.Country)
 
 	// Collect candidates from inverted index
 	candidateSet := make(map[int]bool)
 
 	// Look up full original query
-	if indices, ok := g.nameIndex[toLower(n)]; ok {
-		for _, idx := range indices {
-			candidateSet[idx] = true
-		}
+	for _, idx := range lookup(g.nameKey(n)) {
+		candidateSet[idx] = true
 	}
 
 	// Look up cleaned query (after country/state extraction)
 	cleanedQuery := strings.Join(nSlice, " ")
 	if cleanedQuery != n {
-		if indices, ok := g.nameIndex[toLower(cleanedQuery)]; ok {
-			for _, idx := range indices {
-				candidateSet[idx] = true
-			}
+		for _, idx := range lookup(g.nameKey(cleanedQuery)) {
+			candidateSet[idx] = true
 		}
 	}
 
 	// Look up each name slice part
 	for _, ns := range nSlice {
 		ns = strings.TrimSuffix(ns, ",")
-		key := toLower(ns)
-		if indices, ok := g.nameIndex[key]; ok {
-			for _, idx := range indices {
-				candidateSet[idx] = true
-			}
+		for _, idx := range lookup(g.nameKey(ns)) {
+			candidateSet[idx] = true
 		}
 	}
 
 	// If fuzzy matching enabled, scan nameIndex keys for close matches
+	partial := false
+	hasDeadline := !opts.Deadline.IsZero()
 	if opts.FuzzyDistance > 0 {
-		for key, indices := range g.nameIndex {
-			for _, ns := range nSlice {
-				ns = strings.TrimSuffix(ns, ",")
-				if len(ns) > 2 && fuzzyMatch(ns, key, opts.FuzzyDistance) {
+		// Normalize the query tokens once rather than per index key. Tokens
+		// with cached scan results are not scanned again.
+		var fuzzyKeys []string
+		for _, ns := range nSlice {
+			if ns = strings.TrimSuffix(ns, ","); len(ns) > 2 {
+				key := g.nameKey(ns)
+				if cached, ok := g.fuzzyCache.get(fuzzyScanKey{key, opts.FuzzyDistance}); ok {
+					for _, idx := range cached {
+						candidateSet[idx] = true
+					}
+					continue
+				}
+				fuzzyKeys = append(fuzzyKeys, key)
+			}
+		}
+		var found [][]int // per fuzzy key, recorded for the cache
+		if g.fuzzyCache != nil {
+			found = make([][]int, len(fuzzyKeys))
+		}
+		var byLen [][]string
+		if len(fuzzyKeys) > 0 {
+			byLen = g.keysByLength()
+		}
+		scanned := 0
+	scan:
+		for i, ns := range fuzzyKeys {
+			n := utf8.RuneCountInString(toLower(ns))
+			for l := max(n-opts.FuzzyDistance, 0); l <= n+opts.FuzzyDistance && l < len(byLen); l++ {
+				for _, key := range byLen[l] {
+					if hasDeadline && scanned%deadlineCheckInterval == 0 && time.Now().After(opts.Deadline) {
+						partial = true
+						break scan
+					}
+					scanned++
+					if !fuzzyMatch(ns, key, opts.FuzzyDistance) {
+						continue
+					}
+					indices := g.nameIndex[key]
 					for _, idx := range indices {
 						candidateSet[idx] = true
 					}
+					if found != nil {
+						found[i] = append(found[i], indices...)
+					}
 				}
 			}
 		}
+		if found != nil && !partial {
+			for i, key := range fuzzyKeys {
+				g.fuzzyCache.add(fuzzyScanKey{key, opts.FuzzyDistance}, found[i])
+			}
+		}
 	}
 
-	bestMatchingKeys := map[int]int{}
-	bestMatchingKey := -1
-
-	for currentKey := range candidateSet {
-		v := g.Cities[currentKey]
-		vCountry := v.Country()
-		vRegion := v.Region()
-
-		// Fast path for simple "City, ST" format
-		if nSt != "" {
-			if strings.EqualFold(cleanedQuery, v.City) && strings.EqualFold(nSt, vRegion) {
-				return v
-			}
+	// Why nothing may match, from the first stage that leaves no candidate.
+	miss := MissNoMatch
+	if len(candidateSet) == 0 {
+		miss = MissNoIndexHit
+		if opts.Country != "" && g.hitsElsewhere(append([]string{n, cleanedQuery}, nSlice...)...) {
+			miss = MissCountryFiltered
 		}
+	}
+	g.filterCountry(candidateSet, opts.Country)
+	if miss == MissNoMatch && len(candidateSet) == 0 {
+		miss = MissCountryFiltered
+	}
+	g.filterCandidates(candidateSet)
+	if miss == MissNoMatch && len(candidateSet) == 0 {
+		miss = MissResultFiltered
+	}
 
-		for _, av := range abbrevSlice {
-			if len(av) == 2 && strings.EqualFold(vRegion, av) {
-				bestMatchingKeys[currentKey] += 5
-			}
-			if len(av) == 2 && strings.EqualFold(vCountry, av) {
-				bestMatchingKeys[currentKey] += 3
+	ranker, custom := g.ranker()
+	q := ParsedQuery{
+		Raw:           n,
+		Cleaned:       cleanedQuery,
+		Country:       nCo,
+		Neighbours:    g.neighbours[nCo],
+		State:         nSt,
+		Abbrevs:       abbrevSlice,
+		Names:         nSlice,
+		FuzzyDistance: opts.FuzzyDistance,
+	}
+
+	// Fast path for simple "City, ST" format. Several cities can match (e.g.,
+	// a supplemental place sharing a name with a base-dataset city), so pick
+	// the most populous, then the lowest index, independent of map order.
+	// Custom rankers see every candidate instead.
+	fastPick := -1
+	if nSt != "" && !custom {
+		fast := -1
+		for k := range candidateSet {
+			v := g.Cities[k]
+			if !strings.EqualFold(cleanedQuery, v.City) || !strings.EqualFold(nSt, v.Region()) {
+				continue
 			}
-			if len(av) == 3 && strings.EqualFold(vRegion, av) {
-				bestMatchingKeys[currentKey] += 4
+			if fast < 0 || v.Population > g.Cities[fast].Population ||
+				(v.Population == g.Cities[fast].Population && k < fast) {
+				fast = k
 			}
 		}
-
-		if nCo != "" {
-			if nCo == vCountry {
-				bestMatchingKeys[currentKey] += 4
-			} else {
-				// Country mismatch penalty: when the user explicitly specified a
-				// country (e.g., "Bogota, Colombia"), wrong-country candidates should
-				// score lower to prevent same-name cities from winning via exact-match
-				// bonuses alone.
-				bestMatchingKeys[currentKey] -= 5
-			}
+		if fast >= 0 && !all {
+			g.traceScores(q, nil, fast, true, partial)
+			return locationRanking{best: fast, partial: partial}
 		}
-
-		if nSt != "" && nSt == vRegion {
-			bestMatchingKeys[currentKey] += 4
+		if fast >= 0 {
+			fastPick = fast
 		}
+	}
 
-		// Alt name matching — split on commas, not whitespace
-		if v.CityAlt != "" {
-			for _, raw := range strings.Split(v.CityAlt, ",") {
-				altV := strings.TrimSpace(raw)
-				if altV == "" {
-					continue
-				}
-				if strings.EqualFold(altV, cleanedQuery) {
-					bestMatchingKeys[currentKey] += 3
-				}
-				if altV == cleanedQuery {
-					bestMatchingKeys[currentKey] += 5
-				}
-			}
-		}
+	bestMatchingKeys := map[int]int{}
+	bestMatchingKey := -1
 
-		// Exact match gets highest bonus
-		if strings.EqualFold(cleanedQuery, v.City) {
-			bestMatchingKeys[currentKey] += 7
-		} else if opts.FuzzyDistance > 0 {
-			// Fuzzy matching with Levenshtein distance
-			for _, ns := range nSlice {
-				ns = strings.TrimSuffix(ns, ",")
-				if len(ns) > 2 && fuzzyMatch(ns, v.City, opts.FuzzyDistance) {
-					bestMatchingKeys[currentKey] += 5
-				}
-			}
+	scored := 0
+	for currentKey := range candidateSet {
+		if hasDeadline && scored%deadlineCheckInterval == 0 && time.Now().After(opts.Deadline) {
+			partial = true
+			break
 		}
-
-		for _, ns := range nSlice {
-			ns = strings.TrimSuffix(ns, ",")
-			if strings.Contains(toLower(v.City), toLower(ns)) {
-				bestMatchingKeys[currentKey] += 2
-			}
-			if strings.EqualFold(v.City, ns) {
-				bestMatchingKeys[currentKey] += 1
-			}
+		scored++
+		// A zero score means no signal at all; such candidates are left out
+		// so the population bonus below cannot promote them.
+		if score := ranker.Score(q, g.Cities[currentKey]); score != 0 {
+			bestMatchingKeys[currentKey] = score
 		}
 	}
 
-	if nCo == "" {
+	if nCo != "" && !custom {
+		g.preferNeighbours(nCo, bestMatchingKeys)
+	}
+
+	if nCo == "" && !custom {
 		hp := int32(0)
 		hpk := -1
 		for k, v := range bestMatchingKeys {
@@ -1109,12 +1851,49 @@ func (g *GeoBed) fuzzyMatchLocation(n string, opts GeocodeOptions) GeobedCity {
 		}
 	}
 
+	mismatch := false
+	if fastPick >= 0 {
+		bestMatchingKey = fastPick
+	} else {
+		bestMatchingKey, mismatch = g.checkHints(nCo, nSt, bestMatchingKeys, bestMatchingKey)
+	}
+
+	g.traceScores(q, bestMatchingKeys, bestMatchingKey, false, partial)
+
 	// No match found — return empty city instead of cities[0]
-	if bestMatchingKey < 0 {
-		return GeobedCity{}
+	if bestMatchingKey < 0 && partial {
+		miss = MissDeadline
 	}
+	return locationRanking{scores: bestMatchingKeys, best: bestMatchingKey, partial: partial, mismatch: mismatch, miss: miss}
+}
 
-	return g.Cities[bestMatchingKey]
+// neighbourBonus is added to the score of candidates in a country
+// bordering the one a query names, when that country has no candidate. It
+// outweighs the bonuses of an exact over an alternate-name match, but not
+// a region or abbreviation match.
+const neighbourBonus = 3
+
+// preferNeighbours handles a query naming a country that has no candidate
+// at all, often a border mix-up such as "Geneva, France" or "Basel,
+// France". Candidates in countries bordering nCo get neighbourBonus, so
+// Genève CH wins over the equally named but distant Geneva, NY, while
+// distant candidates stay in scores for GeocodeAll and a clearly better
+// match elsewhere can still win.
+func (g *GeoBed) preferNeighbours(nCo string, scores map[int]int) {
+	neighbours := g.neighbours[nCo]
+	if len(neighbours) == 0 {
+		return
+	}
+	for k := range scores {
+		if g.Cities[k].Country() == nCo {
+			return
+		}
+	}
+	for k := range scores {
+		if slices.Contains(neighbours, g.Cities[k].Country()) {
+			scores[k] += neighbourBonus
+		}
+	}
 }
 
 // abbrevRegex is compiled once for extracting standalone 2-3 letter tokens
@@ -1123,137 +1902,86 @@ var abbrevRegex = sync.OnceValue(func() *regexp.Regexp {
 	return regexp.MustCompile(`\b[A-Za-z]{2,3}\b`)
 })
 
-func (g *GeoBed) extractLocationPieces(n string) (string, string, []string, []string) {
-	abbrevSlice := abbrevRegex().FindAllString(n, -1)
+// locationPieces is the cached result of parsing a raw query. The slices are
+// shared between callers and must not be modified.
+type locationPieces struct {
+	country, state string
+	abbrevs, names []string
+}
 
-	nCo := ""
-	// Check for country names using string operations (safe, fast)
-	for _, co := range g.Countries {
-		countryName := co.Country
-		countryNameLower := toLower(countryName)
-		nLower := toLower(n)
-
-		// Check exact match: "France"
-		// Keep n unchanged so it can match city names (e.g., "Singapore" is both
-		// a country and a city). The country scoring (+4 for nCo match) will
-		// still prefer cities in the matched country.
-		if strings.EqualFold(n, countryName) {
-			nCo = co.ISO
-			break
-		}
+// QueryCacheStats reports hit/miss counts of the parsed-query cache enabled
+// by WithQueryCache. All fields are zero when the cache is disabled.
+func (g *GeoBed) QueryCacheStats() CacheStats {
+	return g.queryCache.stats()
+}
 
-		// Check prefix: "France, Paris" -> match "France, "
-		prefixWithComma := countryNameLower + ", "
-		if len(nLower) > len(prefixWithComma) && nLower[:len(prefixWithComma)] == prefixWithComma {
-			nCo = co.ISO
-			n = n[len(prefixWithComma):]
-			break
-		}
-		prefixWithSpace := countryNameLower + " "
-		if len(nLower) > len(prefixWithSpace) && nLower[:len(prefixWithSpace)] == prefixWithSpace {
-			nCo = co.ISO
-			n = n[len(prefixWithSpace):]
-			break
-		}
+// extractLocationPieces splits a query into country code, state code,
+// abbreviation tokens, and remaining name tokens, consulting the query cache
+// when enabled.
+func (g *GeoBed) extractLocationPieces(n string) (string, string, []string, []string) {
+	if p, ok := g.queryCache.get(n); ok {
+		return p.country, p.state, p.abbrevs, p.names
+	}
+	nCo, nSt, abbrevSlice, nSlice := g.parseLocationPieces(n)
+	g.queryCache.add(n, locationPieces{nCo, nSt, abbrevSlice, nSlice})
+	return nCo, nSt, abbrevSlice, nSlice
+}
 
-		// Check suffix: "Paris, France" -> match ", France"
-		suffixWithComma := ", " + countryNameLower
-		if len(nLower) > len(suffixWithComma) && nLower[len(nLower)-len(suffixWithComma):] == suffixWithComma {
-			nCo = co.ISO
-			n = n[:len(n)-len(suffixWithComma)]
-			break
-		}
-		suffixWithSpace := " " + countryNameLower
-		if len(nLower) > len(suffixWithSpace) && nLower[len(nLower)-len(suffixWithSpace):] == suffixWithSpace {
-			nCo = co.ISO
-			n = n[:len(n)-len(suffixWithSpace)]
-			break
-		}
+// parseLocationPieces does the uncached work of extractLocationPieces.
+func (g *GeoBed) parseLocationPieces(n string) (string, string, []string, []string) {
+	abbrevSlice := abbrevTokens(n)
+
+	if nCo, nSt, names, ok := g.parseComponents(n); ok {
+		return nCo, nSt, abbrevSlice, strings.Split(strings.Join(names, ", "), " ")
 	}
 
-	nSt := ""
-	// Check US state codes using string operations (safe, fast).
-	// Iterate over sorted keys for deterministic matching order.
-	for _, sc := range sortedUsStateCodes() {
-		scLower := toLower(sc)
-		nLower := toLower(n)
-
-		// Exact match: "TX"
-		if nLower == scLower {
-			nSt = sc
-			n = ""
-			if nCo == "" {
-				nCo = "US"
-			}
-			break
-		}
+	nLower := toLower(n)
 
-		// Prefix: "TX, Austin" or "TX Austin"
-		prefixWithComma := scLower + ", "
-		if len(nLower) > len(prefixWithComma) && nLower[:len(prefixWithComma)] == prefixWithComma {
-			nSt = sc
-			n = n[len(prefixWithComma):]
-			if nCo == "" {
-				nCo = "US"
-			}
-			break
-		}
-		prefixWithSpace := scLower + " "
-		if len(nLower) > len(prefixWithSpace) && nLower[:len(prefixWithSpace)] == prefixWithSpace {
-			nSt = sc
-			n = n[len(prefixWithSpace):]
-			if nCo == "" {
-				nCo = "US"
-			}
-			break
+	nCo := ""
+	// Check for country names: exact ("France"), prefix ("France, Paris"),
+	// or suffix ("Paris, France"). An exact match keeps n unchanged so it can
+	// still match city names (e.g., "Singapore" is both a country and a city);
+	// the country scoring (+4 for nCo match) will prefer cities in it.
+	if m, ok := matchAffix(nLower, g.countryKeys, true); ok {
+		nCo = g.Countries[m.rank].ISO
+		if m.kind != affixExact {
+			n, nLower = m.cut(n, nLower)
 		}
+	}
 
-		// Suffix: "Austin, TX" or "Austin TX"
-		suffixWithComma := ", " + scLower
-		if len(nLower) > len(suffixWithComma) && nLower[len(nLower)-len(suffixWithComma):] == suffixWithComma {
-			nSt = sc
-			n = n[:len(n)-len(suffixWithComma)]
-			if nCo == "" {
-				nCo = "US"
-			}
-			break
+	nSt := ""
+	stateByCode := false
+	// Check US state codes: exact ("TX"), prefix ("TX, Austin" or "TX Austin"),
+	// or suffix ("Austin, TX" or "Austin TX"). Ties resolve in sorted code
+	// order for deterministic matching.
+	if m, ok := matchAffix(nLower, usStateCodeKeys(), true); ok {
+		nSt = sortedUsStateCodes()[m.rank]
+		stateByCode = true
+		if m.kind == affixExact {
+			n, nLower = "", ""
+		} else {
+			n, nLower = m.cut(n, nLower)
 		}
-		suffixWithSpace := " " + scLower
-		if len(nLower) > len(suffixWithSpace) && nLower[len(nLower)-len(suffixWithSpace):] == suffixWithSpace {
-			nSt = sc
-			n = n[:len(n)-len(suffixWithSpace)]
-			if nCo == "" {
-				nCo = "US"
-			}
-			break
+	} else if m, ok := matchAffix(nLower, usStateNameKeys(), false); ok {
+		// No 2-letter code matched; check full US state names ("Austin, Texas")
+		nSt = sortedUsStateCodes()[m.rank]
+		n, nLower = m.cut(n, nLower)
+	}
+	if nSt != "" && nCo == "" {
+		switch {
+		case stateByCode && g.preferCountryCode(n, nSt):
+			// "Córdoba, AR": Argentina, not Arkansas.
+			nCo, nSt = nSt, ""
+		case !g.config.NoUSDefault:
+			nCo = "US"
 		}
 	}
 
-	// If no 2-letter code matched, check full US state names (e.g., "Austin, Texas")
-	if nSt == "" {
-		for _, sc := range sortedUsStateCodes() {
-			stateName := UsStateCodes[sc]
-			stateNameLower := toLower(stateName)
-			nLower := toLower(n)
-
-			suffixWithComma := ", " + stateNameLower
-			if len(nLower) > len(suffixWithComma) && nLower[len(nLower)-len(suffixWithComma):] == suffixWithComma {
-				nSt = sc
-				n = n[:len(n)-len(suffixWithComma)]
-				if nCo == "" {
-					nCo = "US"
-				}
-				break
-			}
-			suffixWithSpace := " " + stateNameLower
-			if len(nLower) > len(suffixWithSpace) && nLower[len(nLower)-len(suffixWithSpace):] == suffixWithSpace {
-				nSt = sc
-				n = n[:len(n)-len(suffixWithSpace)]
-				if nCo == "" {
-					nCo = "US"
-				}
-				break
-			}
+	// Trailing ISO 3166 codes are country filters just like full names
+	// ("Paris, FR", "Berlin DEU").
+	if nCo == "" && nSt == "" {
+		if co, rest, ok := g.trailingCountryCode(n); ok {
+			nCo, n = co, rest
 		}
 	}
 
@@ -1287,57 +2015,290 @@ func (g *GeoBed) extractLocationPieces(n string) (string, string, []string, []st
 	return nCo, nSt, abbrevSlice, nSlice
 }
 
-// maxReverseGeocodeDistance is ~100km in radians on the unit sphere.
-// Reverse geocode returns empty result when closest city exceeds this distance.
-const maxReverseGeocodeDistance = 0.0157
+// affixKind is how a known name appears in a query. When one name matches in
+// several ways, the lowest kind wins.
+type affixKind int
 
-// nearbyThreshold is ~10km in radians on the unit sphere.
-// Used for the neighborhood override: if the closest match is a small city,
-// check whether a much larger city exists within this distance.
-const nearbyThreshold = 0.00157
+const (
+	affixExact       affixKind = iota // "france"
+	affixPrefixComma                  // "france, paris"
+	affixPrefixSpace                  // "france paris"
+	affixSuffixComma                  // "paris, france"
+	affixSuffixSpace                  // "paris france"
+)
+
+// affixMatch is a name found by matchAffix. The name occupies nLower outside
+// [start, end); for affixExact the range is empty.
+type affixMatch struct {
+	rank       int
+	kind       affixKind
+	start, end int
+}
+
+// cut removes the matched name and its separator from n and nLower.
+func (m affixMatch) cut(n, nLower string) (string, string) {
+	head, tail := m.start, len(nLower)-m.end
+	if len(n) == len(nLower) {
+		return n[head : len(n)-tail], nLower[m.start:m.end]
+	}
+	// Lowercasing changed the byte length (rare non-ASCII input), so offsets
+	// into nLower do not line up with n; trim by the same byte counts.
+	if head+tail > len(n) {
+		return "", ""
+	}
+	n = n[head : len(n)-tail]
+	return n, toLower(n)
+}
+
+// matchAffix looks for a key of names (lowercase name → rank) that equals
+// nLower or is separated from the rest of it by ", " or " " at the start or
+// end. It tries each separator position once instead of comparing every name
+// against the query, and returns the match with the lowest rank, then the
+// lowest kind. Exact and prefix matches are only considered when prefixes is
+// true. The remainder must be non-empty for prefix and suffix matches.
+func matchAffix(nLower string, names map[string]int, prefixes bool) (affixMatch, bool) {
+	best := affixMatch{rank: -1}
+	try := func(key string, kind affixKind, start, end int) {
+		rank, ok := names[key]
+		if !ok {
+			return
+		}
+		if best.rank < 0 || rank < best.rank || (rank == best.rank && kind < best.kind) {
+			best = affixMatch{rank: rank, kind: kind, start: start, end: end}
+		}
+	}
+
+	if prefixes {
+		try(nLower, affixExact, 0, 0)
+	}
+	for i := 1; i < len(nLower)-1; i++ {
+		if nLower[i] != ' ' {
+			continue
+		}
+		if nLower[i-1] == ',' {
+			if prefixes {
+				try(nLower[:i-1], affixPrefixComma, i+1, len(nLower))
+			}
+			if i > 1 {
+				try(nLower[i+1:], affixSuffixComma, 0, i-1)
+			}
+		}
+		if prefixes {
+			try(nLower[:i], affixPrefixSpace, i+1, len(nLower))
+		}
+		try(nLower[i+1:], affixSuffixSpace, 0, i)
+	}
+	return best, best.rank >= 0
+}
+
+// earthRadiusKm is the mean Earth radius used to convert S2 angles to distances.
+const earthRadiusKm = 6371.0088
+
+// maxReverseGeocodeKm is the reverse geocoding range: the result is empty
+// when the closest city is farther away.
+const maxReverseGeocodeKm = 100
+
+// nearbyKm is the default radius of the neighborhood override: if the
+// closest match is a small city, a much larger city within this distance
+// replaces it. See MetroOverride.
+const nearbyKm = 10
+
+// distanceKm returns the great-circle distance between two points.
+func distanceKm(a, b s2.LatLng) float64 {
+	return a.Distance(b).Radians() * earthRadiusKm
+}
+
+// cityLatLng returns the location of c.
+func cityLatLng(c GeobedCity) s2.LatLng {
+	return s2.LatLngFromDegrees(float64(c.Latitude), float64(c.Longitude))
+}
 
 // reverseCandidate pairs a city with its distance from the query point.
 type reverseCandidate struct {
 	city GeobedCity
-	dist float64
+	idx  int // Index in Cities
+	km   float64
+}
+
+// ReverseGeocodeOptions configures reverse geocoding behavior.
+type ReverseGeocodeOptions struct {
+	// IncludeNeighborhoods returns the nearest populated place, however
+	// small (e.g., the district "Mitte"), instead of applying the metro
+	// override that prefers a much larger city nearby (e.g., "Berlin").
+	IncludeNeighborhoods bool
+
+	// MinPopulation ignores cities with fewer inhabitants, e.g. to label a
+	// map point with the nearest town of at least 100,000 people. Only
+	// cities within the usual reverse geocoding range are considered; the
+	// lookup uses a precomputed population-tier index rather than filtering
+	// every nearby city. Zero disables the filter.
+	MinPopulation int32
+
+	// NearestIfRemote returns the nearest city, however far, for remote
+	// points instead of an empty result; see ReverseGeocodeResult.Remote.
+	// Far from any city the search covers thousands of km and takes
+	// milliseconds.
+	NearestIfRemote bool
+
+	// Metro tunes the metro override for this call; zero fields fall back
+	// to WithMetroOverride and then the defaults.
+	Metro MetroOverride
+
+	// FallbackLevel makes ReverseGeocodeDetailed report the country or
+	// continent of points no city is within range of, instead of an empty
+	// result; see ReverseGeocodeResult.Fallback. ReverseGeocode ignores it.
+	FallbackLevel FallbackLevel
+}
+
+// ReverseGeocode converts lat/lng coordinates to a city location. Points
+// with no city in the surrounding level-10 S2 cells return the zero city.
+func (g *GeoBed) ReverseGeocode(lat, lng float64, opts ...ReverseGeocodeOptions) GeobedCity {
+	options := ReverseGeocodeOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	nearest, metro, _ := g.reverseLookup(lat, lng, options)
+	if options.IncludeNeighborhoods {
+		return nearest
+	}
+	return metro
+}
+
+// ReverseGeocodeResult is the detailed outcome of a reverse geocode.
+type ReverseGeocodeResult struct {
+	Locality GeobedCity // Nearest populated place (ReverseGeocode with IncludeNeighborhoods)
+	Metro    GeobedCity // City after the metro override (plain ReverseGeocode)
+
+	// LocalityKm and MetroKm are the great-circle distances from the point
+	// to Locality and Metro, 0 when they are empty. Unless NearestIfRemote
+	// found a city for a remote point, both are within the 100 km reverse
+	// geocoding range.
+	LocalityKm float64
+	MetroKm    float64
+
+	// Remote reports that no city lies in the level-7 S2 cell (roughly
+	// 50-100km across) of the point or the cells around it. Geobed embeds
+	// no coastlines, so this covers the open ocean and sparsely populated
+	// land such as interior Australia, the Sahara, and Greenland alike.
+	// Locality and Metro are empty unless
+	// ReverseGeocodeOptions.NearestIfRemote is set, in which case both are
+	// the nearest city.
+	Remote bool
+
+	// Marine reports that a remote point lies in the Exclusive Economic
+	// Zone of the country in Waters, as loaded with WithEEZFile. Waters is
+	// the zero CountryInfo otherwise; Zone names the zone as in the file.
+	Marine bool
+	Waters CountryInfo
+	Zone   string
+
+	// Fallback reports that no city was found and, with
+	// ReverseGeocodeOptions.FallbackLevel, how coarsely the point was placed
+	// instead: FallbackCountry sets Country and Continent, FallbackContinent
+	// only Continent (a Geonames code such as "AF"). It is FallbackNone when
+	// a city was found or nothing is near enough.
+	Fallback  FallbackLevel
+	Country   CountryInfo
+	Continent string
+
+	Dataset     Dataset   // Dataset the cities come from; see GeoBed.Provenance
+	DatasetDate time.Time // Date of the dataset the cities come from; see GeoBed.DatasetDate
+}
+
+// ReverseGeocodeDetailed returns both the nearest populated place and the
+// metro city it belongs to from a single lookup, so callers can display e.g.
+// "Brooklyn (New York City)" without two queries that could disagree. The
+// two are the same city when no larger city is nearby. IncludeNeighborhoods
+// has no effect here.
+func (g *GeoBed) ReverseGeocodeDetailed(lat, lng float64, opts ...ReverseGeocodeOptions) ReverseGeocodeResult {
+	options := ReverseGeocodeOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	nearest, metro, remote := g.reverseLookup(lat, lng, options)
+	r := ReverseGeocodeResult{Locality: nearest, Metro: metro, Remote: remote, Dataset: g.dataset, DatasetDate: g.datasetDate}
+	if nearest.City != "" {
+		ll := s2.LatLngFromDegrees(lat, lng)
+		r.LocalityKm, r.MetroKm = distanceKm(ll, cityLatLng(nearest)), distanceKm(ll, cityLatLng(metro))
+	}
+	if remote {
+		if z, ok := g.eezAt(lat, lng); ok {
+			r.Marine, r.Waters, r.Zone = true, g.Countries[z.country], z.name
+		}
+	}
+	if metro.City == "" {
+		g.fallbackLocation(&r, lat, lng, options.FallbackLevel)
+	}
+	return r
 }
 
-// ReverseGeocode converts lat/lng coordinates to a city location.
-func (g *GeoBed) ReverseGeocode(lat, lng float64) GeobedCity {
+// reverseLookup returns the nearest populated place of at least
+// opts.MinPopulation inhabitants to lat/lng and the city after the
+// neighborhood override, which are the same city unless a much larger city
+// is close by. Both are empty when nothing is within range. remote reports
+// whether the point is far from any city, see ReverseGeocodeResult.Remote;
+// remote points are still searched like any other unless
+// opts.NearestIfRemote asks for the nearest city however far.
+func (g *GeoBed) reverseLookup(lat, lng float64, opts ReverseGeocodeOptions) (nearest, metro GeobedCity, remote bool) {
 	// Reject invalid float values that could cause undefined behavior
 	// in S2 geometry calculations.
 	if math.IsNaN(lat) || math.IsNaN(lng) ||
 		math.IsInf(lat, 0) || math.IsInf(lng, 0) {
-		return GeobedCity{}
+		return GeobedCity{}, GeobedCity{}, false
 	}
 
 	queryLL := s2.LatLngFromDegrees(lat, lng)
-	queryCell := s2.CellIDFromLatLng(queryLL).Parent(s2CellLevel)
+	g.ensureCellIndex()
+	remote = g.isRemote(queryLL)
+	if remote && opts.NearestIfRemote {
+		c := g.nearestCity(queryLL, opts.MinPopulation)
+		return c, c, true
+	}
 
-	var candidates []reverseCandidate
+	queryCell := s2.CellIDFromLatLng(queryLL).Parent(s2CellLevel)
+	near, best, ok := g.pickReverse(queryLL, g.cellCandidates(queryCell, opts.MinPopulation), opts.Metro)
+	if !ok {
+		return GeobedCity{}, GeobedCity{}, remote
+	}
+	return near.city, best.city, remote
+}
 
+// cellCandidates returns the indices of the cities with a population of at
+// least minPop in queryCell and its neighbors, the candidate set for any
+// point inside queryCell.
+func (g *GeoBed) cellCandidates(queryCell s2.CellID, minPop int32) []int {
+	index, filter := g.cellIndexFor(minPop)
+	var indices []int
 	for _, cell := range g.cellAndNeighbors(queryCell) {
-		indices, ok := g.cellIndex[cell]
-		if !ok {
-			continue
+		for _, i := range index[cell] {
+			if !filter || g.Cities[i].Population >= minPop {
+				indices = append(indices, i)
+			}
 		}
+	}
+	return indices
+}
 
-		for _, idx := range indices {
-			city := g.Cities[idx]
-			cityLL := s2.LatLngFromDegrees(float64(city.Latitude), float64(city.Longitude))
-			dist := float64(queryLL.Distance(cityLL))
-			candidates = append(candidates, reverseCandidate{city: city, dist: dist})
-		}
+// pickReverse selects the nearest candidate city to queryLL and the city
+// after the neighborhood override. ok is false when no candidate is within
+// maxReverseGeocodeKm.
+func (g *GeoBed) pickReverse(queryLL s2.LatLng, indices []int, metro MetroOverride) (nearest, best reverseCandidate, ok bool) {
+	candidates := make([]reverseCandidate, 0, len(indices))
+	for _, idx := range indices {
+		city := g.Cities[idx]
+		candidates = append(candidates, reverseCandidate{city: city, idx: idx, km: distanceKm(queryLL, cityLatLng(city))})
 	}
 
 	if len(candidates) == 0 {
-		return GeobedCity{}
+		return reverseCandidate{}, reverseCandidate{}, false
 	}
 
 	// Sort by distance, then population (desc), then city name for full determinism.
 	sort.SliceStable(candidates, func(i, j int) bool {
-		if candidates[i].dist != candidates[j].dist {
-			return candidates[i].dist < candidates[j].dist
+		if candidates[i].km != candidates[j].km {
+			return candidates[i].km < candidates[j].km
 		}
 		if candidates[i].city.Population != candidates[j].city.Population {
 			return candidates[i].city.Population > candidates[j].city.Population
@@ -1345,23 +2306,39 @@ func (g *GeoBed) ReverseGeocode(lat, lng float64) GeobedCity {
 		return candidates[i].city.City < candidates[j].city.City
 	})
 
-	best := candidates[0]
+	best = candidates[0]
 
 	// Max distance cutoff — return empty for remote coordinates
-	if best.dist > maxReverseGeocodeDistance {
-		return GeobedCity{}
+	if best.km > maxReverseGeocodeKm {
+		return reverseCandidate{}, reverseCandidate{}, false
 	}
+	nearest = best
 
-	// Neighborhood override: if closest is a small city (<500K pop),
-	// prefer the most populous nearby city within ~10km that has 10x+ the population.
-	if best.city.Population < 500_000 {
+	if decayKm, ok := g.metroCurve(metro); ok {
+		bestScore := metroScore(best, decayKm)
+		for _, c := range candidates[1:] {
+			if c.km > maxReverseGeocodeKm {
+				break
+			}
+			if s := metroScore(c, decayKm); s > bestScore {
+				best, bestScore = c, s
+			}
+		}
+		return nearest, best, true
+	}
+
+	// Neighborhood override: if closest is a small city (<500K pop by
+	// default), prefer the most populous nearby city within ~10km that has
+	// 10x+ the population. See MetroOverride.
+	maxPop, factor, radius := g.metroParams(metro)
+	if int64(best.city.Population) < maxPop {
 		var override *reverseCandidate
 		for i := range candidates[1:] {
 			c := &candidates[i+1]
-			if c.dist > nearbyThreshold {
+			if c.km > radius {
 				break
 			}
-			if c.city.Population > best.city.Population*10 {
+			if int64(c.city.Population) > int64(best.city.Population)*factor {
 				if override == nil || c.city.Population > override.city.Population {
 					override = c
 				}
@@ -1372,7 +2349,7 @@ func (g *GeoBed) ReverseGeocode(lat, lng float64) GeobedCity {
 		}
 	}
 
-	return best.city
+	return nearest, best, true
 }
 
 // toLower converts a string to lowercase using the standard library.
@@ -1404,26 +2381,83 @@ func toUpper(s string) string {
 // This is useful for updating the embedded cache after downloading fresh data.
 // The raw data files must exist in ./geobed-data/ before calling this function.
 //
+// Output is deterministic: two runs over the same raw files produce
+// byte-identical .dmp files. A manifest.json recording the SHA-256 of every
+// input and output file is written alongside them for auditing.
+//
+// Options such as WithAltNamePolicy shape the generated index.
+//
 // After running, compress the cache files with bzip2:
 //
 //	bzip2 -f geobed-cache/*.dmp
-func RegenerateCache() error {
-	g := &GeoBed{config: defaultConfig()}
+func RegenerateCache(opts ...Option) error {
+	_, err := RegenerateCacheWithStats(opts...)
+	return err
+}
+
+// CacheBuildStats describes a cache generated from raw data.
+type CacheBuildStats struct {
+	Cities        int          // Cities written
+	IndexKeys     int          // Distinct name index keys
+	IndexPostings int          // Total name → city references
+	RepeatedKeys  int          // Postings skipped because a city listed the same name twice
+	AltNames      AltNameStats // Alternate-name cleanup results
+
+	Cells         int // Occupied S2 cells of the reverse-geocoding index
+	MaxCellCities int // Most cities in a single cell
+
+	CacheDir string         // Directory the cache files were written to
+	Files    []ManifestFile // Cache files written, uncompressed, as in the manifest
+}
 
-	// Initialize lookup tables
-	lookupOnce.Do(initLookupTables)
+// AvgPostings returns the mean number of cities per name index key.
+func (s CacheBuildStats) AvgPostings() float64 {
+	if s.IndexKeys == 0 {
+		return 0
+	}
+	return float64(s.IndexPostings) / float64(s.IndexKeys)
+}
+
+// CellOccupancy returns the fraction of all S2 cells at the index level
+// that hold at least one city.
+func (s CacheBuildStats) CellOccupancy() float64 {
+	return float64(s.Cells) / float64(6<<(2*s2CellLevel))
+}
+
+// RegenerateCacheWithStats is RegenerateCache, additionally reporting the
+// size of the generated data and indexes and how much alternate-name
+// cleanup saved.
+func RegenerateCacheWithStats(opts ...Option) (CacheBuildStats, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	g := &GeoBed{config: cfg}
 
 	// Load from raw data files (skip cache)
 	if err := g.loadDataSets(); err != nil {
-		return fmt.Errorf("failed to load data sets: %w", err)
+		return CacheBuildStats{}, fmt.Errorf("failed to load data sets: %w", err)
 	}
 
 	// Store to cache
 	if err := g.store(); err != nil {
-		return fmt.Errorf("failed to store cache: %w", err)
+		return CacheBuildStats{}, fmt.Errorf("failed to store cache: %w", err)
 	}
 
-	return nil
+	m, err := ReadCacheManifest(cfg.CacheDir)
+	if err != nil {
+		return CacheBuildStats{}, fmt.Errorf("reading cache manifest: %w", err)
+	}
+	g.buildStats.CacheDir = cfg.CacheDir
+	g.buildStats.Files = m.Outputs
+
+	g.buildCellIndex()
+	g.buildStats.Cells = len(g.cellIndex)
+	for _, postings := range g.cellIndex {
+		g.buildStats.MaxCellCities = max(g.buildStats.MaxCellCities, len(postings))
+	}
+
+	return g.buildStats, nil
 }
 
 // Validation thresholds for data integrity checks.
@@ -1477,19 +2511,30 @@ func ValidateCache() error {
 	}
 
 	// Check city count
-	cityCount := len(g.Cities)
+	cityCount := g.CityCount()
 	if cityCount < minCityCount {
 		return fmt.Errorf("city count too low: got %d, want >= %d", cityCount, minCityCount)
 	}
 	fmt.Printf("      City count: %d (OK)\n", cityCount)
 
 	// Check country count
-	countryCount := len(g.Countries)
+	countryCount := g.CountryCountLoaded()
 	if countryCount < minCountryCount {
 		return fmt.Errorf("country count too low: got %d, want >= %d", countryCount, minCountryCount)
 	}
 	fmt.Printf("      Country count: %d (OK)\n", countryCount)
 
+	// Per-source breakdown (informational; caches predating source tracking
+	// report everything as unknown)
+	sources := g.SourceCounts()
+	fmt.Printf("      Sources:")
+	for _, src := range []RecordSource{SourceGeonames, SourceMaxMind, SourceCustom, SourceRecords, SourceUnknown} {
+		if n := sources[src]; n > 0 {
+			fmt.Printf(" %s=%d", src, n)
+		}
+	}
+	fmt.Println()
+
 	// Validate forward geocoding
 	fmt.Printf("      Forward geocoding: ")
 	for _, tc := range knownCities {
@@ -1529,6 +2574,13 @@ func (g *GeoBed) store() error {
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return fmt.Errorf("creating cache directory: %w", err)
 	}
+	// Serialize with other processes writing the same cache directory, and
+	// write each file atomically so concurrent readers see old or new data.
+	unlock, err := lockDir(cacheDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Convert to GOB-friendly format
 	gobCities := make([]geobedCityGob, len(g.Cities))
@@ -1541,6 +2593,9 @@ func (g *GeoBed) store() error {
 			Latitude:   c.Latitude,
 			Longitude:  c.Longitude,
 			Population: c.Population,
+			GeonameID:  c.geonameID,
+			Source:     c.source,
+			Capital:    c.capital,
 		}
 	}
 
@@ -1550,7 +2605,7 @@ func (g *GeoBed) store() error {
 	if err := enc.Encode(gobCities); err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(cacheDir, "g.c.dmp"), b.Bytes(), 0644); err != nil {
+	if err := writeFileAtomic(filepath.Join(cacheDir, "g.c.dmp"), b.Bytes(), 0644); err != nil {
 		return err
 	}
 
@@ -1559,79 +2614,133 @@ func (g *GeoBed) store() error {
 	if err := enc.Encode(g.Countries); err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(cacheDir, "g.co.dmp"), b.Bytes(), 0644); err != nil {
+	if err := writeFileAtomic(filepath.Join(cacheDir, "g.co.dmp"), b.Bytes(), 0644); err != nil {
 		return err
 	}
 
 	b.Reset()
 	enc = gob.NewEncoder(b) // fresh encoder to avoid leaking type-ID state
-	if err := enc.Encode(g.nameIndex); err != nil {
+	if err := enc.Encode(sortedNameIndexEntries(g.nameIndex)); err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(cacheDir, "nameIndex.dmp"), b.Bytes(), 0644); err != nil {
+	if err := writeFileAtomic(filepath.Join(cacheDir, "nameIndex.dmp"), b.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := g.storeBigCityGrid(cacheDir); err != nil {
 		return err
 	}
 
-	return nil
+	return g.writeManifest()
+}
+
+// nameIndexEntry is the on-disk form of a single nameIndex posting list.
+// The index is stored as a key-sorted slice rather than a map because gob
+// encodes maps in iteration order, which would make identical builds differ.
+type nameIndexEntry struct {
+	Key     string
+	Indices []int
 }
 
-func openOptionallyCachedFile(file string) (fs.File, error) {
+// sortedNameIndexEntries flattens the name index into entries sorted by key.
+func sortedNameIndexEntries(idx map[string][]int) []nameIndexEntry {
+	entries := make([]nameIndexEntry, 0, len(idx))
+	for k, v := range idx {
+		entries = append(entries, nameIndexEntry{Key: k, Indices: v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// embeddedCacheDir is the directory of the cache files in cacheData.
+const embeddedCacheDir = "geobed-cache"
+
+// openOptionallyCachedFile opens the cache file name from dir, falling
+// back to the embedded copy.
+func openOptionallyCachedFile(dir, name string) (fs.File, error) {
 	// WHY FILESYSTEM FIRST: When regenerating cache via RegenerateCache(),
 	// newly written .dmp files need to be validated. If we check embedded
 	// data first, ValidateCache() would verify the OLD embedded data instead
 	// of the fresh files, giving false positive validation results.
 	// This allows filesystem to override embedded data for testing and updates.
-	if fh, err := os.Open(file); err == nil {
+	if fh, err := os.Open(filepath.Join(dir, name)); err == nil {
 		return fh, nil
 	}
 	// Fallback to embedded data (normal runtime case)
-	return cacheData.Open(file)
+	return cacheData.Open(embeddedCacheDir + "/" + name)
 }
 
-func openOptionallyBzippedFile(file string) (io.Reader, func() error, error) {
-	fh, err := openOptionallyCachedFile(file + ".bz2")
+// openOptionallyBzippedFile opens the cache file name from dir or the
+// embedded cache, decompressing the bzip2 copy if that is the one found.
+func openOptionallyBzippedFile(dir, name string) (io.Reader, func() error, error) {
+	fh, bzipped, err := openRawCacheFile(dir, name)
 	if err != nil {
-		fh, err = openOptionallyCachedFile(file)
+		return nil, nil, err
+	}
+	if bzipped {
+		return bzip2.NewReader(fh), fh.Close, nil
+	}
+	return fh, fh.Close, nil
+}
+
+// openRawCacheFile opens the copy of a cache file that
+// openOptionallyBzippedFile reads, without decompressing it, and reports
+// whether it is the bzip2-compressed copy.
+func openRawCacheFile(dir, name string) (fs.File, bool, error) {
+	// An uncompressed file on disk is what RegenerateCache just wrote, so it
+	// must win over any .bz2 copy (on disk or embedded), which is stale until
+	// bzip2 is re-run. Otherwise ValidateCache would check the old data.
+	path := filepath.Join(dir, name)
+	if fh, err := os.Open(path); err == nil {
+		return fh, false, nil
+	}
+	fh, err := openOptionallyCachedFile(dir, name+".bz2")
+	if err != nil {
+		fh, err = openOptionallyCachedFile(dir, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, fmt.Errorf("opening %s: %w: %w", path, ErrCacheMissing, err)
+		}
 		if err != nil {
-			return nil, nil, fmt.Errorf("opening %s: %w", file, err)
+			return nil, false, fmt.Errorf("opening %s: %w", path, err)
 		}
-		return fh, fh.Close, nil
+		return fh, false, nil
 	}
-	return bzip2.NewReader(fh), fh.Close, nil
+	return fh, true, nil
 }
 
-func loadGeobedCityData() ([]GeobedCity, error) {
-	fh, cleanup, err := openOptionallyBzippedFile("geobed-cache/g.c.dmp")
+// loadGeobedCityData reads the city cache from dir or the embedded cache.
+func loadGeobedCityData(dir string) ([]GeobedCity, error) {
+	fh, cleanup, err := openOptionallyBzippedFile(dir, "g.c.dmp")
 	if err != nil {
 		return nil, err
 	}
 	defer cleanup()
+	return decodeCityCache(fh)
+}
 
-	// Try loading as new format first
+// decodeCityCache decodes a g.c.dmp stream into cities.
+func decodeCityCache(r io.Reader) ([]GeobedCity, error) {
 	var gobCities []geobedCityGob
-	dec := gob.NewDecoder(fh)
+	dec := gob.NewDecoder(r)
 	if err := dec.Decode(&gobCities); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("decoding city cache: %w: %w", ErrCacheCorrupt, err)
 	}
 
 	// Convert from GOB format to memory-efficient format
 	cities := make([]GeobedCity, len(gobCities))
 	for i, gc := range gobCities {
-		cities[i] = GeobedCity{
-			City:       gc.City,
-			CityAlt:    gc.CityAlt,
-			country:    internCountry(gc.Country),
-			region:     internRegion(gc.Region),
-			Latitude:   gc.Latitude,
-			Longitude:  gc.Longitude,
-			Population: gc.Population,
+		c, err := gc.toCity()
+		if err != nil {
+			return nil, fmt.Errorf("decoding city cache: %w: %w", ErrCacheCorrupt, err)
 		}
+		cities[i] = c
 	}
 	return cities, nil
 }
 
-func loadGeobedCountryData() ([]CountryInfo, error) {
-	fh, cleanup, err := openOptionallyBzippedFile("geobed-cache/g.co.dmp")
+// loadGeobedCountryData reads the country cache from dir or the embedded
+// cache.
+func loadGeobedCountryData(dir string) ([]CountryInfo, error) {
+	fh, cleanup, err := openOptionallyBzippedFile(dir, "g.co.dmp")
 	if err != nil {
 		return nil, err
 	}
@@ -1640,13 +2749,37 @@ func loadGeobedCountryData() ([]CountryInfo, error) {
 	co := []CountryInfo{}
 	dec := gob.NewDecoder(fh)
 	if err := dec.Decode(&co); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("decoding country cache: %w: %w", ErrCacheCorrupt, err)
 	}
 	return co, nil
 }
 
-func loadNameIndex() (map[string][]int, error) {
-	fh, cleanup, err := openOptionallyBzippedFile("geobed-cache/nameIndex.dmp")
+// loadNameIndex reads the name index from dir or the embedded cache.
+func loadNameIndex(dir string) (map[string][]int, error) {
+	fh, cleanup, err := openOptionallyBzippedFile(dir, "nameIndex.dmp")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var entries []nameIndexEntry
+	if err := gob.NewDecoder(fh).Decode(&entries); err != nil {
+		// Caches generated before the sorted-entry format store the index as
+		// a gob-encoded map. The type mismatch is detected before any values
+		// are read, so reopening and decoding again is cheap.
+		return loadLegacyNameIndex(dir)
+	}
+
+	idx := make(map[string][]int, len(entries))
+	for _, e := range entries {
+		idx[e.Key] = e.Indices
+	}
+	return idx, nil
+}
+
+// loadLegacyNameIndex decodes a name index stored as a gob-encoded map.
+func loadLegacyNameIndex(dir string) (map[string][]int, error) {
+	fh, cleanup, err := openOptionallyBzippedFile(dir, "nameIndex.dmp")
 	if err != nil {
 		return nil, err
 	}
@@ -1655,7 +2788,7 @@ func loadNameIndex() (map[string][]int, error) {
 	idx := make(map[string][]int)
 	dec := gob.NewDecoder(fh)
 	if err := dec.Decode(&idx); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("decoding name index cache: %w: %w", ErrCacheCorrupt, err)
 	}
 	return idx, nil
 }
@@ -13,7 +13,7 @@ import (
 
 // TestGeocodeEdgeCases tests edge case inputs for the Geocode function.
 func TestGeocodeEdgeCases(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -101,7 +101,7 @@ func TestGeocodeEdgeCases(t *testing.T) {
 
 // TestGeocodeUnicodeInternational tests unicode and international city names.
 func TestGeocodeUnicodeInternational(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -170,7 +170,7 @@ func TestGeocodeUnicodeInternational(t *testing.T) {
 
 // TestGeocodeJapaneseChineseNames tests Asian city names.
 func TestGeocodeJapaneseChineseNames(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -224,7 +224,7 @@ func TestGeocodeJapaneseChineseNames(t *testing.T) {
 
 // TestGeocodeAmbiguousNames tests disambiguation of cities with the same name.
 func TestGeocodeAmbiguousNames(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -321,7 +321,7 @@ func TestGeocodeAmbiguousNames(t *testing.T) {
 
 // TestGeocodeCaseInsensitivity tests that geocoding is case-insensitive.
 func TestGeocodeCaseInsensitivity(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -380,7 +380,7 @@ func TestGeocodeCaseInsensitivity(t *testing.T) {
 
 // TestGeocodeConcurrency tests that Geocode is safe for concurrent use.
 func TestGeocodeConcurrency(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -431,7 +431,7 @@ func TestGeocodeConcurrency(t *testing.T) {
 
 // TestReverseGeocodeConcurrency tests that ReverseGeocode is safe for concurrent use.
 func TestReverseGeocodeConcurrency(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -485,7 +485,7 @@ func TestReverseGeocodeConcurrency(t *testing.T) {
 
 // TestMixedConcurrency tests both Geocode and ReverseGeocode running concurrently.
 func TestMixedConcurrency(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -543,7 +543,7 @@ func TestMixedConcurrency(t *testing.T) {
 
 // TestReverseGeocodeEdgeCases tests edge cases for reverse geocoding.
 func TestReverseGeocodeEdgeCases(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -616,7 +616,7 @@ func TestReverseGeocodeEdgeCases(t *testing.T) {
 
 // TestReverseGeocodeKnownLocations tests reverse geocoding of well-known locations.
 func TestReverseGeocodeKnownLocations(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -678,7 +678,7 @@ func TestReverseGeocodeKnownLocations(t *testing.T) {
 
 // TestGeocodeWithOptions tests the GeocodeOptions functionality.
 func TestGeocodeWithOptions(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -743,7 +743,7 @@ func TestGeocodeWithOptions(t *testing.T) {
 
 // TestGeocodeUSStates tests geocoding with various US state formats.
 func TestGeocodeUSStates(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -808,7 +808,7 @@ func TestGeocodeUSStates(t *testing.T) {
 
 // TestGeocodeCountries tests geocoding with explicit country indicators.
 func TestGeocodeCountries(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -923,7 +923,7 @@ func TestGetDefaultGeobedConcurrency(t *testing.T) {
 
 // TestGeobedCityMethods tests the GeobedCity accessor methods.
 func TestGeobedCityMethods(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -970,7 +970,7 @@ func TestGeobedCityMethods(t *testing.T) {
 
 // TestSpecialInputFormats tests various input format edge cases.
 func TestSpecialInputFormats(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -17,6 +17,26 @@ var _ = Suite(&GeobedSuite{})
 
 var g *GeoBed
 
+// sharedGeobed loads a single default GeoBed shared by every test and fuzz
+// iteration that neither needs its own options nor modifies it. Loading
+// takes seconds, so tests build their own only when they must.
+var sharedGeobed = sync.OnceValues(func() (*GeoBed, error) {
+	return NewGeobed()
+})
+
+// fixtureCities is a handful of cities for tests that need a GeoBed with
+// their own options but not the whole dataset: several Parises, Austin,
+// Lyon, Berlin and Pittsburgh.
+var fixtureCities = []CityRecord{
+	{City: "Paris", Country: "FR", Region: "11", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+	{City: "Paris", Country: "US", Region: "TX", Latitude: 33.66094, Longitude: -95.55551, Population: 24782},
+	{City: "Paris", Country: "US", Region: "TN", Latitude: 36.302, Longitude: -88.32671, Population: 10156},
+	{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	{City: "Lyon", Country: "FR", Region: "84", Latitude: 45.74846, Longitude: 4.84671, Population: 522969},
+	{City: "Berlin", Country: "DE", Region: "16", Latitude: 52.52437, Longitude: 13.41053, Population: 3426354},
+	{City: "Pittsburgh", Country: "US", Region: "PA", Latitude: 40.44062, Longitude: -79.99589, Population: 302407},
+}
+
 func (s *GeobedSuite) SetUpSuite(c *C) {
 	s.testLocations = append(s.testLocations, map[string]string{"query": "Austin", "city": "Austin", "country": "US", "region": "TX"})
 	s.testLocations = append(s.testLocations, map[string]string{"query": "Paris", "city": "Paris", "country": "FR", "region": ""})
@@ -26,7 +46,7 @@ func (s *GeobedSuite) SetUpSuite(c *C) {
 
 func (s *GeobedSuite) TestANewGeobed(c *C) {
 	var err error
-	g, err = NewGeobed()
+	g, err = sharedGeobed()
 	c.Assert(err, IsNil)
 	c.Assert(g, Not(IsNil))
 	c.Assert(len(g.Cities), Not(Equals), 0)
@@ -100,7 +120,7 @@ func BenchmarkNewGeobed(b *testing.B) {
 func BenchmarkReverseGeocode(b *testing.B) {
 	if g == nil {
 		var err error
-		g, err = NewGeobed()
+		g, err = sharedGeobed()
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -114,7 +134,7 @@ func BenchmarkReverseGeocode(b *testing.B) {
 func BenchmarkGeocode(b *testing.B) {
 	if g == nil {
 		var err error
-		g, err = NewGeobed()
+		g, err = sharedGeobed()
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -125,6 +145,21 @@ func BenchmarkGeocode(b *testing.B) {
 	}
 }
 
+func BenchmarkExtractLocationPieces(b *testing.B) {
+	if g == nil {
+		var err error
+		g, err = sharedGeobed()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		g.parseLocationPieces("Paris, France")
+	}
+}
+
 // TestConcurrentNewGeobed verifies that multiple goroutines can safely
 // call NewGeobed simultaneously without races or panics.
 func TestConcurrentNewGeobed(t *testing.T) {
@@ -0,0 +1,30 @@
+// Package geobedtest provides a GeoBed backed by a handful of synthetic
+// cities, for unit tests that should not pay for loading the full embedded
+// dataset.
+//
+//	g := geobedtest.NewFake(
+//	    geobedtest.GeobedCityInput{City: "Springfield", Country: "US", Region: "IL", Latitude: 39.8, Longitude: -89.64, Population: 114394},
+//	)
+//	city := g.Geocode("Springfield, IL")
+package geobedtest
+
+import (
+	"fmt"
+
+	"github.com/andreiashu/geobed"
+)
+
+// GeobedCityInput describes one synthetic city.
+type GeobedCityInput = geobed.CityRecord
+
+// NewFake returns a fully functional GeoBed holding only the given cities,
+// with the embedded country metadata so country names and codes in queries
+// work as usual. It panics on invalid input, such as a city without a name
+// or with out-of-range coordinates.
+func NewFake(cities ...GeobedCityInput) *geobed.GeoBed {
+	g, err := geobed.NewGeobedFromRecords(cities, nil)
+	if err != nil {
+		panic(fmt.Sprintf("geobedtest: %v", err))
+	}
+	return g
+}
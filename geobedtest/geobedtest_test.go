@@ -0,0 +1,52 @@
+package geobedtest
+
+import (
+	"testing"
+)
+
+func TestNewFake(t *testing.T) {
+	g := NewFake(
+		GeobedCityInput{City: "Springfield", Country: "US", Region: "IL", Latitude: 39.80172, Longitude: -89.64371, Population: 114394},
+		GeobedCityInput{City: "Springfield", Country: "US", Region: "MA", Latitude: 42.10148, Longitude: -72.58981, Population: 155929},
+		GeobedCityInput{City: "Paris", CityAlt: "Lutetia", Country: "FR", Region: "11", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+	)
+
+	if n := g.CityCount(); n != 3 {
+		t.Fatalf("CityCount() = %d, want 3", n)
+	}
+
+	for _, tt := range []struct {
+		query       string
+		wantCity    string
+		wantRegion  string
+		wantCountry string
+	}{
+		{"Springfield, IL", "Springfield", "IL", "US"},
+		{"Springfield", "Springfield", "MA", "US"}, // More populous
+		{"Paris, France", "Paris", "11", "FR"},
+		{"Lutetia", "Paris", "11", "FR"},
+		{"Austin", "", "", ""}, // Not in the fixture
+	} {
+		c := g.Geocode(tt.query)
+		if c.City != tt.wantCity || c.Region() != tt.wantRegion || c.Country() != tt.wantCountry {
+			t.Errorf("Geocode(%q) = %s, %s, %s; want %s, %s, %s", tt.query,
+				c.City, c.Region(), c.Country(), tt.wantCity, tt.wantRegion, tt.wantCountry)
+		}
+	}
+
+	if c := g.ReverseGeocode(48.86, 2.35); c.City != "Paris" {
+		t.Errorf("ReverseGeocode(Paris) = %q, want Paris", c.City)
+	}
+	if c := g.ReverseGeocode(0, 0); c.City != "" {
+		t.Errorf("ReverseGeocode(0, 0) = %q, want no city", c.City)
+	}
+}
+
+func TestNewFake_Invalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewFake with invalid coordinates did not panic")
+		}
+	}()
+	NewFake(GeobedCityInput{City: "Nowhere", Country: "US", Latitude: 91})
+}
@@ -10,7 +10,7 @@ import (
 // of internal implementation details.
 
 func TestBlackBox_GeocodeEmptyAndWhitespace(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -43,7 +43,7 @@ func TestBlackBox_GeocodeEmptyAndWhitespace(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeInputLengthLimiting(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -87,7 +87,7 @@ func TestBlackBox_GeocodeInputLengthLimiting(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeFuzzyDistanceCapping(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -146,7 +146,7 @@ func TestBlackBox_GeocodeFuzzyDistanceCapping(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeExactCityMode(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -215,7 +215,7 @@ func TestBlackBox_GeocodeExactCityMode(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeStandardCities(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -252,7 +252,7 @@ func TestBlackBox_GeocodeStandardCities(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeCountryDisambiguation(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -298,7 +298,7 @@ func TestBlackBox_GeocodeCountryDisambiguation(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeStateDisambiguation(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -331,7 +331,7 @@ func TestBlackBox_GeocodeStateDisambiguation(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeCaseInsensitivity(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -366,7 +366,7 @@ func TestBlackBox_GeocodeCaseInsensitivity(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeAlternateNames(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -412,7 +412,7 @@ func TestBlackBox_GeocodeAlternateNames(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeMultipleOptionPatterns(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -479,7 +479,7 @@ func TestBlackBox_GeocodeMultipleOptionPatterns(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeCoordinateReasonableness(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -552,7 +552,7 @@ func TestBlackBox_GeocodeCoordinateReasonableness(t *testing.T) {
 }
 
 func TestBlackBox_GeocodePopulationField(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -578,7 +578,7 @@ func TestBlackBox_GeocodePopulationField(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeReturnType(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -610,7 +610,7 @@ func TestBlackBox_GeocodeReturnType(t *testing.T) {
 }
 
 func TestBlackBox_GeocodeIdempotency(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
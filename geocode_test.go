@@ -5,7 +5,7 @@ import (
 )
 
 func TestNewYorkGeocoding(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,97 @@
+package geobed
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// GeocodeCandidate is a city matched by GeocodeAll with its ranking score.
+type GeocodeCandidate struct {
+	City  GeobedCity
+	Score int // Ranker score after the query-level adjustments Geocode applies; higher is better
+}
+
+// GeocodeAll returns every city matching query, best first, so callers
+// such as disambiguation UIs can offer the alternatives Geocode chose
+// between. When Geocode returns a city it comes first; the rest follow by
+// descending score, then population. A query too ambiguous for Geocode,
+// such as a bare name with ExactCity, still lists its candidates.
+// Candidates scoring zero can never be returned by Geocode and are left
+// out, as are near-duplicates with WithDedupe, so for callers wanting the
+// top N a slice of the result is enough.
+//
+// Options apply as for Geocode. With ExactCity only cities named exactly
+// like the query are returned. A Deadline that passes leaves the list
+// incomplete, like the Partial result of GeocodeDetailed. Scores come from
+// the configured Ranker and are comparable only between candidates of one
+// query.
+func (g *GeoBed) GeocodeAll(query string, opts ...GeocodeOptions) []GeocodeCandidate {
+	n, _ := g.cleanQuery(query)
+	if n == "" {
+		return nil
+	}
+
+	options := GeocodeOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	options.Country = toUpper(strings.TrimSpace(options.Country))
+	if options.FuzzyDistance > maxFuzzyDistance {
+		options.FuzzyDistance = maxFuzzyDistance
+	}
+	options, release, _ := g.acquireFuzzySlot(options)
+	defer release()
+
+	rk := g.rankLocation(n, options, true)
+	keep := func(int) bool { return true }
+	var exactPick GeobedCity
+	if options.ExactCity {
+		// The exact path picks its own winner among the exact name matches,
+		// which need not score positively.
+		exactPick, _ = g.exactMatchCity(n, options.Country)
+		_, _, _, nSlice := g.extractLocationPieces(n)
+		nWithoutAbbrev := strings.Join(nSlice, " ")
+		keep = func(k int) bool {
+			return strings.EqualFold(n, g.Cities[k].City) || strings.EqualFold(nWithoutAbbrev, g.Cities[k].City)
+		}
+		rk.best = -1
+		for k := range rk.scores {
+			if g.Cities[k] == exactPick && (rk.best < 0 || k < rk.best) {
+				rk.best = k
+			}
+		}
+	}
+
+	idx := make([]int, 0, len(rk.scores))
+	for k, score := range rk.scores {
+		if k != rk.best && score > 0 && keep(k) {
+			idx = append(idx, k)
+		}
+	}
+	slices.SortFunc(idx, func(a, b int) int {
+		if c := cmp.Compare(rk.scores[b], rk.scores[a]); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(g.Cities[b].Population, g.Cities[a].Population); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	if rk.best >= 0 {
+		idx = slices.Insert(idx, 0, rk.best)
+	}
+
+	var out []GeocodeCandidate
+	d := g.deduper()
+	if rk.best < 0 && exactPick.City != "" {
+		d.keep(exactPick)
+		out = append(out, GeocodeCandidate{City: exactPick})
+	}
+	for _, k := range idx {
+		if d.keep(g.Cities[k]) {
+			out = append(out, GeocodeCandidate{City: g.Cities[k], Score: rk.scores[k]})
+		}
+	}
+	return out
+}
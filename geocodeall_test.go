@@ -0,0 +1,73 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGeocodeAll(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Springfield", Country: "US", Region: "IL", Latitude: 39.80172, Longitude: -89.64371, Population: 116250},
+		{City: "Springfield", Country: "US", Region: "MO", Latitude: 37.21533, Longitude: -93.29824, Population: 159498},
+		{City: "Springfield", Country: "US", Region: "MA", Latitude: 42.10148, Longitude: -72.58981, Population: 153060},
+		{City: "Springfield", Country: "AU", Region: "04", Latitude: -27.6533, Longitude: 152.91699, Population: 18000},
+		{City: "Shelbyville", Country: "US", Region: "IN", Latitude: 39.52144, Longitude: -85.77692, Population: 19191},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		opts  GeocodeOptions
+		want  []string // Region of each candidate, in order
+	}{
+		{"Springfield", GeocodeOptions{}, []string{"MO", "MA", "IL", "04"}},
+		{"Springfield, IL", GeocodeOptions{}, []string{"IL", "MO", "MA", "04"}},
+		{"Springfield", GeocodeOptions{Country: "AU"}, []string{"04"}},
+		{"Springfeld", GeocodeOptions{FuzzyDistance: 1}, []string{"MO", "MA", "IL", "04"}},
+		{"Springfield, IL", GeocodeOptions{ExactCity: true}, []string{"IL", "MO", "MA", "04"}},
+		// Too ambiguous for Geocode, which returns nothing.
+		{"Springfield", GeocodeOptions{ExactCity: true}, []string{"MO", "MA", "IL", "04"}},
+		{"Nowhere", GeocodeOptions{}, nil},
+		{"  ", GeocodeOptions{}, nil},
+	}
+	for _, tt := range tests {
+		got := g.GeocodeAll(tt.query, tt.opts)
+		var regions []string
+		for _, c := range got {
+			regions = append(regions, c.City.Region())
+		}
+		if !slices.Equal(regions, tt.want) {
+			t.Errorf("GeocodeAll(%q, %+v) regions = %v; want %v", tt.query, tt.opts, regions, tt.want)
+			continue
+		}
+		if want := g.Geocode(tt.query, tt.opts); want.City != "" && (len(got) == 0 || got[0].City != want) {
+			t.Errorf("GeocodeAll(%q) = %v; Geocode returns %v first", tt.query, got, want)
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i].Score <= 0 || (i > 1 && got[i].Score > got[i-1].Score) {
+				t.Errorf("GeocodeAll(%q) scores not descending after the pick: %+v", tt.query, got)
+				break
+			}
+		}
+	}
+}
+
+func TestGeocodeAll_MatchesGeocode(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{"Paris", "Portland", "Springfield, MO", "San Jose, Costa Rica", "Victoria", "Londn"} {
+		opts := GeocodeOptions{FuzzyDistance: 1}
+		got := g.GeocodeAll(q, opts)
+		want := g.Geocode(q, opts)
+		if len(got) == 0 || got[0].City != want {
+			t.Errorf("GeocodeAll(%q) first = %+v; Geocode returns %v", q, got, want)
+		}
+		if len(got) < 2 {
+			t.Errorf("GeocodeAll(%q) returned %d candidates; want alternatives", q, len(got))
+		}
+	}
+}
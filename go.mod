@@ -1,14 +1,15 @@
 module github.com/andreiashu/geobed
 
-go 1.24
+go 1.24.0
 
 require (
+	github.com/agnivade/levenshtein v1.2.1
 	github.com/golang/geo v0.0.0-20260129164528-943061e2742c
+	golang.org/x/text v0.34.0
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
 )
 
 require (
-	github.com/agnivade/levenshtein v1.2.1 // indirect
 	github.com/kr/pretty v0.2.1 // indirect
 	github.com/kr/text v0.1.0 // indirect
 )
@@ -0,0 +1,137 @@
+package geobed
+
+import (
+	"slices"
+	"strings"
+)
+
+// parseComponents reads a comma-separated query as ordered components,
+// "city [, admin] [, country]", and assigns roles by position from the
+// right: the last component may be a country, the one before it an admin
+// division of that country, and whatever remains is the city (with any
+// district in front, as in "Soho, London, GB"). When the written order
+// yields no role or leaves no known city name, the reverse order is tried
+// ("France, Paris", "TX, Austin", "Japan, Tokyo") and kept if it does.
+//
+// It returns ok false for queries without commas or whose components fit
+// no role, which are left to the free-form heuristics in
+// parseLocationPieces.
+func (g *GeoBed) parseComponents(n string) (nCo, nSt string, names []string, ok bool) {
+	var parts []string
+	for _, p := range strings.Split(n, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) < 2 {
+		return "", "", nil, false
+	}
+
+	nCo, nSt, names, ok = g.assignRoles(parts)
+	if ok && g.knownName(names) {
+		return nCo, nSt, names, true
+	}
+	reversed := slices.Clone(parts)
+	slices.Reverse(reversed)
+	if co, st, rnames, rok := g.assignRoles(reversed); rok && g.knownName(rnames) {
+		return co, st, rnames, true
+	}
+	return nCo, nSt, names, ok
+}
+
+// knownName reports whether the city components of a parse, or the last of
+// them, name a known place.
+func (g *GeoBed) knownName(names []string) bool {
+	if _, ok := g.nameIndex[g.nameKey(strings.Join(names, ", "))]; ok {
+		return true
+	}
+	_, ok := g.nameIndex[g.nameKey(names[len(names)-1])]
+	return ok
+}
+
+// assignRoles gives the last of parts the country role and the one before
+// it the admin role, where they fit, keeping at least the first component
+// as the city. It reports false when neither role was assigned.
+func (g *GeoBed) assignRoles(parts []string) (nCo, nSt string, names []string, ok bool) {
+	city := parts[0]
+	i := len(parts) - 1
+	if i > 0 {
+		if co, found := g.componentCountry(parts[i], city); found {
+			nCo = co
+			i--
+		}
+	}
+	if i > 0 {
+		if co, st, found := g.componentAdmin(parts[i], nCo); found {
+			nCo, nSt = co, st
+			i--
+		}
+	}
+	if nCo == "" && nSt == "" {
+		return "", "", nil, false
+	}
+	return nCo, nSt, parts[:i+1], true
+}
+
+// componentCountry resolves a component written in the country position: a
+// country name or ISO 3166 code. Names and codes shared with US states
+// ("Georgia", "CA") are read as the state when city is found there and not
+// in the country.
+func (g *GeoBed) componentCountry(s, city string) (string, bool) {
+	lower := toLower(s)
+	if rank, ok := g.countryKeys[lower]; ok {
+		iso := g.Countries[rank].ISO
+		if st, isState := usStateNameKeys()[lower]; isState &&
+			g.hasCityIn(city, "US", sortedUsStateCodes()[st]) && !g.hasCityIn(city, iso, "") {
+			return "", false
+		}
+		return iso, true
+	}
+	if len(s) < 2 || len(s) > 3 {
+		return "", false
+	}
+	code := toUpper(s)
+	if _, isState := usStateCodeKeys()[lower]; isState {
+		if !g.preferCountryCode(city, code) {
+			return "", false
+		}
+		return code, true
+	}
+	return g.countryCode(code, city)
+}
+
+// componentAdmin resolves a component written in the admin position for
+// country nCo, which may still be unknown: a US state code or name, or an
+// admin division code or name. An admin division of an unknown country must
+// be unambiguous, and it fills in the country. US states imply country US
+// unless WithoutUSDefault is set.
+func (g *GeoBed) componentAdmin(s, nCo string) (country, code string, ok bool) {
+	lower := toLower(s)
+	if nCo == "" || nCo == "US" {
+		rank, isCode := usStateCodeKeys()[lower]
+		if !isCode {
+			rank, isCode = usStateNameKeys()[lower]
+		}
+		if isCode {
+			if nCo == "" && !g.config.NoUSDefault {
+				nCo = "US"
+			}
+			return nCo, sortedUsStateCodes()[rank], true
+		}
+	}
+	if len(s) >= 2 && len(s) <= 3 {
+		code = toUpper(s)
+		if nCo != "" && g.isAdminDivision(nCo, code) {
+			return nCo, code, true
+		}
+		if nCo == "" {
+			if country = g.getAdminDivisionCountry(code); country != "" {
+				return country, code, true
+			}
+		}
+	}
+	if country, code = g.findAdminDivisionByName(nCo, s); code != "" {
+		return country, code, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,76 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseComponents(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query       string
+		wantOK      bool
+		wantCountry string
+		wantState   string
+		wantNames   []string
+	}{
+		{"Austin, TX", true, "US", "TX", []string{"Austin"}},
+		{"Austin, Texas, United States", true, "US", "TX", []string{"Austin"}},
+		{" Austin , TX ", true, "US", "TX", []string{"Austin"}},
+		{"Toronto, Ontario", true, "CA", "08", []string{"Toronto"}},                  // Admin division by name
+		{"Sydney, New South Wales, Australia", true, "AU", "02", []string{"Sydney"}}, // All three roles
+		{"Soho, London, GB", true, "GB", "", []string{"Soho", "London"}},             // District stays with the city
+		{"Córdoba, AR", true, "AR", "", []string{"Córdoba"}},                         // Argentina, not Arkansas
+		{"Atlanta, Georgia", true, "US", "GA", []string{"Atlanta"}},                  // The state, not the country
+		{"Tbilisi, Georgia", true, "GE", "", []string{"Tbilisi"}},
+		{"France, Paris", true, "FR", "", []string{"Paris"}}, // Reverse order fallback
+		{"Japan, Tokyo", true, "JP", "", []string{"Tokyo"}},  // Tokyo is also a prefecture
+		{"Austin", false, "", "", nil},                       // No commas
+		{"Austin, Zxqwvbn", false, "", "", nil},              // No role fits
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			co, st, names, ok := g.parseComponents(tt.query)
+			if ok != tt.wantOK || co != tt.wantCountry || st != tt.wantState || !slices.Equal(names, tt.wantNames) {
+				t.Errorf("parseComponents(%q) = %q, %q, %q, %v; want %q, %q, %q, %v",
+					tt.query, co, st, names, ok, tt.wantCountry, tt.wantState, tt.wantNames, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGeocode_CommaGrammar(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query       string
+		wantCity    string
+		wantCountry string
+		wantRegion  string
+	}{
+		{"London, Ontario", "London", "CA", "08"},
+		{"London, Ontario, Canada", "London", "CA", "08"},
+		{"Perth, Scotland", "Perth", "GB", "SCT"},
+		{"Perth, Western Australia", "Perth", "AU", "08"},
+		{"Munich, Bavaria, Germany", "Munich", "DE", "02"},
+		{"NY, US", "New York City", "US", "NY"},
+		{"US, TX, Austin", "Austin", "US", "TX"},
+		{"Springfield, IL, USA", "Springfield", "US", "IL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := g.Geocode(tt.query)
+			if r.City != tt.wantCity || r.Country() != tt.wantCountry || r.Region() != tt.wantRegion {
+				t.Errorf("Geocode(%q) = %s, %s, %s; want %s, %s, %s", tt.query,
+					r.City, r.Region(), r.Country(), tt.wantCity, tt.wantRegion, tt.wantCountry)
+			}
+		})
+	}
+}
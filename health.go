@@ -0,0 +1,131 @@
+package geobed
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// embeddedDatasetDate is the Geonames snapshot date of the embedded cache.
+// Update it together with the cache files (see `make update-data`).
+var embeddedDatasetDate = time.Date(2026, time.February, 12, 0, 0, 0, 0, time.UTC)
+
+// WithMaxDatasetAge makes HealthCheck fail once the loaded dataset is older
+// than d. Zero (the default) disables the age check.
+func WithMaxDatasetAge(d time.Duration) Option {
+	return func(c *GeobedConfig) {
+		c.MaxDatasetAge = d
+	}
+}
+
+// DatasetDate returns when the loaded dataset was produced: the embedded
+// snapshot date, the raw data date recorded in the manifest of an on-disk
+// cache, or the date of the raw data files it was loaded from.
+func (g *GeoBed) DatasetDate() time.Time {
+	return g.datasetDate
+}
+
+// DatasetAge returns how long ago the loaded dataset was produced.
+func (g *GeoBed) DatasetAge() time.Duration {
+	if g.datasetDate.IsZero() {
+		return 0
+	}
+	return time.Since(g.datasetDate)
+}
+
+// HealthCheck verifies that the instance can serve queries: all indexes are
+// populated and a canary forward and reverse lookup both succeed. When
+// WithMaxDatasetAge is set, a dataset older than the limit is also reported.
+// Intended for readiness probes; it is cheap enough to call per probe.
+func (g *GeoBed) HealthCheck() error {
+	g.ensureCellIndex()
+	switch {
+	case len(g.Cities) == 0:
+		return fmt.Errorf("health check: no cities loaded")
+	case len(g.Countries) == 0:
+		return fmt.Errorf("health check: no countries loaded")
+	case len(g.nameIndex) == 0:
+		return fmt.Errorf("health check: name index is empty")
+	case len(g.cellIndex) == 0:
+		return fmt.Errorf("health check: cell index is empty")
+	}
+
+	canary, ok := g.healthCanary()
+	if !ok {
+		return fmt.Errorf("health check: no city both geocodes and reverse geocodes")
+	}
+	if got := g.Geocode(canary.City); got.City == "" {
+		return fmt.Errorf("health check: canary geocode %q returned no city", canary.City)
+	}
+	if got := g.ReverseGeocode(float64(canary.Latitude), float64(canary.Longitude)); got.City == "" {
+		return fmt.Errorf("health check: canary reverse geocode (%v, %v) returned no city", canary.Latitude, canary.Longitude)
+	}
+
+	if maxAge := g.config.MaxDatasetAge; maxAge > 0 {
+		if age := g.DatasetAge(); age > maxAge {
+			return fmt.Errorf("health check: dataset from %s is %s old (max %s)",
+				g.datasetDate.Format(time.DateOnly), age.Round(time.Hour), maxAge)
+		}
+	}
+	return nil
+}
+
+// healthCanary picks the city HealthCheck looks up. It is taken from the
+// loaded data rather than hard-coded so the check also works for custom
+// datasets, starting from the middle of Cities and skipping cities that a
+// healthy instance still cannot return: places kept out of reverse
+// geocoding and cities rejected by WithResultFilter.
+func (g *GeoBed) healthCanary() (GeobedCity, bool) {
+	for i := range g.Cities {
+		c := g.Cities[(len(g.Cities)/2+i)%len(g.Cities)]
+		if c.source == SourceCustom && !g.config.ReversePlaces {
+			continue
+		}
+		if g.config.ResultFilter != nil && !g.config.ResultFilter(c) {
+			continue
+		}
+		return c, true
+	}
+	return GeobedCity{}, false
+}
+
+// cacheDatasetDate returns the date of the cache NewGeobed just loaded from
+// dir, following the same lookup order as openOptionallyBzippedFile: the
+// dataset date recorded in the manifest of an on-disk cache, or the
+// embedded snapshot date. The file modification time is only used for
+// caches written before the manifest recorded the date, as it reflects
+// when the files were checked out or copied rather than the data.
+func cacheDatasetDate(dir string) time.Time {
+	for _, name := range []string{"g.c.dmp", "g.c.dmp.bz2"} {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if m, err := ReadCacheManifest(dir); err == nil {
+			if d, err := time.Parse(time.DateOnly, m.DatasetDate); err == nil {
+				return d
+			}
+		}
+		return fi.ModTime()
+	}
+	return embeddedDatasetDate
+}
+
+// rawDatasetDate returns the date of the raw data: the snapshot selected
+// with WithDatasetDate, or else the modification time of the raw cities
+// file in the data directory, or the current time if neither is known.
+func (g *GeoBed) rawDatasetDate() time.Time {
+	if d, err := time.Parse(time.DateOnly, g.config.DatasetDate); err == nil {
+		return d
+	}
+	for _, f := range g.dataSources() {
+		if f.ID != DataSourceGeonamesCities {
+			continue
+		}
+		if fi, err := os.Stat(filepath.Join(g.config.DataDir, filepath.Base(f.Path))); err == nil {
+			return fi.ModTime()
+		}
+	}
+	return time.Now()
+}
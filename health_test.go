@@ -0,0 +1,103 @@
+package geobed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthCheck_Healthy(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.HealthCheck(); err != nil {
+		t.Errorf("HealthCheck() = %v, want nil", err)
+	}
+	if g.DatasetDate().IsZero() {
+		t.Error("DatasetDate() is zero after NewGeobed")
+	}
+	if g.DatasetAge() <= 0 {
+		t.Errorf("DatasetAge() = %v, want > 0", g.DatasetAge())
+	}
+}
+
+func TestHealthCheck_MaxDatasetAge(t *testing.T) {
+	g, err := NewGeobed(WithMaxDatasetAge(time.Nanosecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.HealthCheck(); err == nil {
+		t.Error("HealthCheck() = nil, want error for dataset older than 1ns")
+	}
+
+	g.config.MaxDatasetAge = 100 * 365 * 24 * time.Hour
+	if err := g.HealthCheck(); err != nil {
+		t.Errorf("HealthCheck() = %v, want nil with a generous max age", err)
+	}
+}
+
+func TestHealthCheck_Unloaded(t *testing.T) {
+	tests := []struct {
+		name string
+		g    *GeoBed
+	}{
+		{"empty", &GeoBed{config: defaultConfig()}},
+		{"no indexes", &GeoBed{
+			Cities:    Cities{{City: "Austin"}},
+			Countries: []CountryInfo{{ISO: "US"}},
+			config:    defaultConfig(),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.g.HealthCheck(); err == nil {
+				t.Error("HealthCheck() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestHealthCheck_CanaryPassesResultFilter(t *testing.T) {
+	// The middle city, Lyon, can never be returned with this filter.
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+		{City: "Lyon", Country: "FR", Region: "84", Latitude: 45.74846, Longitude: 4.84671, Population: 522969},
+		{City: "Paris", Country: "FR", Region: "11", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+	}, nil, WithResultFilter(func(c GeobedCity) bool { return c.Country() == "US" }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.HealthCheck(); err != nil {
+		t.Errorf("HealthCheck() = %v, want nil", err)
+	}
+
+	g.config.ResultFilter = func(GeobedCity) bool { return false }
+	if err := g.HealthCheck(); err == nil {
+		t.Error("HealthCheck() = nil with every city filtered, want error")
+	}
+}
+
+func TestHealthCheck_ManifestDatasetDate(t *testing.T) {
+	// A freshly copied cache of old data must still count as old.
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	}, nil, WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.datasetDate = time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	if err := g.store(); err != nil {
+		t.Fatal(err)
+	}
+
+	g2, err := NewGeobed(WithCacheDir(g.config.CacheDir), WithMaxDatasetAge(365*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.DatasetDate().Equal(g.datasetDate) {
+		t.Errorf("DatasetDate() = %v, want the manifest date %v", g2.DatasetDate(), g.datasetDate)
+	}
+	if err := g2.HealthCheck(); err == nil {
+		t.Error("HealthCheck() = nil for a cache of 2020 data, want a dataset age error")
+	}
+}
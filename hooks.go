@@ -0,0 +1,77 @@
+package geobed
+
+import "math/rand/v2"
+
+// WithQueryPreprocessor rewrites every forward-geocoding query before it is
+// parsed, e.g. to strip emoji or expand in-house abbreviations ("HQ" to a
+// city name) without wrapping each call site. It runs before the input is
+// trimmed and length-limited, so its output is held to the same limits as
+// a caller's query. fn must be safe for concurrent use.
+func WithQueryPreprocessor(fn func(string) string) Option {
+	return func(c *GeobedConfig) {
+		c.QueryPreprocessor = fn
+	}
+}
+
+// preprocessQuery applies the configured query preprocessor, if any.
+func (g *GeoBed) preprocessQuery(n string) string {
+	if g.config == nil || g.config.QueryPreprocessor == nil {
+		return n
+	}
+	return g.config.QueryPreprocessor(n)
+}
+
+// WithResultFilter drops forward-geocoding candidates for which keep returns
+// false before any are scored, so a rejected city is never returned and the
+// next best match wins instead, e.g. to apply an organization's blocklist
+// without forking the ranking code. ReverseGeocode is not filtered. keep
+// must be safe for concurrent use.
+func WithResultFilter(keep func(GeobedCity) bool) Option {
+	return func(c *GeobedConfig) {
+		c.ResultFilter = keep
+	}
+}
+
+// filterCandidates removes the candidates rejected by the configured result
+// filter, if any, from set.
+func (g *GeoBed) filterCandidates(set map[int]bool) {
+	if g.config == nil || g.config.ResultFilter == nil {
+		return
+	}
+	for idx := range set {
+		if !g.config.ResultFilter(g.Cities[idx]) {
+			delete(set, idx)
+		}
+	}
+}
+
+// Miss is a forward geocode that found no city, or one of doubtful quality.
+type Miss struct {
+	Query  string        // Query after preprocessing, trimming, and truncation
+	Result GeocodeResult // What GeocodeDetailed returned
+}
+
+// WithMissSampler passes a random fraction rate (0 < rate <= 1) of the
+// forward geocodes that return no city, or a city outside the region named
+// in the query (HintMismatch) or cut short by a deadline (Partial), to fn,
+// so teams can collect real-world misses to improve preprocessing and
+// dataset coverage. fn runs synchronously on the querying goroutine, so it
+// should hand the miss off rather than block, and must be safe for
+// concurrent use. Empty queries are never reported.
+func WithMissSampler(rate float64, fn func(Miss)) Option {
+	return func(c *GeobedConfig) {
+		c.MissHook = fn
+		c.MissSampleRate = rate
+	}
+}
+
+// reportMiss passes a sample of misses to the configured miss hook.
+func (g *GeoBed) reportMiss(query string, r GeocodeResult) {
+	if r.City.City != "" && !r.HintMismatch && !r.Partial {
+		return
+	}
+	if g.config.MissSampleRate < 1 && rand.Float64() >= g.config.MissSampleRate {
+		return
+	}
+	g.config.MissHook(Miss{Query: query, Result: r})
+}
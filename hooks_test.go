@@ -0,0 +1,94 @@
+package geobed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestWithQueryPreprocessor(t *testing.T) {
+	stripSymbols := func(s string) string {
+		s = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.So, r) {
+				return -1
+			}
+			return r
+		}, s)
+		return strings.ReplaceAll(s, "HQ", "Austin, TX")
+	}
+	g, err := NewGeobedFromRecords(fixtureCities, nil, WithQueryPreprocessor(stripSymbols))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query, want string
+	}{
+		{"🗼 Paris 🇫🇷", "Paris"},
+		{"HQ", "Austin"},
+		{"🌍", ""}, // Nothing left after preprocessing
+	}
+	for _, tt := range tests {
+		if got := g.Geocode(tt.query); got.City != tt.want {
+			t.Errorf("Geocode(%q) = %q, want %q", tt.query, got.City, tt.want)
+		}
+	}
+
+	if got := g.Geocode(strings.Repeat("🗼", 10*maxGeocodeInputLen)); got.City != "" {
+		t.Errorf("Geocode(emoji only) = %q, want empty", got.City)
+	}
+}
+
+func TestWithResultFilter(t *testing.T) {
+	g, err := NewGeobedFromRecords(fixtureCities, nil, WithResultFilter(func(c GeobedCity) bool {
+		return c.Country() != "FR"
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := g.Geocode("Paris"); got.City != "Paris" || got.Country() == "FR" {
+		t.Errorf("Geocode(Paris) = %s, %s; want a Paris outside FR", got.City, got.Country())
+	}
+	if got := g.Geocode("Paris, France"); got.Country() == "FR" {
+		t.Errorf("Geocode(Paris, France) = %s, %s; want no FR result", got.City, got.Country())
+	}
+	if got := g.Geocode("Paris", GeocodeOptions{ExactCity: true}); got.Country() == "FR" {
+		t.Errorf("Geocode(Paris, ExactCity) = %s, %s; want no FR result", got.City, got.Country())
+	}
+	if got := g.Geocode("Lyon"); got.Country() == "FR" {
+		t.Errorf("Geocode(Lyon) = %s, %s; want no FR result", got.City, got.Country())
+	}
+	if got := g.Geocode("Berlin"); got.City != "Berlin" || got.Country() != "DE" {
+		t.Errorf("Geocode(Berlin) = %s, %s; want Berlin, DE", got.City, got.Country())
+	}
+
+	// Reverse geocoding is unaffected.
+	if got := g.ReverseGeocode(48.8566, 2.3522); got.Country() != "FR" {
+		t.Errorf("ReverseGeocode(Paris) = %s, %s; want FR", got.City, got.Country())
+	}
+}
+
+func TestWithMissSampler(t *testing.T) {
+	var misses []Miss
+	g, err := NewGeobedFromRecords([]CityRecord{{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306}}, nil,
+		WithMissSampler(1, func(m Miss) { misses = append(misses, m) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.Geocode("Austin")
+	g.Geocode("   ")
+	g.Geocode("  Qwxyzzy ")
+	if len(misses) != 1 || misses[0].Query != "Qwxyzzy" || misses[0].Result.City.City != "" {
+		t.Errorf("misses = %+v, want only the unmatched query", misses)
+	}
+
+	for _, rate := range []float64{0, 1.5} {
+		_, err := NewGeobedFromRecords(nil, nil, WithMissSampler(rate, func(Miss) {}))
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("WithMissSampler(%v) error = %v, want ErrInvalidConfig", rate, err)
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package geobed
 
 import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -61,22 +66,13 @@ func TestStore_CacheRoundTrip(t *testing.T) {
 	}
 
 	// Load from the temp directory (cache files are uncompressed .dmp)
-	g2 := &GeoBed{config: &GeobedConfig{CacheDir: tmpDir}}
-	lookupOnce.Do(initLookupTables)
-
-	// Load city data from temp cache
-	cities, err := loadGeobedCityData()
+	cities, err := loadGeobedCityData(tmpDir)
 	if err != nil {
-		// The loadGeobedCityData tries embedded first; force filesystem by
-		// using a specific path check. Instead, verify store created valid files.
-		t.Logf("loadGeobedCityData from embedded: %v (expected)", err)
+		t.Fatalf("loadGeobedCityData(%q) error: %v", tmpDir, err)
+	}
+	if len(cities) != len(g1.Cities) {
+		t.Errorf("loaded %d cities from CacheDir, want %d", len(cities), len(g1.Cities))
 	}
-
-	// Instead, do a functional verification: store and re-load via NewGeobed
-	// with the cache dir set. Since openOptionallyCachedFile checks filesystem
-	// first, our temp files should be found.
-	_ = g2
-	_ = cities
 
 	// Verify data integrity via geocoding
 	result := g1.Geocode("Austin, TX")
@@ -111,7 +107,6 @@ func TestStore_DirectoryCreation(t *testing.T) {
 
 func TestLoadGeonamesCities(t *testing.T) {
 	g := &GeoBed{config: defaultConfig()}
-	lookupOnce.Do(initLookupTables)
 
 	err := g.loadGeonamesCities("./geobed-data/cities1000.zip")
 	if err != nil {
@@ -136,7 +131,6 @@ func TestLoadGeonamesCities(t *testing.T) {
 
 func TestLoadGeonamesCities_InvalidPath(t *testing.T) {
 	g := &GeoBed{config: defaultConfig()}
-	lookupOnce.Do(initLookupTables)
 
 	err := g.loadGeonamesCities("/nonexistent/cities1000.zip")
 	if err == nil {
@@ -200,7 +194,6 @@ func TestLoadGeonamesCountryInfo_InvalidPath(t *testing.T) {
 
 func TestLoadDataSets(t *testing.T) {
 	g := &GeoBed{config: defaultConfig()}
-	lookupOnce.Do(initLookupTables)
 
 	err := g.loadDataSets()
 	if err != nil {
@@ -245,7 +238,6 @@ func TestLoadDataSets(t *testing.T) {
 
 func TestLoadMaxMindCities(t *testing.T) {
 	g := &GeoBed{config: defaultConfig()}
-	lookupOnce.Do(initLookupTables)
 	dedup := make(map[string]bool)
 
 	err := g.loadMaxMindCities("./geobed-data/worldcitiespop.txt.gz", dedup)
@@ -286,7 +278,7 @@ func TestLoadMaxMindCities_InvalidPath(t *testing.T) {
 
 func TestOpenOptionallyBzippedFile_EmbeddedBz2(t *testing.T) {
 	// The embedded cache has .bz2 files - this should work
-	reader, cleanup, err := openOptionallyBzippedFile("geobed-cache/g.co.dmp")
+	reader, cleanup, err := openOptionallyBzippedFile(t.TempDir(), "g.co.dmp")
 	if err != nil {
 		t.Fatalf("failed to open embedded bz2: %v", err)
 	}
@@ -298,7 +290,7 @@ func TestOpenOptionallyBzippedFile_EmbeddedBz2(t *testing.T) {
 }
 
 func TestOpenOptionallyBzippedFile_NonexistentFile(t *testing.T) {
-	_, _, err := openOptionallyBzippedFile("nonexistent/file.dmp")
+	_, _, err := openOptionallyBzippedFile(t.TempDir(), "file.dmp")
 	if err == nil {
 		t.Error("expected error for nonexistent file")
 	}
@@ -313,7 +305,7 @@ func TestOpenOptionallyBzippedFile_FilesystemFallback(t *testing.T) {
 	}
 
 	// This should fall back to the uncompressed file (no .bz2 exists)
-	reader, cleanup, err := openOptionallyBzippedFile(dmpPath)
+	reader, cleanup, err := openOptionallyBzippedFile(tmpDir, "test.dmp")
 	if err != nil {
 		t.Fatalf("failed to open uncompressed fallback: %v", err)
 	}
@@ -330,7 +322,7 @@ func TestOpenOptionallyBzippedFile_FilesystemFallback(t *testing.T) {
 
 func TestOpenOptionallyCachedFile_Embedded(t *testing.T) {
 	// Embedded cache files should be accessible
-	fh, err := openOptionallyCachedFile("geobed-cache/g.co.dmp.bz2")
+	fh, err := openOptionallyCachedFile(t.TempDir(), "g.co.dmp.bz2")
 	if err != nil {
 		t.Fatalf("failed to open embedded file: %v", err)
 	}
@@ -346,7 +338,7 @@ func TestOpenOptionallyCachedFile_FilesystemOverride(t *testing.T) {
 	}
 
 	// Filesystem should be preferred
-	fh, err := openOptionallyCachedFile(testFile)
+	fh, err := openOptionallyCachedFile(tmpDir, "test.txt")
 	if err != nil {
 		t.Fatalf("failed to open filesystem file: %v", err)
 	}
@@ -354,7 +346,7 @@ func TestOpenOptionallyCachedFile_FilesystemOverride(t *testing.T) {
 }
 
 func TestOpenOptionallyCachedFile_Nonexistent(t *testing.T) {
-	_, err := openOptionallyCachedFile("/nonexistent/path/file.txt")
+	_, err := openOptionallyCachedFile("/nonexistent/path", "file.txt")
 	if err == nil {
 		t.Error("expected error for nonexistent file in both filesystem and embedded")
 	}
@@ -434,7 +426,6 @@ func TestGeobedCityGob_RoundTrip(t *testing.T) {
 
 func TestLoadGeonamesCities_DataQuality(t *testing.T) {
 	g := &GeoBed{config: defaultConfig()}
-	lookupOnce.Do(initLookupTables)
 
 	if err := g.loadGeonamesCities("./geobed-data/cities1000.zip"); err != nil {
 		t.Fatal(err)
@@ -471,3 +462,297 @@ func TestLoadGeonamesCities_DataQuality(t *testing.T) {
 		}
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Reproducible cache builds
+// ---------------------------------------------------------------------------
+
+func TestStore_Deterministic(t *testing.T) {
+	g, err := NewGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dirA, dirB := t.TempDir(), t.TempDir()
+	for _, dir := range []string{dirA, dirB} {
+		g.config.CacheDir = dir
+		if err := g.store(); err != nil {
+			t.Fatalf("store(%s) error: %v", dir, err)
+		}
+	}
+
+	for _, name := range append(cacheFileNames, manifestFileName) {
+		a, err := os.ReadFile(filepath.Join(dirA, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(filepath.Join(dirB, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s differs between two stores of the same data", name)
+		}
+	}
+
+	m, err := ReadCacheManifest(dirA)
+	if err != nil {
+		t.Fatalf("ReadCacheManifest error: %v", err)
+	}
+	if len(m.Outputs) != len(cacheFileNames) {
+		t.Errorf("manifest has %d outputs, want %d", len(m.Outputs), len(cacheFileNames))
+	}
+	if len(m.Inputs) == 0 {
+		t.Error("manifest has no inputs, want hashes of ./geobed-data files")
+	}
+}
+
+func TestNameIndexEntries_RoundTrip(t *testing.T) {
+	idx := map[string][]int{"paris": {3, 7}, "austin": {1}, "berlin": {2}}
+	entries := sortedNameIndexEntries(idx)
+
+	for i := 1; i < len(entries); i++ {
+		if entries[i-1].Key >= entries[i].Key {
+			t.Fatalf("entries not sorted: %q before %q", entries[i-1].Key, entries[i].Key)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entries); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []nameIndexEntry
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(idx) {
+		t.Fatalf("decoded %d entries, want %d", len(decoded), len(idx))
+	}
+	for _, e := range decoded {
+		if fmt.Sprint(e.Indices) != fmt.Sprint(idx[e.Key]) {
+			t.Errorf("entry %q = %v, want %v", e.Key, e.Indices, idx[e.Key])
+		}
+	}
+}
+
+func TestCompareCities_TotalOrder(t *testing.T) {
+	a := mustCity(t, geobedCityGob{City: "Springfield", Country: "US", Region: "IL", Latitude: 39.8})
+	b := mustCity(t, geobedCityGob{City: "Springfield", Country: "US", Region: "MO", Latitude: 37.2})
+	c := mustCity(t, geobedCityGob{City: "springfield", Country: "US", Region: "IL", Latitude: 39.8})
+
+	if compareCities(a, b) >= 0 {
+		t.Error("same name: region IL should sort before MO")
+	}
+	if compareCities(a, c) == 0 {
+		t.Error("names differing only in case should not compare equal")
+	}
+	if compareCities(a, a) != 0 {
+		t.Error("city should compare equal to itself")
+	}
+}
+
+func TestLoadNameIndex_SortedEntryFormat(t *testing.T) {
+	g, err := NewGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An uncompressed nameIndex.dmp in CacheDir takes precedence over the
+	// embedded cache, so storing into a temp directory exercises the file
+	// just written.
+	g.config.CacheDir = t.TempDir()
+	if err := g.store(); err != nil {
+		t.Fatalf("store() error: %v", err)
+	}
+
+	idx, err := loadNameIndex(g.config.CacheDir)
+	if err != nil {
+		t.Fatalf("loadNameIndex() error: %v", err)
+	}
+	if len(idx) != len(g.nameIndex) {
+		t.Fatalf("loaded %d keys, want %d", len(idx), len(g.nameIndex))
+	}
+	if fmt.Sprint(idx["austin"]) != fmt.Sprint(g.nameIndex["austin"]) {
+		t.Errorf("idx[austin] = %v, want %v", idx["austin"], g.nameIndex["austin"])
+	}
+
+	// Confirm the on-disk file was actually the one decoded.
+	fh, cleanup, err := openOptionallyBzippedFile(g.config.CacheDir, "nameIndex.dmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	var entries []nameIndexEntry
+	if err := gob.NewDecoder(fh).Decode(&entries); err != nil {
+		t.Errorf("nameIndex.dmp was not read from the sorted-entry file: %v", err)
+	}
+}
+
+func TestLoadNameIndex_LegacyMapFormat(t *testing.T) {
+	// Caches generated before the sorted-entry format hold a gob-encoded map.
+	dir := t.TempDir()
+	want := map[string][]int{"austin": {3, 7}, "paris": {1}}
+	b := new(bytes.Buffer)
+	if err := gob.NewEncoder(b).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nameIndex.dmp"), b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := loadNameIndex(dir)
+	if err != nil {
+		t.Fatalf("loadNameIndex() error: %v", err)
+	}
+	if fmt.Sprint(idx) != fmt.Sprint(want) {
+		t.Errorf("loadNameIndex() = %v, want %v", idx, want)
+	}
+}
+
+func TestOpenOptionallyBzippedFile_UncompressedDiskWins(t *testing.T) {
+	// After RegenerateCache, CacheDir holds fresh .dmp files next to stale
+	// .bz2 copies; the fresh uncompressed file must be read.
+	cacheDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(cacheDir, "g.co.dmp"), []byte("fresh"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, cleanup, err := openOptionallyBzippedFile(cacheDir, "g.co.dmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "fresh" {
+		t.Errorf("read %q, want the uncompressed on-disk file", b)
+	}
+}
+
+func TestMatchAffix(t *testing.T) {
+	names := map[string]int{"france": 0, "niger": 1, "nigeria": 2, "new york": 3}
+
+	tests := []struct {
+		query    string
+		prefixes bool
+		wantRank int // -1 = no match
+		wantKind affixKind
+		wantRest string
+	}{
+		{"france", true, 0, affixExact, ""},
+		{"france, paris", true, 0, affixPrefixComma, "paris"},
+		{"france paris", true, 0, affixPrefixSpace, "paris"},
+		{"paris, france", true, 0, affixSuffixComma, "paris"},
+		{"paris france", true, 0, affixSuffixSpace, "paris"},
+		{"lagos nigeria", true, 2, affixSuffixSpace, "lagos"},
+		{"buffalo, new york", true, 3, affixSuffixComma, "buffalo"},
+		{"niger france", true, 0, affixSuffixSpace, "niger"}, // lowest rank wins
+		{"france, ", true, -1, 0, ""},                        // prefix needs a remainder
+		{"france", false, -1, 0, ""},
+		{"france paris", false, -1, 0, ""},
+		{"frances", true, -1, 0, ""},
+		{"", true, -1, 0, ""},
+	}
+
+	for _, tt := range tests {
+		m, ok := matchAffix(tt.query, names, tt.prefixes)
+		if tt.wantRank < 0 {
+			if ok {
+				t.Errorf("matchAffix(%q) = %+v, want no match", tt.query, m)
+			}
+			continue
+		}
+		if !ok || m.rank != tt.wantRank || m.kind != tt.wantKind {
+			t.Errorf("matchAffix(%q) = %+v, %v; want rank %d kind %d", tt.query, m, ok, tt.wantRank, tt.wantKind)
+			continue
+		}
+		if tt.wantKind != affixExact {
+			if _, rest := m.cut(tt.query, tt.query); rest != tt.wantRest {
+				t.Errorf("matchAffix(%q) remainder = %q, want %q", tt.query, rest, tt.wantRest)
+			}
+		}
+	}
+}
+
+// mustCity converts gc like the loaders do, failing the test on error.
+func mustCity(t testing.TB, gc geobedCityGob) GeobedCity {
+	t.Helper()
+	c, err := gc.toCity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestGeobedCity_ID(t *testing.T) {
+	austin := geobedCityGob{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306}
+
+	withID := austin
+	withID.GeonameID = 4671654
+	if got := mustCity(t, withID).ID(); got != "4671654" {
+		t.Errorf("ID() with geonameID = %q, want 4671654", got)
+	}
+
+	id := mustCity(t, austin).ID()
+	if !strings.HasPrefix(id, "h") {
+		t.Fatalf("ID() without geonameID = %q, want h-prefixed hash", id)
+	}
+	if again := mustCity(t, austin).ID(); again != id {
+		t.Errorf("ID() not deterministic: %q vs %q", id, again)
+	}
+
+	jitter := austin
+	jitter.Latitude += 0.000001
+	if got := mustCity(t, jitter).ID(); got != id {
+		t.Errorf("sub-rounding coordinate change altered ID: %q vs %q", got, id)
+	}
+
+	// Region and population are not part of the hash; name, country, and
+	// coordinates are.
+	other := austin
+	other.Region, other.Population = "XX", 5
+	if got := mustCity(t, other).ID(); got != id {
+		t.Errorf("region/population change altered ID: %q vs %q", got, id)
+	}
+	for _, mod := range []func(*geobedCityGob){
+		func(c *geobedCityGob) { c.City = "Austn" },
+		func(c *geobedCityGob) { c.Country = "CA" },
+		func(c *geobedCityGob) { c.Longitude = -97.8 },
+	} {
+		c := austin
+		mod(&c)
+		if got := mustCity(t, c).ID(); got == id {
+			t.Errorf("ID() of %+v collides with original", c)
+		}
+	}
+}
+
+func TestGeobedCity_IDUnique(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]int, len(g.Cities))
+	dups := 0
+	for _, c := range g.Cities {
+		if seen[c.ID()]++; seen[c.ID()] == 2 {
+			dups++
+		}
+	}
+	t.Logf("%d cities, %d duplicated IDs", len(g.Cities), dups)
+	if dups != 0 {
+		t.Errorf("%d duplicated IDs among %d cities", dups, len(g.Cities))
+	}
+
+	// The embedded cache records Geonames IDs, so shipped IDs are the
+	// Geonames record IDs rather than hashes that a refresh would change.
+	for _, c := range g.Cities {
+		if c.GeonameID() <= 0 {
+			t.Fatalf("embedded city %s, %s has no Geonames ID", c.City, c.Country())
+		}
+	}
+	if got := g.Geocode("Austin, TX").ID(); got != "4671654" {
+		t.Errorf("Geocode(Austin, TX).ID() = %q, want the Geonames ID 4671654", got)
+	}
+}
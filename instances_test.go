@@ -0,0 +1,67 @@
+package geobed
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestNewGeobed_IndependentDatasets loads two datasets side by side, as a
+// production instance and a canary would, and checks that neither sees the
+// other's cities or codes.
+func TestNewGeobed_IndependentDatasets(t *testing.T) {
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), testCityRows)
+	writeCitiesZip(t, filepath.Join(dir, "cities5000.zip"), []string{
+		"4717560\tParis\tParis\t\t33.66094\t-95.55551\tP\tPPLA2\tUS\t\tTX\t277\t\t\t24171\t\t183\tAmerica/Chicago\t2023-01-01",
+		"3413829\tReykjavik\tReykjavik\t\t64.13548\t-21.89541\tP\tPPLC\tIS\t\t39\t\t\t\t118918\t\t\tAtlantic/Reykjavik\t2023-01-01",
+	})
+
+	datasets := []Dataset{DatasetCities15000, DatasetCities5000}
+	instances := make([]*GeoBed, len(datasets))
+	errs := make([]error, len(datasets))
+	var wg sync.WaitGroup
+	for i, d := range datasets {
+		cacheDir := t.TempDir()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instances[i], errs[i] = NewGeobed(WithDataset(d), WithOffline(), WithDataDir(dir), WithCacheDir(cacheDir))
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("NewGeobed(%s) error = %v", datasets[i], err)
+		}
+	}
+	prod, canary := instances[0], instances[1]
+
+	tests := []struct {
+		g       *GeoBed
+		query   string
+		country string
+		region  string
+	}{
+		{prod, "Paris", "FR", "11"},
+		{prod, "London", "GB", "ENG"},
+		{canary, "Paris", "US", "TX"},
+		{canary, "Reykjavik", "IS", "39"},
+	}
+	for _, tt := range tests {
+		c := tt.g.Geocode(tt.query)
+		if c.Country() != tt.country || c.Region() != tt.region {
+			t.Errorf("%s: Geocode(%q) = %s, %s, %s; want %s, %s", tt.g.config.Dataset, tt.query, c.City, c.Region(), c.Country(), tt.country, tt.region)
+		}
+	}
+
+	for _, c := range prod.Cities {
+		if c.Country() == "IS" || c.Country() == "US" {
+			t.Errorf("%s holds canary city %s, %s", prod.config.Dataset, c.City, c.Country())
+		}
+	}
+	if len(prod.Cities) != len(testCityRows) || len(canary.Cities) != 2 {
+		t.Errorf("loaded %d and %d cities, want %d and 2", len(prod.Cities), len(canary.Cities), len(testCityRows))
+	}
+}
@@ -0,0 +1,75 @@
+package geobed
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// NearestCities returns, for each of points, the city ReverseGeocode would
+// return for it with opts, for spatial joins of large point sets against
+// the city data. Entries for points out of range or with NaN/Inf
+// coordinates are the zero GeobedCity.
+//
+// Points are visited in S2 cell order rather than input order, so the
+// candidate cities are looked up once per cell and reused
+// by every point in it; only the distance ranking runs per point. Working
+// memory is about 70 bytes per point, so
+// jobs over tens of millions of rows should pass chunks of a few million,
+// which can run on separate goroutines.
+func (g *GeoBed) NearestCities(points []LatLng, opts ...ReverseGeocodeOptions) []GeobedCity {
+	options := ReverseGeocodeOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	g.ensureCellIndex()
+
+	cells := make([]s2.CellID, len(points))
+	order := make([]int, 0, len(points))
+	for i, p := range points {
+		if math.IsNaN(p.Lat) || math.IsNaN(p.Lng) ||
+			math.IsInf(p.Lat, 0) || math.IsInf(p.Lng, 0) {
+			continue
+		}
+		cells[i] = s2.CellIDFromLatLng(s2.LatLngFromDegrees(p.Lat, p.Lng)).Parent(s2CellLevel)
+		order = append(order, i)
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return cmp.Compare(cells[a], cells[b])
+	})
+
+	out := make([]GeobedCity, len(points))
+	var (
+		cell       s2.CellID
+		remote     bool
+		candidates []int
+	)
+	for _, i := range order {
+		ll := s2.LatLngFromDegrees(points[i].Lat, points[i].Lng)
+		if cells[i] != cell {
+			// A level-10 cell lies in a single coarse cell, so every point
+			// in it is equally remote.
+			cell = cells[i]
+			remote = options.NearestIfRemote && g.isRemote(ll)
+			candidates = nil
+			if !remote {
+				candidates = g.cellCandidates(cell, options.MinPopulation)
+			}
+		}
+		if remote {
+			out[i] = g.nearestCity(ll, options.MinPopulation)
+			continue
+		}
+		near, best, ok := g.pickReverse(ll, candidates, options.Metro)
+		switch {
+		case !ok:
+		case options.IncludeNeighborhoods:
+			out[i] = near.city
+		default:
+			out[i] = best.city
+		}
+	}
+	return out
+}
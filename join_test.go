@@ -0,0 +1,67 @@
+package geobed
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestNearestCities(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clusters of nearby points, so cells hold several, plus open sea and
+	// invalid input.
+	rng := rand.New(rand.NewPCG(1, 2))
+	var points []LatLng
+	for range 50 {
+		lat, lng := rng.Float64()*120-60, rng.Float64()*360-180
+		for range 5 {
+			points = append(points, LatLng{lat + rng.Float64()*0.1, lng + rng.Float64()*0.1})
+		}
+	}
+	points = append(points, LatLng{48.8566, 2.3522}, LatLng{30, -40}, LatLng{math.NaN(), 0})
+
+	for _, opts := range []ReverseGeocodeOptions{
+		{},
+		{IncludeNeighborhoods: true},
+		{MinPopulation: 100000, NearestIfRemote: true},
+	} {
+		got := g.NearestCities(points, opts)
+		if len(got) != len(points) {
+			t.Fatalf("NearestCities() returned %d cities for %d points", len(got), len(points))
+		}
+		for i, p := range points {
+			if want := g.ReverseGeocode(p.Lat, p.Lng, opts); got[i] != want {
+				t.Errorf("NearestCities(%+v)[%d] for %v = %s, want %s as ReverseGeocode", opts, i, p, got[i].City, want.City)
+			}
+		}
+	}
+}
+
+func BenchmarkNearestCities(b *testing.B) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		b.Fatal(err)
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	points := make([]LatLng, 100000)
+	for i := range points {
+		points[i] = LatLng{48 + rng.Float64()*6, 2 + rng.Float64()*10} // France to Poland
+	}
+
+	b.Run("NearestCities", func(b *testing.B) {
+		for b.Loop() {
+			g.NearestCities(points)
+		}
+	})
+	b.Run("ReverseGeocode", func(b *testing.B) {
+		for b.Loop() {
+			for _, p := range points {
+				g.ReverseGeocode(p.Lat, p.Lng)
+			}
+		}
+	})
+}
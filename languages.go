@@ -0,0 +1,61 @@
+package geobed
+
+import "strings"
+
+// LanguageTag is one entry of CountryInfo.Languages, such as "en-US" or "fr".
+type LanguageTag struct {
+	Language string // ISO 639 language code, e.g. "en" or "fil"
+	Region   string // Region subtag, e.g. "US" ("" if none)
+}
+
+// String returns the tag in its Geonames form, e.g. "en-US".
+func (t LanguageTag) String() string {
+	if t.Region == "" {
+		return t.Language
+	}
+	return t.Language + "-" + t.Region
+}
+
+// ParsedLanguages splits Languages ("en-US,es-US,haw,fr") into tags, most
+// widely spoken first as listed by Geonames. Empty entries are skipped.
+func (c CountryInfo) ParsedLanguages() []LanguageTag {
+	var tags []LanguageTag
+	for _, raw := range strings.Split(c.Languages, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		lang, region, _ := strings.Cut(raw, "-")
+		tags = append(tags, LanguageTag{Language: toLower(lang), Region: region})
+	}
+	return tags
+}
+
+// PrimaryLanguage returns the first language listed for the country. ok is
+// false when the country has no languages.
+func (c CountryInfo) PrimaryLanguage() (tag LanguageTag, ok bool) {
+	if tags := c.ParsedLanguages(); len(tags) > 0 {
+		return tags[0], true
+	}
+	return LanguageTag{}, false
+}
+
+// LanguageAt returns the primary language of the country containing the
+// city ReverseGeocode finds for lat/lng, e.g. to pick a default locale. ok
+// is false when no city is in range or its country lists no languages.
+func (g *GeoBed) LanguageAt(lat, lng float64) (tag LanguageTag, ok bool) {
+	co, ok := g.countryInfo(g.ReverseGeocode(lat, lng).Country())
+	if !ok {
+		return LanguageTag{}, false
+	}
+	return co.PrimaryLanguage()
+}
+
+// countryInfo returns the loaded country with the given ISO code.
+func (g *GeoBed) countryInfo(iso string) (CountryInfo, bool) {
+	i, ok := g.countryISO[iso]
+	if !ok {
+		return CountryInfo{}, false
+	}
+	return g.Countries[i], true
+}
@@ -0,0 +1,62 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsedLanguages(t *testing.T) {
+	tests := []struct {
+		languages string
+		want      []LanguageTag
+	}{
+		{"en-US,es-US,haw,fr", []LanguageTag{{"en", "US"}, {"es", "US"}, {"haw", ""}, {"fr", ""}}},
+		{"de-CH, fr-CH ,it-CH,rm", []LanguageTag{{"de", "CH"}, {"fr", "CH"}, {"it", "CH"}, {"rm", ""}}},
+		{"fr-FR,,frp", []LanguageTag{{"fr", "FR"}, {"frp", ""}}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := CountryInfo{Languages: tt.languages}.ParsedLanguages()
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParsedLanguages(%q) = %v, want %v", tt.languages, got, tt.want)
+		}
+	}
+
+	if tag, ok := (CountryInfo{Languages: "en-IN,hi,bn"}).PrimaryLanguage(); !ok || tag.String() != "en-IN" {
+		t.Errorf("PrimaryLanguage() = %v, %v; want en-IN", tag, ok)
+	}
+	if tag, ok := (CountryInfo{}).PrimaryLanguage(); ok {
+		t.Errorf("PrimaryLanguage() of empty Languages = %v, want not ok", tag)
+	}
+	if s := (LanguageTag{Language: "haw"}).String(); s != "haw" {
+		t.Errorf("String() = %q, want haw", s)
+	}
+}
+
+func TestLanguageAt(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		lat, lng float64
+		want     string
+	}{
+		{"Paris", 48.8566, 2.3522, "fr-FR"},
+		{"Berlin", 52.52, 13.405, "de"},
+		{"Austin", 30.2672, -97.7431, "en-US"},
+		{"Tokyo", 35.6762, 139.6503, "ja"},
+	}
+	for _, tt := range tests {
+		tag, ok := g.LanguageAt(tt.lat, tt.lng)
+		if !ok || tag.String() != tt.want {
+			t.Errorf("LanguageAt(%s) = %v, %v; want %s", tt.name, tag, ok, tt.want)
+		}
+	}
+
+	if tag, ok := g.LanguageAt(0, -140); ok {
+		t.Errorf("LanguageAt(Pacific) = %v, want not ok", tag)
+	}
+}
@@ -0,0 +1,51 @@
+package geobed
+
+import (
+	"cmp"
+	"slices"
+)
+
+// LargestCities returns up to n cities of the country with the given ISO
+// code, most populous first, e.g. for dropdown defaults or fallback
+// coordinates. Ties keep the name order of Cities. Supplemental places are
+// left out unless WithReversePlaces is set, and near-duplicates with
+// WithDedupe. The per-country ranking is built on first use; the returned
+// slice is a copy.
+func (g *GeoBed) LargestCities(iso string, n int) []GeobedCity {
+	if n <= 0 {
+		return nil
+	}
+	idxs := g.citiesByCountry()[toUpper(iso)]
+	out := make([]GeobedCity, 0, min(n, len(idxs)))
+	d := g.deduper()
+	for _, i := range idxs {
+		if len(out) == n {
+			break
+		}
+		if d.keep(g.Cities[i]) {
+			out = append(out, g.Cities[i])
+		}
+	}
+	return out
+}
+
+// citiesByCountry returns city indices per ISO country code by descending
+// population, building them on first use.
+func (g *GeoBed) citiesByCountry() map[string][]int {
+	g.byCountryOnce.Do(func() {
+		g.byCountry = make(map[string][]int)
+		for i, c := range g.Cities {
+			if c.source == SourceCustom && !g.config.ReversePlaces {
+				continue
+			}
+			cc := c.Country()
+			g.byCountry[cc] = append(g.byCountry[cc], i)
+		}
+		for _, idxs := range g.byCountry {
+			slices.SortStableFunc(idxs, func(a, b int) int {
+				return cmp.Compare(g.Cities[b].Population, g.Cities[a].Population)
+			})
+		}
+	})
+	return g.byCountry
+}
@@ -0,0 +1,29 @@
+package geobed
+
+import "testing"
+
+func TestLargestCities(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Lyon", Country: "FR", Latitude: 45.75, Longitude: 4.85, Population: 522_000},
+		{City: "Paris", Country: "FR", Latitude: 48.85, Longitude: 2.35, Population: 2_138_000},
+		{City: "Marseille", Country: "FR", Latitude: 43.3, Longitude: 5.38, Population: 870_000},
+		{City: "Berlin", Country: "DE", Latitude: 52.52, Longitude: 13.4, Population: 3_426_000},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := g.LargestCities("fr", 2)
+	if len(got) != 2 || got[0].City != "Paris" || got[1].City != "Marseille" {
+		t.Errorf("LargestCities(fr, 2) = %v", got)
+	}
+	if got := g.LargestCities("FR", 10); len(got) != 3 || got[2].City != "Lyon" {
+		t.Errorf("LargestCities(FR, 10) = %v, want all 3 French cities", got)
+	}
+	if got := g.LargestCities("XX", 5); len(got) != 0 {
+		t.Errorf("LargestCities(XX, 5) = %v, want none", got)
+	}
+	if got := g.LargestCities("FR", 0); got != nil {
+		t.Errorf("LargestCities(FR, 0) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,55 @@
+package geobed
+
+import (
+	"fmt"
+	"sort"
+)
+
+// checkCitiesSorted verifies the invariant that Cities is ordered by
+// case-insensitive name (Cities.Less), which CityAt's binary search relies
+// on. It returns the position of the first out-of-order city.
+func checkCitiesSorted(cities Cities) error {
+	prev := ""
+	for i, c := range cities {
+		cur := toLower(c.City)
+		if i > 0 && cur < prev {
+			return fmt.Errorf("cities not sorted by name at index %d: %q after %q", i, c.City, cities[i-1].City)
+		}
+		prev = cur
+	}
+	return nil
+}
+
+// CityAt returns the city with exactly the given name (case-insensitive),
+// country code, and region code, without any query parsing or scoring. An
+// empty country or region matches any value. When several records match,
+// the most populous is returned; ties go to the first in sorted order.
+//
+// The lookup is a binary search over the name-sorted Cities slice.
+func (g *GeoBed) CityAt(name, country, region string) (GeobedCity, bool) {
+	key := toLower(name)
+	if key == "" {
+		return GeobedCity{}, false
+	}
+	i := sort.Search(len(g.Cities), func(i int) bool {
+		return toLower(g.Cities[i].City) >= key
+	})
+
+	best := -1
+	for ; i < len(g.Cities) && toLower(g.Cities[i].City) == key; i++ {
+		c := g.Cities[i]
+		if country != "" && c.Country() != country {
+			continue
+		}
+		if region != "" && c.Region() != region {
+			continue
+		}
+		if best < 0 || c.Population > g.Cities[best].Population {
+			best = i
+		}
+	}
+	if best < 0 {
+		return GeobedCity{}, false
+	}
+	return g.Cities[best], true
+}
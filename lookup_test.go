@@ -0,0 +1,66 @@
+package geobed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCitiesSorted(t *testing.T) {
+	sorted := Cities{{City: "austin"}, {City: "Austin"}, {City: "Paris"}, {City: "paris"}}
+	if err := checkCitiesSorted(sorted); err != nil {
+		t.Errorf("checkCitiesSorted(sorted) = %v", err)
+	}
+	if err := checkCitiesSorted(Cities{{City: "Paris"}, {City: "Austin"}}); err == nil {
+		t.Error("checkCitiesSorted accepted out-of-order cities")
+	}
+	if err := checkCitiesSorted(nil); err != nil {
+		t.Errorf("checkCitiesSorted(nil) = %v", err)
+	}
+}
+
+func TestCityAt(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkCitiesSorted(g.Cities); err != nil {
+		t.Fatalf("loaded cities violate sort invariant: %v", err)
+	}
+
+	tests := []struct {
+		name, country, region string
+		wantCountry           string
+		wantRegion            string
+	}{
+		{"Austin", "US", "TX", "US", "TX"},
+		{"austin", "US", "TX", "US", "TX"},
+		{"Paris", "FR", "", "FR", "11"},
+		{"Paris", "US", "TX", "US", "TX"},
+		{"London", "", "", "GB", "ENG"},
+	}
+	for _, tt := range tests {
+		c, ok := g.CityAt(tt.name, tt.country, tt.region)
+		if !ok {
+			t.Errorf("CityAt(%q, %q, %q) found nothing", tt.name, tt.country, tt.region)
+			continue
+		}
+		if !strings.EqualFold(c.City, tt.name) || c.Country() != tt.wantCountry || c.Region() != tt.wantRegion {
+			t.Errorf("CityAt(%q, %q, %q) = %s, %s, %s; want country %s region %s",
+				tt.name, tt.country, tt.region, c.City, c.Country(), c.Region(), tt.wantCountry, tt.wantRegion)
+		}
+	}
+
+	for _, miss := range [][3]string{{"Austin", "FR", ""}, {"Zxqwvbn", "", ""}, {"", "", ""}, {"Austi", "US", ""}} {
+		if c, ok := g.CityAt(miss[0], miss[1], miss[2]); ok {
+			t.Errorf("CityAt(%q, %q, %q) = %s, want no match", miss[0], miss[1], miss[2], c.City)
+		}
+	}
+
+	// Every city must be reachable by its own name, country, and region.
+	for _, want := range sampleCities(g, 200, propertySeed) {
+		c, ok := g.CityAt(want.City, want.Country(), want.Region())
+		if !ok || toLower(c.City) != toLower(want.City) || c.Population < want.Population {
+			t.Errorf("CityAt(%q, %q, %q) = %+v, %v", want.City, want.Country(), want.Region(), c, ok)
+		}
+	}
+}
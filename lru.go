@@ -0,0 +1,112 @@
+package geobed
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// CacheStats reports the effectiveness of one of the optional query caches.
+type CacheStats struct {
+	Hits     uint64 // Lookups answered from the cache
+	Misses   uint64 // Lookups that had to be computed
+	Entries  int    // Entries currently cached
+	Capacity int    // Maximum number of entries (0 = cache disabled)
+}
+
+// HitRate returns Hits / (Hits + Misses), or 0 before any lookup.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// lruCache is a fixed-capacity, concurrency-safe least-recently-used cache.
+// A nil *lruCache is valid and caches nothing.
+type lruCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List // front = most recently used
+	items    map[K]*list.Element
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// newLRUCache returns a cache holding up to capacity entries, or nil when
+// capacity <= 0.
+func newLRUCache[K comparable, V any](capacity int) *lruCache[K, V] {
+	if capacity <= 0 {
+		return nil
+	}
+	return &lruCache[K, V]{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element, capacity),
+	}
+}
+
+// get returns the cached value for key and marks it most recently used.
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+	c.mu.Lock()
+	el, ok := c.items[key]
+	if !ok {
+		c.mu.Unlock()
+		c.misses.Add(1)
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	value := el.Value.(*lruEntry[K, V]).value
+	c.mu.Unlock()
+
+	c.hits.Add(1)
+	return value, true
+}
+
+// add stores value under key, evicting the least recently used entry when
+// the cache is full.
+func (c *lruCache[K, V]) add(key K, value V) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry[K, V]).value = value
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// stats returns a snapshot of the cache counters.
+func (c *lruCache[K, V]) stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	entries := c.ll.Len()
+	c.mu.Unlock()
+	return CacheStats{
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Entries:  entries,
+		Capacity: c.capacity,
+	}
+}
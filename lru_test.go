@@ -0,0 +1,105 @@
+package geobed
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLRUCache_Eviction(t *testing.T) {
+	c := newLRUCache[string, int](2)
+	c.add("a", 1)
+	c.add("b", 2)
+	if _, ok := c.get("a"); !ok { // a becomes most recently used
+		t.Fatal("get(a) missed")
+	}
+	c.add("c", 3) // evicts b
+
+	if _, ok := c.get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Errorf("get(a) = %v, %v; want 1, true", v, ok)
+	}
+	if v, ok := c.get("c"); !ok || v != 3 {
+		t.Errorf("get(c) = %v, %v; want 3, true", v, ok)
+	}
+
+	st := c.stats()
+	if st.Entries != 2 || st.Capacity != 2 {
+		t.Errorf("stats entries/capacity = %d/%d, want 2/2", st.Entries, st.Capacity)
+	}
+	if st.Hits != 3 || st.Misses != 1 {
+		t.Errorf("stats hits/misses = %d/%d, want 3/1", st.Hits, st.Misses)
+	}
+	if got := st.HitRate(); got != 0.75 {
+		t.Errorf("HitRate() = %v, want 0.75", got)
+	}
+}
+
+func TestLRUCache_UpdateExisting(t *testing.T) {
+	c := newLRUCache[string, int](2)
+	c.add("a", 1)
+	c.add("a", 2)
+	if v, _ := c.get("a"); v != 2 {
+		t.Errorf("get(a) = %d, want 2", v)
+	}
+	if n := c.stats().Entries; n != 1 {
+		t.Errorf("Entries = %d, want 1", n)
+	}
+}
+
+func TestLRUCache_NilIsDisabled(t *testing.T) {
+	c := newLRUCache[string, int](0)
+	if c != nil {
+		t.Fatal("newLRUCache(0) should return nil")
+	}
+	c.add("a", 1)
+	if _, ok := c.get("a"); ok {
+		t.Error("nil cache returned a hit")
+	}
+	if st := c.stats(); st != (CacheStats{}) {
+		t.Errorf("nil cache stats = %+v, want zero", st)
+	}
+}
+
+func TestLRUCache_Concurrent(t *testing.T) {
+	c := newLRUCache[int, int](16)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.add((w*i)%32, i)
+				c.get(i % 32)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if n := c.stats().Entries; n > 16 {
+		t.Errorf("Entries = %d, exceeds capacity 16", n)
+	}
+}
+
+func TestQueryCache(t *testing.T) {
+	g, err := NewGeobedFromRecords(fixtureCities, nil, WithQueryCache(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := g.Geocode("Austin, TX")
+	second := g.Geocode("Austin, TX")
+	if first != second || first.City != "Austin" {
+		t.Errorf("cached Geocode(Austin, TX) = %q then %q, want Austin twice", first.City, second.City)
+	}
+
+	st := g.QueryCacheStats()
+	if st.Hits != 1 || st.Misses != 1 {
+		t.Errorf("QueryCacheStats hits/misses = %d/%d, want 1/1", st.Hits, st.Misses)
+	}
+
+	g.queryCache = nil
+	if st := g.QueryCacheStats(); st.Capacity != 0 {
+		t.Errorf("disabled cache Capacity = %d, want 0", st.Capacity)
+	}
+}
@@ -0,0 +1,77 @@
+package geobed
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/golang/geo/s2"
+)
+
+// NearbyCity is a city and its distance from a query point.
+type NearbyCity struct {
+	City       GeobedCity
+	DistanceKm float64
+}
+
+// MajorCitiesNear returns up to n cities with a population of at least
+// minPop nearest to lat/lng, closest first, for "nearest big city" features.
+// Unlike ReverseGeocode there is no distance limit: the n nearest qualifying
+// cities are returned wherever they are.
+//
+// Cities are kept in a population-ordered sub-index built on first use, so
+// only the cities above the threshold are examined. Near-duplicates are
+// dropped with WithDedupe. It returns nil for n <= 0 or NaN/Inf
+// coordinates.
+func (g *GeoBed) MajorCitiesNear(lat, lng float64, n int, minPop int32) []NearbyCity {
+	if n <= 0 || math.IsNaN(lat) || math.IsNaN(lng) ||
+		math.IsInf(lat, 0) || math.IsInf(lng, 0) {
+		return nil
+	}
+	byPop := g.citiesByPopulation()
+	k := sort.Search(len(byPop), func(i int) bool {
+		return g.Cities[byPop[i]].Population < minPop
+	})
+
+	queryLL := s2.LatLngFromDegrees(lat, lng)
+	nearest := make([]NearbyCity, 0, min(n, k))
+	dd := g.deduper()
+	for _, idx := range byPop[:k] {
+		c := g.Cities[idx]
+		d := distanceKm(queryLL, cityLatLng(c))
+		if len(nearest) == n && d >= nearest[n-1].DistanceKm {
+			continue
+		}
+		// The more populous of two near-duplicates is visited first.
+		if !dd.keep(c) {
+			continue
+		}
+		// Insert in distance order. byPop is visited in population order, so
+		// among equally distant cities the more populous one stays first.
+		i := sort.Search(len(nearest), func(i int) bool { return nearest[i].DistanceKm > d })
+		if len(nearest) < n {
+			nearest = append(nearest, NearbyCity{})
+		}
+		copy(nearest[i+1:], nearest[i:])
+		nearest[i] = NearbyCity{City: c, DistanceKm: d}
+	}
+	return nearest
+}
+
+// citiesByPopulation returns the indices of all reverse-geocodable cities
+// with a known population, most populous first (ties by index).
+func (g *GeoBed) citiesByPopulation() []int {
+	g.byPopOnce.Do(func() {
+		for i, c := range g.Cities {
+			if c.Population <= 0 || (c.source == SourceCustom && !g.config.ReversePlaces) {
+				continue
+			}
+			g.byPop = append(g.byPop, i)
+		}
+		slices.SortStableFunc(g.byPop, func(a, b int) int {
+			return cmp.Compare(g.Cities[b].Population, g.Cities[a].Population)
+		})
+	})
+	return g.byPop
+}
@@ -0,0 +1,81 @@
+package geobed
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMajorCitiesNear(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := g.MajorCitiesNear(30.2672, -97.7431, 3, 1_000_000) // Austin, TX
+	want := []string{"San Antonio", "Houston", "Fort Worth"}
+	if len(got) != len(want) {
+		t.Fatalf("MajorCitiesNear returned %d cities, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].City.City != w {
+			t.Errorf("MajorCitiesNear[%d] = %s (%.0f km), want %s", i, got[i].City.City, got[i].DistanceKm, w)
+		}
+	}
+
+	// With a lower threshold Austin itself is the nearest.
+	if got := g.MajorCitiesNear(30.2672, -97.7431, 1, 500_000); len(got) != 1 || got[0].City.City != "Austin" {
+		t.Errorf("MajorCitiesNear(minPop=500K) = %+v, want Austin", got)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		lat, lng float64
+		n        int
+	}{
+		{"zero n", 0, 0, 0},
+		{"negative n", 0, 0, -1},
+		{"NaN", math.NaN(), 0, 5},
+		{"Inf", 0, math.Inf(-1), 5},
+	} {
+		if got := g.MajorCitiesNear(tt.lat, tt.lng, tt.n, 0); got != nil {
+			t.Errorf("%s: MajorCitiesNear = %v, want nil", tt.name, got)
+		}
+	}
+}
+
+func TestMajorCitiesNear_MatchesFullScan(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const minPop = 250_000
+	for _, p := range []LatLng{{51.5, -0.12}, {-33.9, 151.2}, {0, 0}, {64.1, -21.9}} {
+		got := g.MajorCitiesNear(p.Lat, p.Lng, 5, minPop)
+		if len(got) != 5 {
+			t.Fatalf("MajorCitiesNear(%v) returned %d cities, want 5", p, len(got))
+		}
+		for i, c := range got {
+			if c.City.Population < minPop {
+				t.Errorf("MajorCitiesNear(%v)[%d] %s has population %d < %d", p, i, c.City.City, c.City.Population, minPop)
+			}
+			if i > 0 && c.DistanceKm < got[i-1].DistanceKm {
+				t.Errorf("MajorCitiesNear(%v) not sorted by distance at %d", p, i)
+			}
+		}
+
+		// No qualifying city may be closer than the farthest one returned.
+		q := GeobedCity{Latitude: float32(p.Lat), Longitude: float32(p.Lng)}
+		limit := got[len(got)-1].DistanceKm
+		closer := 0
+		for _, c := range g.Cities {
+			if c.Population >= minPop && cityDistanceKm(q, c) < limit-0.01 {
+				closer++
+			}
+		}
+		if closer > len(got)-1 {
+			t.Errorf("MajorCitiesNear(%v): full scan found %d cities closer than %.1f km, want at most %d",
+				p, closer, limit, len(got)-1)
+		}
+	}
+}
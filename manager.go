@@ -0,0 +1,68 @@
+package geobed
+
+import "sync/atomic"
+
+// Manager holds the current GeoBed of an application and lets it be
+// replaced while queries are running. Callers fetch the instance with Get
+// for each request (or batch) instead of keeping it, so a refreshed
+// instance installed with Swap or Reload is picked up transparently;
+// queries already running on the old instance finish on it. The zero
+// value holds no instance.
+type Manager struct {
+	current atomic.Pointer[GeoBed]
+}
+
+// NewManager returns a Manager holding g.
+func NewManager(g *GeoBed) *Manager {
+	m := &Manager{}
+	m.current.Store(g)
+	return m
+}
+
+// Get returns the current instance, or nil if none was set.
+func (m *Manager) Get() *GeoBed {
+	return m.current.Load()
+}
+
+// Swap installs g as the current instance and returns the previous one.
+func (m *Manager) Swap(g *GeoBed) *GeoBed {
+	return m.current.Swap(g)
+}
+
+// Reload builds a new instance with NewGeobed(opts...) and installs it.
+// On error the current instance is kept.
+func (m *Manager) Reload(opts ...Option) error {
+	g, err := NewGeobed(opts...)
+	if err != nil {
+		return err
+	}
+	m.current.Store(g)
+	return nil
+}
+
+// Update installs the instance fn builds from the current one, e.g. with
+// NewGeobedFromRecords over the Record of each of its cities plus new ones;
+// Record copies every field a record can hold, so alternate names and IDs
+// survive the update. fn must not modify the instance it is given, which
+// queries may still be using. If another Swap, Reload, or Update installs
+// an instance in the meantime, fn runs again on that one, so concurrent
+// updates are not lost. On error the current instance is kept.
+func (m *Manager) Update(fn func(*GeoBed) (*GeoBed, error)) error {
+	for {
+		old := m.current.Load()
+		g, err := fn(old)
+		if err != nil {
+			return err
+		}
+		if m.current.CompareAndSwap(old, g) {
+			return nil
+		}
+	}
+}
+
+// Swap replaces the instance returned by GetDefaultGeobed and returns the
+// previous one (nil if it was never initialized). Use it to publish a
+// refreshed instance to code that calls GetDefaultGeobed per request.
+func Swap(g *GeoBed) *GeoBed {
+	return defaultGeobed.Swap(g)
+}
@@ -0,0 +1,129 @@
+package geobed
+
+import (
+	"sync"
+	"testing"
+)
+
+func newTestManagerInstance(t *testing.T, region string) *GeoBed {
+	t.Helper()
+	g, err := NewGeobedFromRecords([]CityRecord{{City: "Austin", Country: "US", Region: region, Latitude: 30, Longitude: -97}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestManager_Swap(t *testing.T) {
+	if (&Manager{}).Get() != nil {
+		t.Error("zero Manager holds an instance")
+	}
+
+	first, second := newTestManagerInstance(t, "TX"), newTestManagerInstance(t, "MN")
+	m := NewManager(first)
+	if m.Get() != first {
+		t.Fatal("Get() did not return the initial instance")
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if c := m.Get().Geocode("Austin"); c.City != "Austin" {
+					t.Error("Geocode on the current instance returned no city")
+					return
+				}
+			}
+		}()
+	}
+	if old := m.Swap(second); old != first {
+		t.Error("Swap() did not return the previous instance")
+	}
+	wg.Wait()
+	if m.Get() != second {
+		t.Error("Get() after Swap did not return the new instance")
+	}
+}
+
+func TestManager_ReloadError(t *testing.T) {
+	g := newTestManagerInstance(t, "TX")
+	m := NewManager(g)
+	if err := m.Reload(WithDataset("cities42")); err == nil {
+		t.Fatal("Reload with an unknown dataset succeeded")
+	}
+	if m.Get() != g {
+		t.Error("failed Reload replaced the current instance")
+	}
+}
+
+func TestSwap_DefaultGeobed(t *testing.T) {
+	if _, err := GetDefaultGeobed(); err != nil {
+		t.Fatal(err)
+	}
+	g := newTestManagerInstance(t, "TX")
+	old := Swap(g)
+	defer Swap(old)
+
+	if got, err := GetDefaultGeobed(); err != nil || got != g {
+		t.Errorf("GetDefaultGeobed() after Swap = %p, %v; want %p", got, err, g)
+	}
+}
+
+func TestManager_UpdateWhileQuerying(t *testing.T) {
+	m := NewManager(newTestManagerInstance(t, "TX"))
+	addCity := func(name string) func(*GeoBed) (*GeoBed, error) {
+		return func(old *GeoBed) (*GeoBed, error) {
+			records := make([]CityRecord, 0, len(old.Cities)+1)
+			for _, c := range old.Cities {
+				records = append(records, c.Record())
+			}
+			records = append(records, CityRecord{City: name, Country: "US", Region: "TX", Latitude: 31, Longitude: -96})
+			return NewGeobedFromRecords(records, nil)
+		}
+	}
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				g := m.Get()
+				if g.Geocode("Austin").City != "Austin" || g.ReverseGeocode(30, -97).City == "" {
+					t.Error("query during Update returned no city")
+					return
+				}
+				g.LargestCities("US", 5)
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	for _, name := range []string{"Waco", "Temple", "Killeen", "Belton"} {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			if err := m.Update(addCity(name)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	writers.Wait()
+	close(done)
+	readers.Wait()
+
+	g := m.Get()
+	for _, name := range []string{"Waco", "Temple", "Killeen", "Belton"} {
+		if g.Geocode(name).City != name {
+			t.Errorf("%s lost by a concurrent Update", name)
+		}
+	}
+}
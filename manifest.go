@@ -0,0 +1,114 @@
+package geobed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifestFileName is the name of the manifest written next to the cache files.
+const manifestFileName = "manifest.json"
+
+// CacheManifest records the inputs and outputs of a cache build. Because the
+// cache is generated deterministically, two builds from the same raw dumps
+// produce identical manifests, which makes regenerated caches auditable.
+//
+// The manifest deliberately contains no build timestamps or host
+// information. DatasetDate is the date of the raw data rather than of the
+// build; NewGeobed reads it for GeoBed.DatasetDate, since the modification
+// time of cache files only tells when they were checked out or copied.
+type CacheManifest struct {
+	DatasetDate string         `json:"dataset_date,omitempty"` // Date of the raw data (YYYY-MM-DD); empty in older manifests
+	Inputs      []ManifestFile `json:"inputs"`                 // Raw data files the cache was built from
+	Outputs     []ManifestFile `json:"outputs"`                // Uncompressed cache files written
+}
+
+// ManifestFile describes a single file by name, size, and SHA-256 digest.
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// cacheFileNames lists the cache files written by store, in write order.
+var cacheFileNames = []string{"g.c.dmp", "g.co.dmp", "nameIndex.dmp"}
+
+// writeManifest hashes the raw inputs and the freshly written cache files and
+// stores the result as manifest.json in the cache directory. Missing inputs
+// (e.g., optional data sets) are omitted rather than treated as errors.
+func (g *GeoBed) writeManifest() error {
+	date := g.datasetDate
+	if date.IsZero() {
+		date = g.rawDatasetDate()
+	}
+	m := CacheManifest{DatasetDate: date.UTC().Format(time.DateOnly)}
+	for _, f := range g.dataSources() {
+		mf, err := hashManifestFile(filepath.Join(g.config.DataDir, filepath.Base(f.Path)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		m.Inputs = append(m.Inputs, mf)
+	}
+	for _, name := range cacheFileNames {
+		mf, err := hashManifestFile(filepath.Join(g.config.CacheDir, name))
+		if err != nil {
+			return err
+		}
+		m.Outputs = append(m.Outputs, mf)
+	}
+	if g.config.BigCityGrid {
+		mf, err := hashManifestFile(filepath.Join(g.config.CacheDir, bigCityFileName))
+		if err != nil {
+			return err
+		}
+		m.Outputs = append(m.Outputs, mf)
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	b = append(b, '\n')
+	return os.WriteFile(filepath.Join(g.config.CacheDir, manifestFileName), b, 0644)
+}
+
+// hashManifestFile computes the manifest entry for a single file.
+func hashManifestFile(path string) (ManifestFile, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	defer fh.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, fh)
+	if err != nil {
+		return ManifestFile{}, fmt.Errorf("hashing %s: %w", path, err)
+	}
+	return ManifestFile{
+		Name:   filepath.Base(path),
+		Size:   n,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// ReadCacheManifest reads the manifest written by RegenerateCache from dir.
+func ReadCacheManifest(dir string) (CacheManifest, error) {
+	var m CacheManifest
+	b, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, fmt.Errorf("decoding manifest: %w", err)
+	}
+	return m, nil
+}
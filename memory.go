@@ -0,0 +1,96 @@
+package geobed
+
+import (
+	"unsafe"
+
+	"github.com/golang/geo/s2"
+)
+
+// MemoryUsage is an estimate of the heap bytes held by each component of a
+// GeoBed. Sizes are computed from slice capacities, string lengths, and a
+// fixed per-entry overhead for maps, so they approximate rather than match
+// runtime.MemStats. They are intended for comparing layouts and tracking
+// dataset growth, not for precise accounting.
+type MemoryUsage struct {
+	Cities    int64 // City structs plus name and alt-name string data
+	Countries int64 // CountryInfo structs plus their string data
+	NameIndex int64 // Name index keys, map entries, posting lists, and length buckets
+	CellIndex int64 // S2 cell, coarse, population-tier, and big-city indexes
+	Interners int64 // Country/region interners (package-level, shared by all instances)
+}
+
+// Total returns the sum of all components.
+func (m MemoryUsage) Total() int64 {
+	return m.Cities + m.Countries + m.NameIndex + m.CellIndex + m.Interners
+}
+
+// mapEntryOverhead approximates the per-entry bookkeeping cost of a Go map
+// (control bytes, unused slots at typical load factor) beyond the key and
+// value themselves.
+const mapEntryOverhead = 8
+
+// MemoryUsage walks the loaded data structures and estimates their size.
+// Cost is proportional to the number of cities and index keys; avoid calling
+// it on hot paths.
+func (g *GeoBed) MemoryUsage() MemoryUsage {
+	g.ensureCellIndex()
+	var m MemoryUsage
+
+	m.Cities = int64(cap(g.Cities)) * int64(unsafe.Sizeof(GeobedCity{}))
+	for _, c := range g.Cities {
+		m.Cities += int64(len(c.City) + len(c.CityAlt))
+	}
+
+	m.Countries = int64(cap(g.Countries)) * int64(unsafe.Sizeof(CountryInfo{}))
+	for _, co := range g.Countries {
+		m.Countries += int64(len(co.Country) + len(co.Capital) + len(co.ISO) + len(co.ISO3) +
+			len(co.Fips) + len(co.Continent) + len(co.Tld) + len(co.CurrencyCode) +
+			len(co.CurrencyName) + len(co.Phone) + len(co.PostalCodeFormat) +
+			len(co.PostalCodeRegex) + len(co.Languages) + len(co.Neighbours) +
+			len(co.EquivalentFipsCode))
+	}
+	const capitalEntry = int64(unsafe.Sizeof("") + unsafe.Sizeof(GeobedCity{}) + mapEntryOverhead)
+	m.Countries += int64(len(g.capitals)) * capitalEntry
+
+	const nameEntry = int64(unsafe.Sizeof("") + unsafe.Sizeof([]int(nil)) + mapEntryOverhead)
+	for k, v := range g.nameIndex {
+		m.NameIndex += nameEntry + int64(len(k)) + int64(cap(v))*int64(unsafe.Sizeof(int(0)))
+	}
+	const countryNameEntry = int64(unsafe.Sizeof(countryNameKey{}) + unsafe.Sizeof([]int(nil)) + mapEntryOverhead)
+	for _, v := range g.byCountryName {
+		m.NameIndex += countryNameEntry + int64(cap(v))*int64(unsafe.Sizeof(int(0)))
+	}
+	for _, keys := range g.keysByLen {
+		m.NameIndex += int64(cap(keys)) * int64(unsafe.Sizeof(""))
+	}
+
+	const cellEntry = int64(unsafe.Sizeof(uint64(0)) + unsafe.Sizeof([]int(nil)) + mapEntryOverhead)
+	for _, idx := range append([]map[s2.CellID][]int{g.cellIndex, g.coarseIndex}, g.tierCellIndex[:]...) {
+		for _, v := range idx {
+			m.CellIndex += cellEntry + int64(cap(v))*int64(unsafe.Sizeof(int(0)))
+		}
+	}
+	m.CellIndex += int64(cap(g.bigCity)) * int64(unsafe.Sizeof(int32(0)))
+
+	m.Interners = countryInterner.memoryUsage() + regionInterner.memoryUsage()
+	return m
+}
+
+// memoryUsage estimates the bytes held by the interner's lookup slice and map.
+func (si *stringInterner[T]) memoryUsage() int64 {
+	if si == nil {
+		return 0
+	}
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	var zero T
+	entry := int64(unsafe.Sizeof("") + unsafe.Sizeof(zero) + mapEntryOverhead)
+	n := int64(cap(si.lookup)) * int64(unsafe.Sizeof(""))
+	for _, s := range si.lookup {
+		// Each string is referenced by both the slice and the map but its
+		// bytes are stored once.
+		n += entry + int64(len(s))
+	}
+	return n
+}
@@ -20,9 +20,56 @@ func TestMemoryFootprint(t *testing.T) {
 	var city GeobedCity
 	structSize := unsafe.Sizeof(city)
 
-	fmt.Printf("Cities loaded: %d\n", len(g.Cities))
+	fmt.Printf("Cities loaded: %d\n", g.CityCount())
 	fmt.Printf("GeobedCity size: %d bytes\n", structSize)
 	fmt.Printf("Heap in use: %d MB\n", m.Alloc/1024/1024)
-	fmt.Printf("Countries indexed: %d\n", CountryCount())
-	fmt.Printf("Regions indexed: %d\n", RegionCount())
+	stats := g.InternerStats()
+	fmt.Printf("Countries indexed: %d\n", stats.CountryCodes)
+	fmt.Printf("Regions indexed: %d\n", stats.RegionCodes)
+
+	usage := g.MemoryUsage()
+	fmt.Printf("Estimated cities: %d MB\n", usage.Cities/1024/1024)
+	fmt.Printf("Estimated countries: %d KB\n", usage.Countries/1024)
+	fmt.Printf("Estimated name index: %d MB\n", usage.NameIndex/1024/1024)
+	fmt.Printf("Estimated cell index: %d MB\n", usage.CellIndex/1024/1024)
+	fmt.Printf("Estimated interners: %d KB\n", usage.Interners/1024)
+	fmt.Printf("Estimated total: %d MB\n", usage.Total()/1024/1024)
+}
+
+func TestMemoryUsage_Components(t *testing.T) {
+	g, err := NewGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usage := g.MemoryUsage()
+	components := map[string]int64{
+		"Cities":    usage.Cities,
+		"Countries": usage.Countries,
+		"NameIndex": usage.NameIndex,
+		"CellIndex": usage.CellIndex,
+		"Interners": usage.Interners,
+	}
+	for name, n := range components {
+		if n <= 0 {
+			t.Errorf("MemoryUsage().%s = %d, want > 0", name, n)
+		}
+	}
+
+	// Struct storage alone is a hard lower bound for the cities component.
+	minCities := int64(len(g.Cities)) * int64(unsafe.Sizeof(GeobedCity{}))
+	if usage.Cities < minCities {
+		t.Errorf("MemoryUsage().Cities = %d, want >= %d", usage.Cities, minCities)
+	}
+	if usage.Total() != usage.Cities+usage.Countries+usage.NameIndex+usage.CellIndex+usage.Interners {
+		t.Error("Total() does not equal the sum of components")
+	}
+}
+
+func TestMemoryUsage_Empty(t *testing.T) {
+	g := &GeoBed{}
+	usage := g.MemoryUsage()
+	if usage.Cities != 0 || usage.NameIndex != 0 || usage.CellIndex != 0 {
+		t.Errorf("empty GeoBed MemoryUsage() = %+v, want zero instance components", usage)
+	}
 }
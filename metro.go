@@ -0,0 +1,93 @@
+package geobed
+
+import "math"
+
+// MetroOverride tunes how plain ReverseGeocode replaces a small nearest
+// place with a much larger city close by, e.g. the district "Mitte" with
+// "Berlin". Dense regions of mid-sized cities, as in much of Europe, suit a
+// smaller radius or factor than sprawling metros. Zero fields keep the
+// defaults.
+type MetroOverride struct {
+	MaxPopulation int32   // Only nearest places below this population are replaced (default 500,000)
+	Factor        int32   // The replacement needs this many times their population (default 10)
+	RadiusKm      float64 // And must lie within this distance of the point (default 10)
+
+	// Continuous replaces the thresholds above with a score balancing
+	// distance against population: every city in range scores
+	// log10(population) minus its distance in units of DecayKm, and the
+	// highest score wins. A city ten times as populous is thus preferred
+	// when it is at most DecayKm farther away, so a town a few km off beats
+	// a village next to the point, while a megacity 30 km off does not beat
+	// a sizeable town nearby. Once set with WithMetroOverride, a call cannot
+	// turn it off.
+	Continuous bool
+	DecayKm    float64 // Distance worth a tenfold population in Continuous mode (default 5)
+}
+
+// Default metro override parameters; the radius is nearbyKm.
+const (
+	defaultMetroMaxPopulation = 500_000
+	defaultMetroFactor        = 10
+	defaultMetroDecayKm       = 5
+)
+
+// WithMetroOverride sets the metro override parameters for all reverse
+// lookups. ReverseGeocodeOptions.Metro overrides them per call.
+func WithMetroOverride(m MetroOverride) Option {
+	return func(c *GeobedConfig) {
+		c.Metro = m
+	}
+}
+
+// metroParams resolves the metro override for a call: non-zero fields of
+// call win over those set with WithMetroOverride, which win over the
+// defaults.
+func (g *GeoBed) metroParams(call MetroOverride) (maxPop, factor int64, radiusKm float64) {
+	var cfg MetroOverride
+	if g.config != nil {
+		cfg = g.config.Metro
+	}
+	pick := func(call, cfg, def int32) int64 {
+		switch {
+		case call != 0:
+			return int64(call)
+		case cfg != 0:
+			return int64(cfg)
+		}
+		return int64(def)
+	}
+	maxPop = pick(call.MaxPopulation, cfg.MaxPopulation, defaultMetroMaxPopulation)
+	factor = pick(call.Factor, cfg.Factor, defaultMetroFactor)
+
+	radiusKm = nearbyKm
+	if call.RadiusKm > 0 {
+		radiusKm = call.RadiusKm
+	} else if cfg.RadiusKm > 0 {
+		radiusKm = cfg.RadiusKm
+	}
+	return maxPop, factor, radiusKm
+}
+
+// metroCurve resolves Continuous and DecayKm like metroParams. ok is false
+// when the threshold override applies.
+func (g *GeoBed) metroCurve(call MetroOverride) (decayKm float64, ok bool) {
+	var cfg MetroOverride
+	if g.config != nil {
+		cfg = g.config.Metro
+	}
+	if !call.Continuous && !cfg.Continuous {
+		return 0, false
+	}
+	km := float64(defaultMetroDecayKm)
+	if call.DecayKm > 0 {
+		km = call.DecayKm
+	} else if cfg.DecayKm > 0 {
+		km = cfg.DecayKm
+	}
+	return km, true
+}
+
+// metroScore is a candidate's score in Continuous mode.
+func metroScore(c reverseCandidate, decayKm float64) float64 {
+	return math.Log10(float64(c.city.Population)+1) - c.km/decayKm
+}
@@ -0,0 +1,106 @@
+package geobed
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetroOverride(t *testing.T) {
+	// A district 5 km from a city 20 times its size.
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Metropolis", Country: "DE", Latitude: 52.52, Longitude: 13.40, Population: 2_000_000},
+		{City: "District", Country: "DE", Latitude: 52.52, Longitude: 13.4739, Population: 100_000},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lat, lng := 52.52, 13.475 // next to District
+
+	for _, tt := range []struct {
+		name string
+		m    MetroOverride
+		want string
+	}{
+		{"defaults", MetroOverride{}, "Metropolis"},
+		{"radius too small", MetroOverride{RadiusKm: 2}, "District"},
+		{"factor too large", MetroOverride{Factor: 30}, "District"},
+		{"population cap below district", MetroOverride{MaxPopulation: 50_000}, "District"},
+		{"larger radius", MetroOverride{RadiusKm: 20, Factor: 15}, "Metropolis"},
+	} {
+		got := g.ReverseGeocode(lat, lng, ReverseGeocodeOptions{Metro: tt.m})
+		if got.City != tt.want {
+			t.Errorf("%s: ReverseGeocode() = %q, want %q", tt.name, got.City, tt.want)
+		}
+	}
+
+	// Instance-wide settings apply unless the call overrides them.
+	g.config.Metro = MetroOverride{RadiusKm: 2}
+	if got := g.ReverseGeocode(lat, lng); got.City != "District" {
+		t.Errorf("with WithMetroOverride radius 2: ReverseGeocode() = %q, want District", got.City)
+	}
+	if got := g.ReverseGeocode(lat, lng, ReverseGeocodeOptions{Metro: MetroOverride{RadiusKm: 10}}); got.City != "Metropolis" {
+		t.Errorf("call radius 10 over config radius 2: ReverseGeocode() = %q, want Metropolis", got.City)
+	}
+}
+
+func TestMetroOverride_Continuous(t *testing.T) {
+	// Cities east of (0, 0) at the given distance.
+	at := func(name string, km float64, pop int32) CityRecord {
+		return CityRecord{City: name, Country: "DE", Longitude: km / 111.195, Population: pop}
+	}
+	continuous := MetroOverride{Continuous: true}
+
+	for _, tt := range []struct {
+		name              string
+		cities            []CityRecord
+		thresholds, curve string
+	}{
+		{"town just past a village",
+			[]CityRecord{at("Village", 1, 2_000), at("Town", 3, 15_000)}, "Village", "Town"},
+		{"megacity past a large town",
+			[]CityRecord{at("Town", 1, 200_000), at("Megacity", 9, 3_000_000)}, "Megacity", "Town"},
+		{"district inside a metro",
+			[]CityRecord{at("District", 1, 100_000), at("Metropolis", 3, 2_000_000)}, "Metropolis", "Metropolis"},
+	} {
+		g, err := NewGeobedFromRecords(tt.cities, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := g.ReverseGeocode(0, 0).City; got != tt.thresholds {
+			t.Errorf("%s: ReverseGeocode() = %q, want %q", tt.name, got, tt.thresholds)
+		}
+		if got := g.ReverseGeocode(0, 0, ReverseGeocodeOptions{Metro: continuous}).City; got != tt.curve {
+			t.Errorf("%s: ReverseGeocode(Continuous) = %q, want %q", tt.name, got, tt.curve)
+		}
+		if r := g.ReverseGeocodeDetailed(0, 0, ReverseGeocodeOptions{Metro: continuous}); r.Locality.City != tt.cities[0].City {
+			t.Errorf("%s: Locality = %q, want the nearest city %q", tt.name, r.Locality.City, tt.cities[0].City)
+		}
+	}
+
+	// A short decay distance keeps the nearest place.
+	g, err := NewGeobedFromRecords([]CityRecord{at("Village", 1, 2_000), at("Town", 3, 15_000)}, nil,
+		WithMetroOverride(MetroOverride{Continuous: true, DecayKm: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.ReverseGeocode(0, 0).City; got != "Village" {
+		t.Errorf("with DecayKm 1: ReverseGeocode() = %q, want Village", got)
+	}
+}
+
+func TestReverseGeocodeDetailed_Distances(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "District", Country: "DE", Longitude: 1 / 111.195, Population: 100_000},
+		{City: "Metropolis", Country: "DE", Longitude: 4 / 111.195, Population: 2_000_000},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := g.ReverseGeocodeDetailed(0, 0)
+	if math.Abs(r.LocalityKm-1) > 0.01 || math.Abs(r.MetroKm-4) > 0.01 {
+		t.Errorf("ReverseGeocodeDetailed() = LocalityKm %.3f, MetroKm %.3f; want 1 and 4", r.LocalityKm, r.MetroKm)
+	}
+	if r := g.ReverseGeocodeDetailed(10, 10); r.LocalityKm != 0 || r.MetroKm != 0 {
+		t.Errorf("out of range: LocalityKm %v, MetroKm %v; want 0", r.LocalityKm, r.MetroKm)
+	}
+}
@@ -0,0 +1,28 @@
+package geobed
+
+// MissReason explains why GeocodeDetailed returned no city, so failures
+// can be grouped in bulk analysis.
+type MissReason string
+
+const (
+	MissNone            MissReason = ""                 // A city matched
+	MissEmptyQuery      MissReason = "empty-query"      // Nothing left of the query after preprocessing and trimming
+	MissInputTruncated  MissReason = "input-truncated"  // Nothing matched the query cut to its first 256 characters
+	MissNoIndexHit      MissReason = "no-index-hit"     // No indexed name matched the query, even with FuzzyDistance
+	MissCountryFiltered MissReason = "country-filtered" // Names matched, but none in GeocodeOptions.Country
+	MissResultFiltered  MissReason = "result-filtered"  // Every candidate was rejected by WithResultFilter
+	MissNoMatch         MissReason = "no-match"         // Candidates were found, but none was close enough to the query
+	MissDeadline        MissReason = "deadline"         // GeocodeOptions.Deadline passed before a match was found
+)
+
+// hitsElsewhere reports whether any of keys is in the name index outside
+// the country a query was scoped to, telling a country-filtered miss from
+// one with no index hit.
+func (g *GeoBed) hitsElsewhere(keys ...string) bool {
+	for _, k := range keys {
+		if len(g.nameIndex[g.nameKey(k)]) > 0 {
+			return true
+		}
+	}
+	return false
+}
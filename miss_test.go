@@ -0,0 +1,52 @@
+package geobed
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGeocodeDetailed_MissReason(t *testing.T) {
+	records := []CityRecord{
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 931830},
+		{City: "Springfield", Country: "US", Region: "MO", Latitude: 37.21533, Longitude: -93.29824, Population: 166810},
+		{City: "Springfield", Country: "US", Region: "IL", Latitude: 39.80172, Longitude: -89.64371, Population: 116250},
+	}
+	g, err := NewGeobedFromRecords(records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered, err := NewGeobedFromRecords(records, nil, WithResultFilter(func(c GeobedCity) bool { return c.City != "Austin" }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		g     *GeoBed
+		query string
+		opts  GeocodeOptions
+		want  MissReason
+	}{
+		{"match", g, "Austin", GeocodeOptions{}, MissNone},
+		{"empty", g, "  ", GeocodeOptions{}, MissEmptyQuery},
+		{"unknown name", g, "Qwxyzzy", GeocodeOptions{}, MissNoIndexHit},
+		{"other country", g, "Austin", GeocodeOptions{Country: "FR"}, MissCountryFiltered},
+		{"other country, exact", g, "Austin", GeocodeOptions{Country: "FR", ExactCity: true}, MissCountryFiltered},
+		{"result filter", filtered, "Austin", GeocodeOptions{}, MissResultFiltered},
+		{"ambiguous, exact", g, "Springfield", GeocodeOptions{ExactCity: true}, MissNoMatch},
+		{"deadline", g, "Qwxyzzy", GeocodeOptions{FuzzyDistance: 1, Deadline: time.Now().Add(-time.Second)}, MissDeadline},
+		{"truncated", g, strings.Repeat("Qwxyzzy ", 40), GeocodeOptions{}, MissInputTruncated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.g.GeocodeDetailed(tt.query, tt.opts)
+			if r.MissReason != tt.want {
+				t.Errorf("MissReason = %q (city %q), want %q", r.MissReason, r.City.City, tt.want)
+			}
+			if (r.City.City == "") != (tt.want != MissNone) {
+				t.Errorf("City = %q with MissReason %q", r.City.City, r.MissReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package geobed
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// NameIndexStats summarizes the name index used for forward geocoding.
+type NameIndexStats struct {
+	Keys        int // Distinct lowercase names (primary and alternate)
+	Postings    int // Total name → city references
+	MaxPostings int // Largest number of cities sharing one name
+}
+
+// NameIndexStats returns size statistics for the name index.
+func (g *GeoBed) NameIndexStats() NameIndexStats {
+	s := NameIndexStats{Keys: len(g.nameIndex)}
+	for _, idxs := range g.nameIndex {
+		s.Postings += len(idxs)
+		if len(idxs) > s.MaxPostings {
+			s.MaxPostings = len(idxs)
+		}
+	}
+	return s
+}
+
+// HasName reports whether name is a key of the name index, i.e. whether some
+// city has it as its primary or an indexed alternate name. Matching uses
+// the configured Normalizer, by default case-insensitive and ignoring
+// surrounding whitespace. It is a cheap way to
+// check a vocabulary against the dataset before geocoding it in bulk.
+func (g *GeoBed) HasName(name string) bool {
+	key := g.nameKey(name)
+	if key == "" {
+		return false
+	}
+	_, ok := g.nameIndex[key]
+	return ok
+}
+
+// NamesWithPrefix returns how many distinct index names start with prefix,
+// compared case-insensitively. A prefix may end in a space ("san ") to count
+// multi-word names; with WithNormalizer the prefix goes through the custom
+// normalizer instead. The first call sorts the index keys, which takes a
+// moment on the full dataset; later calls are binary searches.
+func (g *GeoBed) NamesWithPrefix(prefix string) int {
+	if g.config != nil && g.config.Normalizer != nil {
+		prefix = g.config.Normalizer.Normalize(prefix)
+	} else {
+		prefix = toLower(prefix)
+	}
+	keys := g.sortedNameKeys()
+	if prefix == "" {
+		return len(keys)
+	}
+	lo := sort.SearchStrings(keys, prefix)
+	hi := lo + sort.Search(len(keys)-lo, func(i int) bool {
+		return !strings.HasPrefix(keys[lo+i], prefix)
+	})
+	return hi - lo
+}
+
+// sortedNameKeys returns the name index keys in sorted order, built on
+// first use.
+func (g *GeoBed) sortedNameKeys() []string {
+	g.nameKeysOnce.Do(func() {
+		keys := make([]string, 0, len(g.nameIndex))
+		for k := range g.nameIndex {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		g.nameKeys = keys
+	})
+	return g.nameKeys
+}
+
+// sharedKeyStarts returns, for each of sortedNameKeys, how many leading
+// runes it shares with the key before it (capped at 255), built on first
+// use. Keys sharing a start are contiguous, so a run of entries of at least
+// n spans every key with the current key's first n runes.
+func (g *GeoBed) sharedKeyStarts() []uint8 {
+	g.keyStartsOnce.Do(func() {
+		keys := g.sortedNameKeys()
+		starts := make([]uint8, len(keys))
+		for i := 1; i < len(keys); i++ {
+			a, b, n := keys[i-1], keys[i], 0
+			for a != "" && b != "" && n < math.MaxUint8 {
+				_, w := utf8.DecodeRuneInString(a)
+				if !strings.HasPrefix(b, a[:w]) {
+					break
+				}
+				a, b, n = a[w:], b[w:], n+1
+			}
+			starts[i] = uint8(n)
+		}
+		g.keyStarts = starts
+	})
+	return g.keyStarts
+}
+
+// keysByLength returns the name index keys grouped by their length in runes,
+// built on first use. A key more than d runes longer or shorter than a token
+// is more than d edits away from it, so fuzzy scans only visit the buckets
+// within their distance of the token's length.
+func (g *GeoBed) keysByLength() [][]string {
+	g.keysByLenOnce.Do(func() {
+		var byLen [][]string
+		for k := range g.nameIndex {
+			n := utf8.RuneCountInString(toLower(k))
+			for len(byLen) <= n {
+				byLen = append(byLen, nil)
+			}
+			byLen[n] = append(byLen[n], k)
+		}
+		g.keysByLen = byLen
+	})
+	return g.keysByLen
+}
@@ -0,0 +1,109 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNameIndexLookups(t *testing.T) {
+	g := &GeoBed{nameIndex: map[string][]int{
+		"austin":     {0, 1},
+		"austintown": {2},
+		"aus":        {3},
+		"paris":      {4, 5, 6},
+	}}
+
+	for _, tt := range []struct {
+		name string
+		want bool
+	}{
+		{"Austin", true},
+		{"  PARIS ", true},
+		{"Austi", false},
+		{"", false},
+	} {
+		if got := g.HasName(tt.name); got != tt.want {
+			t.Errorf("HasName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		prefix string
+		want   int
+	}{
+		{"aus", 3},
+		{"AUSTIN", 2},
+		{"austint", 1},
+		{"p", 1},
+		{"z", 0},
+		{"", 4},
+	} {
+		if got := g.NamesWithPrefix(tt.prefix); got != tt.want {
+			t.Errorf("NamesWithPrefix(%q) = %d, want %d", tt.prefix, got, tt.want)
+		}
+	}
+
+	want := NameIndexStats{Keys: 4, Postings: 7, MaxPostings: 3}
+	if got := g.NameIndexStats(); got != want {
+		t.Errorf("NameIndexStats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNameIndexLookups_Dataset(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasName("Austin") || !g.HasName("münchen") {
+		t.Error("HasName missed a primary or alternate name")
+	}
+	if g.HasName("Zxqwvbn") {
+		t.Error("HasName(Zxqwvbn) = true")
+	}
+	if n := g.NamesWithPrefix("san "); n < 100 {
+		t.Errorf("NamesWithPrefix(san ) = %d, want >= 100", n)
+	}
+	if st := g.NameIndexStats(); st.Keys != g.NamesWithPrefix("") || st.Postings < g.CityCount() {
+		t.Errorf("NameIndexStats() = %+v inconsistent with %d keys, %d cities", st, g.NamesWithPrefix(""), g.CityCount())
+	}
+}
+
+func TestKeysByLength(t *testing.T) {
+	g := &GeoBed{nameIndex: map[string][]int{
+		"aus":    {0},
+		"paris":  {1},
+		"parís":  {2},
+		"austin": {3},
+		"köln":   {4},
+	}}
+
+	byLen := g.keysByLength()
+	want := map[int][]string{3: {"aus"}, 4: {"köln"}, 5: {"paris", "parís"}, 6: {"austin"}}
+	if len(byLen) != 7 {
+		t.Fatalf("keysByLength() has %d buckets, want 7", len(byLen))
+	}
+	for n, keys := range byLen {
+		slices.Sort(keys)
+		if !slices.Equal(keys, want[n]) {
+			t.Errorf("keysByLength()[%d] = %q, want %q", n, keys, want[n])
+		}
+	}
+}
+
+func TestKeysByLength_FuzzyGeocode(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Paris", Country: "FR", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{"Pariss", "Pari", "Austn"} {
+		if got := g.Geocode(q, GeocodeOptions{FuzzyDistance: 1}); got.City == "" {
+			t.Errorf("Geocode(%q, FuzzyDistance 1) found nothing", q)
+		}
+	}
+	if got := g.Geocode("Parisss", GeocodeOptions{FuzzyDistance: 1}); got.City != "" {
+		t.Errorf("Geocode(Parisss, FuzzyDistance 1) = %q, want no match", got.City)
+	}
+}
@@ -0,0 +1,34 @@
+package geobed
+
+import (
+	"slices"
+	"strings"
+)
+
+// NeighbourList splits Neighbours ("CH,DE,BE") into ISO country codes.
+// Island nations and other countries without land borders return nil.
+func (c CountryInfo) NeighbourList() []string {
+	var codes []string
+	for _, code := range strings.Split(c.Neighbours, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// BorderingCountries returns the ISO codes of the countries sharing a land
+// border with iso, in Geonames order, or nil for an unknown or island
+// country. The adjacency graph is parsed once at load; the returned slice is
+// a copy and may be modified.
+func (g *GeoBed) BorderingCountries(iso string) []string {
+	return slices.Clone(g.neighbours[toUpper(iso)])
+}
+
+// SharesBorder reports whether countries a and b share a land border.
+// Either listing the other is enough, so a one-sided entry in the country
+// data still counts.
+func (g *GeoBed) SharesBorder(a, b string) bool {
+	a, b = toUpper(a), toUpper(b)
+	return slices.Contains(g.neighbours[a], b) || slices.Contains(g.neighbours[b], a)
+}
@@ -0,0 +1,65 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNeighbourList(t *testing.T) {
+	tests := []struct {
+		neighbours string
+		want       []string
+	}{
+		{"CH,DE,BE", []string{"CH", "DE", "BE"}},
+		{" CA , MX ", []string{"CA", "MX"}},
+		{"ES,,FR", []string{"ES", "FR"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := (CountryInfo{Neighbours: tt.neighbours}).NeighbourList(); !slices.Equal(got, tt.want) {
+			t.Errorf("NeighbourList(%q) = %q, want %q", tt.neighbours, got, tt.want)
+		}
+	}
+}
+
+func TestCountryAdjacency(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if us := g.BorderingCountries("US"); !slices.Contains(us, "CA") || !slices.Contains(us, "MX") {
+		t.Errorf("BorderingCountries(US) = %q, want CA and MX", us)
+	}
+	if got := g.BorderingCountries("jp"); got != nil {
+		t.Errorf("BorderingCountries(JP) = %q, want none for an island nation", got)
+	}
+	if got := g.BorderingCountries("XX"); got != nil {
+		t.Errorf("BorderingCountries(XX) = %q, want nil", got)
+	}
+
+	// The result is a copy.
+	fr := g.BorderingCountries("FR")
+	fr[0] = "ZZ"
+	if g.BorderingCountries("FR")[0] == "ZZ" {
+		t.Error("BorderingCountries returned the internal slice")
+	}
+
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"FR", "CH", true},
+		{"ch", "fr", true},
+		{"DE", "PL", true},
+		{"FR", "PL", false},
+		{"JP", "KR", false},
+		{"FR", "XX", false},
+	}
+	for _, tt := range tests {
+		if got := g.SharesBorder(tt.a, tt.b); got != tt.want {
+			t.Errorf("SharesBorder(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+}
@@ -0,0 +1,48 @@
+package geobed
+
+import "strings"
+
+// Normalizer maps a name to its name-index key. It is applied to every city
+// and alternate name when the name index is built at load time, and to the
+// query text when the index is searched, so both sides always agree.
+// Implementations must be deterministic and safe for concurrent use.
+type Normalizer interface {
+	Normalize(s string) string
+}
+
+// DefaultNormalizer lowercases and trims surrounding whitespace. Custom
+// normalizers can embed it to add transliteration or domain-specific
+// cleanup on top.
+type DefaultNormalizer struct{}
+
+// Normalize implements Normalizer.
+func (DefaultNormalizer) Normalize(s string) string {
+	return toLower(strings.TrimSpace(s))
+}
+
+// WithNormalizer replaces how names are turned into name-index keys, e.g. to
+// fold diacritics or strip "APO"/"FPO" military address markers. Country
+// and state extraction and candidate scoring still see the raw query; only
+// name-index keys and lookups change. A normalizer returning "" for a name
+// leaves it unindexed.
+//
+// The cache always stores default keys, so the index is rebuilt with the
+// custom normalizer at load time, which adds a few seconds to NewGeobed.
+func WithNormalizer(n Normalizer) Option {
+	return func(c *GeobedConfig) {
+		c.Normalizer = n
+	}
+}
+
+// normalizer returns the configured normalizer.
+func (g *GeoBed) normalizer() Normalizer {
+	if g.config != nil && g.config.Normalizer != nil {
+		return g.config.Normalizer
+	}
+	return DefaultNormalizer{}
+}
+
+// nameKey returns the name-index key for s under the configured normalizer.
+func (g *GeoBed) nameKey(s string) string {
+	return g.normalizer().Normalize(s)
+}
@@ -0,0 +1,59 @@
+package geobed
+
+import (
+	"strings"
+	"testing"
+)
+
+// compactNormalizer ignores spaces, hyphens, and periods, so "Winston Salem"
+// and "Winston-Salem" share a key.
+type compactNormalizer struct{ DefaultNormalizer }
+
+func (n compactNormalizer) Normalize(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "").Replace(n.DefaultNormalizer.Normalize(s))
+}
+
+func TestDefaultNormalizer(t *testing.T) {
+	for in, want := range map[string]string{
+		"Berlin":        "berlin",
+		"  São Paulo  ": "são paulo",
+		"ZÜRICH":        "zürich",
+		"":              "",
+	} {
+		if got := (DefaultNormalizer{}).Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWithNormalizer(t *testing.T) {
+	records := []CityRecord{
+		{City: "Winston-Salem", Country: "US", Region: "NC", Latitude: 36.09986, Longitude: -80.24422, Population: 249545},
+		{City: "St. Louis", Country: "US", Region: "MO", Latitude: 38.62727, Longitude: -90.19789, Population: 315685},
+	}
+	g, err := NewGeobedFromRecords(records, nil, WithNormalizer(compactNormalizer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !g.HasName("Winston Salem") || !g.HasName("winstonsalem") {
+		t.Error("HasName should match Winston-Salem under the compact normalizer")
+	}
+	if _, ok := g.nameIndex["winston-salem"]; ok {
+		t.Error("name index still holds the unnormalized key winston-salem")
+	}
+	if got := g.Geocode("Winston Salem, NC"); got.City != "Winston-Salem" {
+		t.Errorf("Geocode(Winston Salem, NC) = %q, want Winston-Salem", got.City)
+	}
+	if n := g.NamesWithPrefix("St. Lou"); n == 0 {
+		t.Error("NamesWithPrefix(St. Lou) = 0, want St. Louis and others")
+	}
+
+	def, err := NewGeobedFromRecords(records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.HasName("winstonsalem") {
+		t.Error("default normalizer should not match winstonsalem")
+	}
+}
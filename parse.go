@@ -0,0 +1,139 @@
+package geobed
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ParsedLocation is the interpretation of a query that forward geocoding
+// works from, as returned by ParseLocation.
+type ParsedLocation struct {
+	City       []string `json:"city"`        // Name tokens left once the other parts are removed
+	Admin      string   `json:"admin"`       // Admin division code, e.g. "TX" or "08" ("" if none)
+	Country    string   `json:"country"`     // ISO 3166-1 alpha-2 country code ("" if none)
+	PostalCode string   `json:"postal_code"` // Postal code found in the query ("" if none)
+}
+
+// ParseLocation splits a query into city tokens, admin division, country,
+// and postal code, so applications can log or adjust the interpretation
+// before geocoding. The query preprocessor set by WithQueryPreprocessor is
+// applied first, and admin division and country are extracted as Geocode
+// extracts them.
+//
+// A postal code is a token containing a digit ("78701", "SW1A 1AA"); when
+// the query names a country, the code must also match that country's
+// postal code format. Geocode does not strip postal codes, so for a query
+// holding one the city tokens differ from the names Geocode matches;
+// geocoding the parsed parts without the code avoids that. ParseLocation
+// returns ErrEmptyQuery for blank queries.
+func (g *GeoBed) ParseLocation(query string) (ParsedLocation, error) {
+	n, _ := g.cleanQuery(query)
+	if n == "" {
+		return ParsedLocation{}, ErrEmptyQuery
+	}
+
+	var loc ParsedLocation
+	fields := strings.Fields(n)
+	if i := postalTokenIndex(fields); i >= 0 {
+		co, _, _, _ := g.extractLocationPieces(joinWithout(fields, i, i+1))
+		if end := g.postalCodeEnd(fields, i, co); end > i {
+			loc.PostalCode = toUpper(strings.TrimRight(strings.Join(fields[i:end], " "), ","))
+			n = joinWithout(fields, i, end)
+		}
+	}
+
+	nCo, nSt, _, nSlice := g.extractLocationPieces(n)
+	loc.Country, loc.Admin = nCo, nSt
+	for _, s := range nSlice {
+		if s = strings.Trim(s, ","); s != "" {
+			loc.City = append(loc.City, s)
+		}
+	}
+	return loc, nil
+}
+
+// postalTokenIndex returns the index of the first field that may start a
+// postal code: 3-10 letters, digits, or hyphens including at least one
+// digit. It returns -1 if there is none.
+func postalTokenIndex(fields []string) int {
+	for i, f := range fields {
+		if f = strings.TrimRight(f, ","); len(f) >= 3 && len(f) <= 10 && postalChars(f) &&
+			strings.ContainsFunc(f, unicode.IsDigit) {
+			return i
+		}
+	}
+	return -1
+}
+
+// postalChars reports whether s consists of ASCII letters, digits, and
+// hyphens only.
+func postalChars(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// postalCodeEnd returns the end of the postal code starting at fields[i]:
+// i+2 when the next short field completes it ("SW1A 1AA", "1012 AB"), i+1
+// when fields[i] is the whole code, and i when the text does not match the
+// postal code format of country. Without a known format a second field is
+// only taken if it contains a digit.
+func (g *GeoBed) postalCodeEnd(fields []string, i int, country string) int {
+	one := strings.TrimRight(fields[i], ",")
+	two, next := "", ""
+	if i+1 < len(fields) && !strings.HasSuffix(fields[i], ",") {
+		if next = strings.TrimRight(fields[i+1], ","); len(next) <= 4 && postalChars(next) {
+			two = one + " " + next
+		}
+	}
+
+	if re := g.postalCodeRegex(country); re != nil {
+		switch {
+		case two != "" && re.MatchString(toUpper(two)):
+			return i + 2
+		case re.MatchString(toUpper(one)):
+			return i + 1
+		}
+		return i
+	}
+	if two != "" && strings.ContainsFunc(next, unicode.IsDigit) {
+		return i + 2
+	}
+	return i + 1
+}
+
+// postalCodeRegex returns the compiled Geonames postal code pattern of
+// country. It returns nil for unknown countries, countries without postal
+// codes, and patterns that do not compile. The patterns of all countries
+// are compiled together on first use.
+func (g *GeoBed) postalCodeRegex(country string) *regexp.Regexp {
+	g.postalOnce.Do(func() {
+		g.postalRegex = make(map[string]*regexp.Regexp)
+		for _, info := range g.Countries {
+			if info.PostalCodeRegex == "" {
+				continue
+			}
+			if re, err := regexp.Compile(info.PostalCodeRegex); err == nil {
+				g.postalRegex[info.ISO] = re
+			}
+		}
+	})
+	return g.postalRegex[country]
+}
+
+// joinWithout joins fields with spaces, leaving out fields[start:end]. A
+// comma that ended the removed run moves to the field before it, so
+// "Berlin 10115, Germany" becomes "Berlin, Germany".
+func joinWithout(fields []string, start, end int) string {
+	kept := make([]string, 0, len(fields)-(end-start))
+	kept = append(kept, fields[:start]...)
+	if start > 0 && strings.HasSuffix(fields[end-1], ",") && !strings.HasSuffix(kept[start-1], ",") {
+		kept[start-1] += ","
+	}
+	kept = append(kept, fields[end:]...)
+	return strings.Join(kept, " ")
+}
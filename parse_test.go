@@ -0,0 +1,74 @@
+package geobed
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  ParsedLocation
+	}{
+		{"Austin, TX", ParsedLocation{City: []string{"Austin"}, Admin: "TX", Country: "US"}},
+		{"Austin, TX 78701", ParsedLocation{City: []string{"Austin"}, Admin: "TX", Country: "US", PostalCode: "78701"}},
+		{"Berlin 10115, Germany", ParsedLocation{City: []string{"Berlin"}, Country: "DE", PostalCode: "10115"}},
+		{"London SW1A 1AA, United Kingdom", ParsedLocation{City: []string{"London"}, Country: "GB", PostalCode: "SW1A 1AA"}},
+		{"1012 AB Amsterdam, NL", ParsedLocation{City: []string{"Amsterdam"}, Country: "NL", PostalCode: "1012 AB"}},
+		{"Toronto, Ontario", ParsedLocation{City: []string{"Toronto"}, Admin: "08", Country: "CA"}},
+		{"San Francisco", ParsedLocation{City: []string{"San", "Francisco"}}},
+		{"Paris 123, France", ParsedLocation{City: []string{"Paris", "123"}, Country: "FR"}}, // Not a French postal code
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := g.ParseLocation(tt.query)
+			if err != nil {
+				t.Fatalf("ParseLocation(%q) error: %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLocation(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := g.ParseLocation("   "); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("ParseLocation(blank) error = %v, want ErrEmptyQuery", err)
+	}
+}
+
+func TestParseLocation_Preprocessor(t *testing.T) {
+	g, err := NewGeobedFromRecords(fixtureCities, nil, WithQueryPreprocessor(func(q string) string {
+		return strings.ReplaceAll(q, ";", ",")
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := g.ParseLocation("Austin; TX")
+	if err != nil || got.Admin != "TX" || got.Country != "US" {
+		t.Errorf("ParseLocation(Austin; TX) = %+v, %v; want TX, US", got, err)
+	}
+}
+
+func TestJoinWithout(t *testing.T) {
+	tests := []struct {
+		fields     []string
+		start, end int
+		want       string
+	}{
+		{[]string{"Austin,", "TX", "78701"}, 2, 3, "Austin, TX"},
+		{[]string{"Berlin", "10115,", "Germany"}, 1, 2, "Berlin, Germany"},
+		{[]string{"1012", "AB", "Amsterdam"}, 0, 2, "Amsterdam"},
+	}
+	for _, tt := range tests {
+		if got := joinWithout(tt.fields, tt.start, tt.end); got != tt.want {
+			t.Errorf("joinWithout(%q, %d, %d) = %q, want %q", tt.fields, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
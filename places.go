@@ -0,0 +1,212 @@
+package geobed
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithPlacesFile merges a supplemental dataset of places such as
+// neighborhoods and boroughs into the loaded cities. Places take part in
+// forward geocoding, so "Williamsburg, NY" can resolve to the neighborhood,
+// but are excluded from reverse geocoding unless WithReversePlaces is set,
+// so coordinates inside Williamsburg still reverse to "New York City".
+//
+// The file is tab-separated, one place per line; blank lines and lines
+// starting with '#' are ignored:
+//
+//	name  alternate names (comma-separated)  country  region  latitude  longitude  [population  [geoname ID]]
+//
+// The optional Geonames ID keeps GeobedCity.ID stable for places taken
+// from Geonames; without it the ID is derived from name and coordinates.
+// Places are merged on every NewGeobed call and never written to the cache.
+// WritePlaces writes files in this format.
+func WithPlacesFile(path string) Option {
+	return func(c *GeobedConfig) {
+		c.PlacesFile = path
+	}
+}
+
+// WithPlaces merges the given places like those of a places file, e.g.
+// ones an operator added at run time. Places returns them (together with
+// those from the file) and WritePlaces saves them as a places file, so they
+// can be reloaded with WithPlacesFile after a restart.
+func WithPlaces(places ...CityRecord) Option {
+	return func(c *GeobedConfig) {
+		c.Places = append(c.Places, places...)
+	}
+}
+
+// WithReversePlaces lets ReverseGeocode return places loaded with
+// WithPlacesFile or WithPlaces.
+func WithReversePlaces() Option {
+	return func(c *GeobedConfig) {
+		c.ReversePlaces = true
+	}
+}
+
+// IsPlace reports whether the city is a supplemental place loaded with
+// WithPlacesFile or WithPlaces rather than part of the base dataset.
+func (c GeobedCity) IsPlace() bool {
+	return c.source == SourceCustom
+}
+
+// Places returns the supplemental places g has loaded, from both
+// WithPlacesFile and WithPlaces, in name order.
+func (g *GeoBed) Places() []CityRecord {
+	var places []CityRecord
+	for _, c := range g.Cities {
+		if c.source != SourceCustom {
+			continue
+		}
+		places = append(places, c.Record())
+	}
+	return places
+}
+
+// WritePlaces writes g's supplemental places to w in the places file
+// format, as an overlay to load with WithPlacesFile on the next start.
+func (g *GeoBed) WritePlaces(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# name\talt\tcountry\tregion\tlat\tlng\tpopulation\tgeonameid\n")
+	for _, p := range g.Places() {
+		for _, field := range []string{p.City, p.CityAlt, p.Country, p.Region} {
+			if strings.ContainsAny(field, "\t\r\n") {
+				return fmt.Errorf("place %q: field %q contains a tab or line break", p.City, field)
+			}
+		}
+		fmt.Fprintf(bw, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\n", p.City, p.CityAlt, p.Country, p.Region,
+			strconv.FormatFloat(p.Latitude, 'f', -1, 32), strconv.FormatFloat(p.Longitude, 'f', -1, 32), p.Population, p.GeonameID)
+	}
+	return bw.Flush()
+}
+
+// placesFileMinFields is the number of required columns in a places file.
+const placesFileMinFields = 6
+
+// parsePlaceLine parses one line of a places file.
+func parsePlaceLine(line string) (geobedCityGob, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < placesFileMinFields {
+		return geobedCityGob{}, fmt.Errorf("want at least %d tab-separated fields, got %d", placesFileMinFields, len(fields))
+	}
+	name := strings.TrimSpace(fields[0])
+	if name == "" {
+		return geobedCityGob{}, fmt.Errorf("empty name")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(fields[4]), 32)
+	if err != nil {
+		return geobedCityGob{}, fmt.Errorf("latitude: %w", err)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(fields[5]), 32)
+	if err != nil {
+		return geobedCityGob{}, fmt.Errorf("longitude: %w", err)
+	}
+	if !validCoordinates(lat, lng) {
+		return geobedCityGob{}, fmt.Errorf("coordinates out of range: %v, %v", lat, lng)
+	}
+	var pop, id int64
+	if len(fields) > placesFileMinFields && strings.TrimSpace(fields[6]) != "" {
+		pop, err = strconv.ParseInt(strings.TrimSpace(fields[6]), 10, 32)
+		if err != nil {
+			return geobedCityGob{}, fmt.Errorf("population: %w", err)
+		}
+	}
+	if len(fields) > placesFileMinFields+1 && strings.TrimSpace(fields[7]) != "" {
+		id, err = strconv.ParseInt(strings.TrimSpace(fields[7]), 10, 32)
+		if err != nil {
+			return geobedCityGob{}, fmt.Errorf("geoname ID: %w", err)
+		}
+	}
+	return geobedCityGob{
+		City:       name,
+		CityAlt:    strings.TrimSpace(fields[1]),
+		Country:    toUpper(strings.TrimSpace(fields[2])),
+		Region:     strings.TrimSpace(fields[3]),
+		Latitude:   float32(lat),
+		Longitude:  float32(lng),
+		Population: int32(pop),
+		GeonameID:  int32(id),
+	}, nil
+}
+
+// readPlacesFile reads the places in a places file.
+func readPlacesFile(path string) (Cities, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var places Cities
+	sc := bufio.NewScanner(fh)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p, err := parsePlaceLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		p.Source = SourceCustom
+		c, err := p.toCity()
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		places = append(places, c)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return places, nil
+}
+
+// mergePlaces merges places into the name-sorted Cities slice and updates
+// the name index in place. Existing posting lists are remapped rather than
+// rebuilt, so the index loaded from the cache is preserved exactly.
+func (g *GeoBed) mergePlaces(places Cities) {
+	if len(places) == 0 {
+		return
+	}
+	sort.SliceStable(places, func(i, j int) bool {
+		return compareCities(places[i], places[j]) < 0
+	})
+
+	merged := make(Cities, 0, len(g.Cities)+len(places))
+	newPos := make([]int, len(g.Cities)) // old city index → merged index
+	placePos := make([]int, len(places))
+	i, j := 0, 0
+	for i < len(g.Cities) || j < len(places) {
+		if j == len(places) || (i < len(g.Cities) && compareCities(g.Cities[i], places[j]) <= 0) {
+			newPos[i] = len(merged)
+			merged = append(merged, g.Cities[i])
+			i++
+		} else {
+			placePos[j] = len(merged)
+			merged = append(merged, places[j])
+			j++
+		}
+	}
+	g.Cities = merged
+
+	for _, idxs := range g.nameIndex {
+		for k, old := range idxs {
+			idxs[k] = newPos[old]
+		}
+	}
+	for j, p := range places {
+		g.indexCityNames(placePos[j], p, g.normalizer())
+	}
+	// Place indices were appended after the remapped ones; restore order.
+	for _, p := range places {
+		keys, _ := g.cityNameKeys(p, g.normalizer())
+		for _, key := range keys {
+			sort.Ints(g.nameIndex[key])
+		}
+	}
+}
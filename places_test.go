@@ -0,0 +1,128 @@
+package geobed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPlaces = `# name	alt	country	region	lat	lng	population
+Williamsburg	Billyburg	US	NY	40.7081	-73.9571	151308
+
+Zzyzx Heights		us	CA	35.1433	-116.1044
+`
+
+func writePlacesFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "places.tsv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// placesBase is the base dataset the test places are merged into.
+var placesBase = []CityRecord{
+	{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	{City: "Paris", Country: "FR", Region: "11", Latitude: 48.85341, Longitude: 2.3488, Population: 2138551},
+	{City: "Brooklyn", Country: "US", Region: "NY", Latitude: 40.6501, Longitude: -73.94958, Population: 2736074},
+	{City: "Williamsburg", Country: "US", Region: "VA", Latitude: 37.27070, Longitude: -76.70746, Population: 15052},
+}
+
+func TestPlacesFile(t *testing.T) {
+	path := writePlacesFile(t, testPlaces)
+	g, err := NewGeobedFromRecords(placesBase, nil, WithPlacesFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkCitiesSorted(g.Cities); err != nil {
+		t.Fatalf("merged cities unsorted: %v", err)
+	}
+
+	for _, q := range []string{"Williamsburg, NY", "Billyburg", "Zzyzx Heights"} {
+		c := g.Geocode(q)
+		if !c.IsPlace() {
+			t.Errorf("Geocode(%q) = %s, %s; want the supplemental place", q, c.City, c.Region())
+		}
+	}
+	if c := g.Geocode("Zzyzx Heights"); c.Country() != "US" || c.Population != 0 {
+		t.Errorf("Zzyzx Heights = %s pop %d, want US pop 0", c.Country(), c.Population)
+	}
+
+	// Every posting must still point at a city carrying that name.
+	for _, q := range []string{"austin", "paris", "williamsburg"} {
+		for _, i := range g.nameIndex[q] {
+			if !strings.EqualFold(g.Cities[i].City, q) && !strings.Contains(toLower(g.Cities[i].CityAlt), q) {
+				t.Errorf("nameIndex[%q] points at %q after merge", q, g.Cities[i].City)
+			}
+		}
+	}
+	if c := g.Geocode("Austin, TX"); c.City != "Austin" || c.Region() != "TX" {
+		t.Errorf("Geocode(Austin, TX) = %s, %s after merge", c.City, c.Region())
+	}
+
+	if c := g.ReverseGeocode(40.7081, -73.9571); c.IsPlace() || c.City == "" {
+		t.Errorf("ReverseGeocode inside Williamsburg = %q (place=%v), want a base city", c.City, c.IsPlace())
+	}
+
+	gr, err := NewGeobedFromRecords(placesBase, nil, WithPlacesFile(path), WithReversePlaces())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := gr.ReverseGeocode(35.1433, -116.1044); c.City != "Zzyzx Heights" {
+		t.Errorf("ReverseGeocode with WithReversePlaces = %q, want Zzyzx Heights", c.City)
+	}
+}
+
+func TestPlacesFile_Errors(t *testing.T) {
+	for name, content := range map[string]string{
+		"too few fields": "Foo\t\tUS\tNY\t1\n",
+		"bad latitude":   "Foo\t\tUS\tNY\tx\t1\n",
+		"out of range":   "Foo\t\tUS\tNY\t91\t1\n",
+		"bad population": "Foo\t\tUS\tNY\t1\t1\tmany\n",
+		"empty name":     "\t\tUS\tNY\t1\t1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := readPlacesFile(writePlacesFile(t, content))
+			if err == nil || !strings.Contains(err.Error(), ":1:") {
+				t.Errorf("readPlacesFile error = %v, want error at line 1", err)
+			}
+		})
+	}
+
+	if _, err := NewGeobedFromRecords(placesBase, nil, WithPlacesFile(filepath.Join(t.TempDir(), "missing.tsv"))); err == nil {
+		t.Error("NewGeobedFromRecords with missing places file succeeded")
+	}
+}
+
+func TestWritePlaces_RoundTrip(t *testing.T) {
+	base := []CityRecord{{City: "New York City", Country: "US", Region: "NY", Latitude: 40.71427, Longitude: -74.00597, Population: 8175133}}
+	added := CityRecord{City: "Williamsburg", CityAlt: "Billyburg", Country: "US", Region: "NY", Latitude: 40.7081, Longitude: -73.9571, Population: 151308, GeonameID: 5143858}
+	g, err := NewGeobedFromRecords(base, nil, WithPlaces(added))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := g.Geocode("Billyburg"); c.City != "Williamsburg" || !c.IsPlace() {
+		t.Fatalf("Geocode(Billyburg) = %q (place %v), want the added place", c.City, c.IsPlace())
+	}
+
+	var overlay strings.Builder
+	if err := g.WritePlaces(&overlay); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewGeobedFromRecords(base, nil, WithPlacesFile(writePlacesFile(t, overlay.String())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Places(); len(got) != 1 || got[0] != g.Places()[0] {
+		t.Errorf("Places() after reloading the overlay = %+v, want %+v", got, g.Places())
+	}
+	if got := restored.Geocode("Billyburg").ID(); got != "5143858" {
+		t.Errorf("ID() after reloading the overlay = %q, want the Geonames ID", got)
+	}
+
+	if _, err := NewGeobedFromRecords(base, nil, WithPlaces(CityRecord{City: "Nowhere", Latitude: 91})); err == nil {
+		t.Error("WithPlaces with invalid coordinates succeeded")
+	}
+}
@@ -0,0 +1,134 @@
+package geobed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// InitSource names where NewGeobed loads its data from.
+type InitSource string
+
+const (
+	InitFromWarmStart InitSource = "warm-start" // Snapshot in the warm-start directory (WithWarmStart)
+	InitFromCache     InitSource = "cache"      // Cache files on disk, or the embedded cache
+	InitFromRawData   InitSource = "raw-data"   // Raw Geonames files in the data directory, downloaded first if missing
+)
+
+// InitPlan describes what NewGeobed would do with a configuration.
+type InitPlan struct {
+	Source         InitSource        `json:"source"`
+	CacheDir       string            `json:"cache_dir,omitempty"`       // On-disk cache read for InitFromCache; empty for the embedded cache
+	WarmStartPath  string            `json:"warm_start_path,omitempty"` // Snapshot read or, with WriteWarmStart, written
+	WriteWarmStart bool              `json:"write_warm_start"`          // A missing snapshot is written after loading the cache
+	Downloads      []PlannedDownload `json:"downloads,omitempty"`       // Raw files missing from the data directory
+	DownloadBytes  int64             `json:"download_bytes"`            // Total size of Downloads whose size is known
+	StoreCache     bool              `json:"store_cache"`               // Parsed raw data is stored in CacheDir; false if it is read-only
+}
+
+// PlannedDownload is a raw data file NewGeobed would download.
+type PlannedDownload struct {
+	ID   DataSourceID `json:"id"`
+	URL  string       `json:"url"`
+	Path string       `json:"path"` // Destination in the data directory
+	Size int64        `json:"size"` // Content length reported by the server, -1 if unknown
+}
+
+// PlanInit reports what NewGeobed would do with opts, without loading any
+// data or writing any file other than a probe of whether the cache
+// directory is writable, so deployment tooling can verify a configuration
+// before rollout. It fails like NewGeobed for invalid options and, with
+// WithOffline, for raw files missing from the data directory. Sizes of
+// files to download are asked from the server with HEAD requests.
+//
+// The plan only checks which files exist: a cache or warm-start snapshot
+// that turns out to be corrupt makes NewGeobed fall back to the next
+// source.
+func PlanInit(opts ...Option) (*InitPlan, error) {
+	g, err := newGeobed(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &InitPlan{}
+	if g.embeddedData() {
+		p.WarmStartPath = g.warmStartPath()
+		if p.WarmStartPath != "" {
+			if _, err := os.Stat(p.WarmStartPath); err == nil {
+				p.Source = InitFromWarmStart
+				return p, nil
+			}
+		}
+		if dir, ok := cacheLocation(g.config.CacheDir); ok {
+			p.Source = InitFromCache
+			p.CacheDir = dir
+			p.WriteWarmStart = p.WarmStartPath != ""
+			return p, nil
+		}
+		p.WarmStartPath = ""
+	}
+
+	p.Source = InitFromRawData
+	p.StoreCache = g.embeddedData() && !cacheDirReadOnly(g.config.CacheDir)
+	for _, f := range g.dataSources() {
+		path := filepath.Join(g.config.DataDir, filepath.Base(f.Path))
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if g.config.Offline {
+			return nil, fmt.Errorf("%s missing from %s: %w: offline mode", f.ID, g.config.DataDir, ErrDownloadFailed)
+		}
+		d := PlannedDownload{ID: f.ID, URL: f.URL, Path: path, Size: g.remoteSize(f.URL)}
+		if d.Size > 0 {
+			p.DownloadBytes += d.Size
+		}
+		p.Downloads = append(p.Downloads, d)
+	}
+	return p, nil
+}
+
+// cacheLocation reports whether all cache files can be opened from dir or
+// the embedded cache and, if any of them is read from disk rather than the
+// embedded copy, the absolute path of dir.
+func cacheLocation(dir string) (string, bool) {
+	onDisk := false
+	for _, name := range cacheFileNames {
+		fh, _, err := openRawCacheFile(dir, name)
+		if err != nil {
+			return "", false
+		}
+		if _, ok := fh.(*os.File); ok {
+			onDisk = true
+		}
+		fh.Close()
+	}
+	if !onDisk {
+		return "", true
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return dir, true
+}
+
+// remoteSize returns the content length the server reports for url, or -1
+// if the request fails or the length is unknown.
+func (g *GeoBed) remoteSize(url string) int64 {
+	ctx, cancel := context.WithTimeout(context.Background(), g.downloadTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return -1
+	}
+	resp, err := g.downloadClient().Do(req)
+	if err != nil {
+		return -1
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return -1
+	}
+	return resp.ContentLength
+}
@@ -0,0 +1,74 @@
+package geobed
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlanInit_Cache(t *testing.T) {
+	t.Chdir(t.TempDir())
+	warm := t.TempDir()
+
+	p, err := PlanInit(WithWarmStart(warm), WithDataDir(t.TempDir()), WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Source != InitFromCache || p.CacheDir != "" || !p.WriteWarmStart || len(p.Downloads) != 0 {
+		t.Errorf("plan = %+v, want the embedded cache and a new warm-start snapshot", p)
+	}
+
+	if err := os.WriteFile(p.WarmStartPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if p, err = PlanInit(WithWarmStart(warm)); err != nil || p.Source != InitFromWarmStart {
+		t.Errorf("PlanInit() with a snapshot = %+v, %v; want %s", p, err, InitFromWarmStart)
+	}
+}
+
+func TestPlanInit_Downloads(t *testing.T) {
+	archive := t.TempDir()
+	snapshot := filepath.Join(archive, "2024-06-01")
+	if err := os.Mkdir(snapshot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	copyMetadataFiles(t, snapshot)
+	writeCitiesZip(t, filepath.Join(snapshot, "cities1000.zip"), testCityRows)
+	srv := httptest.NewServer(http.FileServer(http.Dir(archive)))
+	defer srv.Close()
+
+	dataDir := t.TempDir()
+	p, err := PlanInit(WithDatasetDate("2024-06-01"), WithArchiveURL(srv.URL+"/{date}/{file}"), WithDataDir(dataDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Source != InitFromRawData || p.StoreCache || len(p.Downloads) != len(dataSetFiles) {
+		t.Fatalf("plan = %+v, want %d downloads of raw data", p, len(dataSetFiles))
+	}
+	var total int64
+	for _, d := range p.Downloads {
+		fi, err := os.Stat(filepath.Join(snapshot, filepath.Base(d.Path)))
+		if err != nil || d.Size != fi.Size() {
+			t.Errorf("download %s size = %d, want %d (%v)", d.ID, d.Size, fi.Size(), err)
+		}
+		total += d.Size
+	}
+	if p.DownloadBytes != total {
+		t.Errorf("DownloadBytes = %d, want %d", p.DownloadBytes, total)
+	}
+	if entries, _ := os.ReadDir(dataDir); len(entries) != 0 {
+		t.Errorf("PlanInit wrote %d entries to the data directory", len(entries))
+	}
+}
+
+func TestPlanInit_Errors(t *testing.T) {
+	if _, err := PlanInit(WithDataset(DatasetCities15000), WithOffline(), WithDataDir(t.TempDir())); !errors.Is(err, ErrDownloadFailed) {
+		t.Errorf("PlanInit() offline without raw files error = %v, want ErrDownloadFailed", err)
+	}
+	if _, err := PlanInit(WithDataset("cities42")); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("PlanInit() with an unknown dataset error = %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,41 @@
+package geobed
+
+import "github.com/golang/geo/s2"
+
+// popTiers are the population thresholds that get their own cell index,
+// ascending. A population-filtered lookup uses the highest tier at or below
+// its threshold, so it only sees cities that can possibly qualify.
+var popTiers = [...]int32{10_000, 100_000, 1_000_000}
+
+// buildTierIndexes builds the per-tier cell indexes from cellIndex. Each
+// tier holds the subset of cellIndex postings whose city reaches the tier's
+// population, in the same order.
+func (g *GeoBed) buildTierIndexes() {
+	for t, minPop := range popTiers {
+		idx := make(map[s2.CellID][]int)
+		for cell, postings := range g.cellIndex {
+			for _, i := range postings {
+				if g.Cities[i].Population >= minPop {
+					idx[cell] = append(idx[cell], i)
+				}
+			}
+		}
+		g.tierCellIndex[t] = idx
+	}
+}
+
+// cellIndexFor returns the cell index to search for cities with a
+// population of at least minPop, and whether its postings still need to be
+// filtered because minPop falls between tiers.
+func (g *GeoBed) cellIndexFor(minPop int32) (map[s2.CellID][]int, bool) {
+	g.ensureCellIndex()
+	if minPop <= 0 {
+		return g.cellIndex, false
+	}
+	for t := len(popTiers) - 1; t >= 0; t-- {
+		if popTiers[t] <= minPop && g.tierCellIndex[t] != nil {
+			return g.tierCellIndex[t], popTiers[t] < minPop
+		}
+	}
+	return g.cellIndex, true
+}
@@ -0,0 +1,103 @@
+package geobed
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestTierIndexes(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ti, minPop := range popTiers {
+		idx := g.tierCellIndex[ti]
+		if len(idx) == 0 {
+			t.Fatalf("tier %d (>= %d) is empty", ti, minPop)
+		}
+		want := 0
+		for _, postings := range g.cellIndex {
+			for _, i := range postings {
+				if g.Cities[i].Population >= minPop {
+					want++
+				}
+			}
+		}
+		got := 0
+		for _, postings := range idx {
+			for _, i := range postings {
+				if g.Cities[i].Population < minPop {
+					t.Fatalf("tier %d holds %s with population %d", ti, g.Cities[i].City, g.Cities[i].Population)
+				}
+				got++
+			}
+		}
+		if got != want {
+			t.Errorf("tier %d (>= %d) has %d postings, want %d", ti, minPop, got, want)
+		}
+	}
+}
+
+func TestCellIndexFor(t *testing.T) {
+	// Each index holds a single marker posting naming its tier.
+	g := &GeoBed{cellIndex: map[s2.CellID][]int{1: {-1}}}
+	for i := range popTiers {
+		g.tierCellIndex[i] = map[s2.CellID][]int{1: {i}}
+	}
+
+	tests := []struct {
+		minPop     int32
+		wantTier   int // -1 = full cell index
+		wantFilter bool
+	}{
+		{0, -1, false},
+		{5_000, -1, true},
+		{10_000, 0, false},
+		{50_000, 0, true},
+		{100_000, 1, false},
+		{1_000_000, 2, false},
+		{5_000_000, 2, true},
+	}
+	for _, tt := range tests {
+		idx, filter := g.cellIndexFor(tt.minPop)
+		if tier := idx[1][0]; tier != tt.wantTier || filter != tt.wantFilter {
+			t.Errorf("cellIndexFor(%d) = tier %d, filter=%v; want tier %d, filter=%v",
+				tt.minPop, tier, filter, tt.wantTier, tt.wantFilter)
+		}
+	}
+}
+
+func TestReverseGeocode_MinPopulation(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mitte, Berlin: the nearest place is a district, any threshold above it
+	// lands on Berlin.
+	if got := g.ReverseGeocode(52.5200, 13.4050, ReverseGeocodeOptions{IncludeNeighborhoods: true, MinPopulation: 1_000_000}); got.City != "Berlin" {
+		t.Errorf("ReverseGeocode(Mitte, MinPopulation=1M) = %q, want Berlin", got.City)
+	}
+
+	// Results agree with filtering the full cell index, on and between tiers.
+	rng := rand.New(rand.NewSource(1))
+	for _, minPop := range []int32{10_000, 50_000, 100_000, 2_000_000} {
+		for range 200 {
+			c := g.Cities[rng.Intn(len(g.Cities))]
+			lat, lng := float64(c.Latitude)+rng.Float64()*0.2-0.1, float64(c.Longitude)+rng.Float64()*0.2-0.1
+			got := g.ReverseGeocode(lat, lng, ReverseGeocodeOptions{IncludeNeighborhoods: true, MinPopulation: minPop})
+			if got.City != "" && got.Population < minPop {
+				t.Fatalf("ReverseGeocode(%v, %v, MinPopulation=%d) = %s with population %d", lat, lng, minPop, got.City, got.Population)
+			}
+
+			full := &GeoBed{Cities: g.Cities, cellIndex: g.cellIndex}
+			want := full.ReverseGeocode(lat, lng, ReverseGeocodeOptions{IncludeNeighborhoods: true, MinPopulation: minPop})
+			if got != want {
+				t.Fatalf("ReverseGeocode(%v, %v, MinPopulation=%d) = %s, full scan %s", lat, lng, minPop, got.City, want.City)
+			}
+		}
+	}
+}
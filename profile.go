@@ -0,0 +1,72 @@
+package geobed
+
+// InitProfile selects how NewGeobed builds the reverse-geocoding cell
+// indexes. Loading the cache files takes the same time and memory in every
+// profile and dominates start-up, so the profiles differ by little. Measured
+// on the embedded dataset (165K cities) on a single-core linux/amd64 VM:
+//
+//	Profile    NewGeobed  Cell indexes  First reverse lookup
+//	Balanced   ~4.5s      ~10MB         ~50µs
+//	FastStart  ~4.4s      ~10MB         ~150ms (builds the cell indexes)
+//	LowMemory  ~4.5s      ~8MB          ~60µs
+//
+// There is no compact name index: the name index is a hash map in every
+// profile. For repeated starts, WithWarmStart saves far more time than any
+// profile.
+type InitProfile int
+
+const (
+	// Balanced builds all reverse-geocoding indexes in NewGeobed.
+	Balanced InitProfile = iota
+
+	// FastStart defers the reverse-geocoding cell indexes to the first
+	// lookup that needs them, which pays their build cost instead.
+	FastStart
+
+	// LowMemory skips the population-tier cell indexes behind
+	// ReverseGeocodeOptions.MinPopulation, which then filters the full cell
+	// index instead.
+	LowMemory
+)
+
+// String returns the profile name, e.g. "balanced".
+func (p InitProfile) String() string {
+	switch p {
+	case FastStart:
+		return "fast-start"
+	case LowMemory:
+		return "low-memory"
+	default:
+		return "balanced"
+	}
+}
+
+// WithInitProfile selects how NewGeobed builds the reverse-geocoding cell
+// indexes. The default is Balanced.
+func WithInitProfile(p InitProfile) Option {
+	return func(c *GeobedConfig) {
+		c.InitProfile = p
+	}
+}
+
+// loadCache decodes the cache files into Cities, Countries, and the name
+// index.
+func (g *GeoBed) loadCache() error {
+	var err error
+	g.Cities, err = loadGeobedCityData(g.config.CacheDir)
+	if err == nil {
+		g.Countries, err = loadGeobedCountryData(g.config.CacheDir)
+	}
+	if err == nil {
+		g.nameIndex, err = loadNameIndex(g.config.CacheDir)
+	}
+	return err
+}
+
+// ensureCellIndex builds the cell index on first use when FastStart
+// deferred it. Safe for concurrent use.
+func (g *GeoBed) ensureCellIndex() {
+	if g.config != nil && g.config.InitProfile == FastStart {
+		g.cellOnce.Do(g.buildCellIndex)
+	}
+}
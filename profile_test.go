@@ -0,0 +1,69 @@
+package geobed
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestInitProfiles(t *testing.T) {
+	base, err := NewGeobedFromRecords(fixtureCities, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := base.ReverseGeocode(30.26, -97.74, ReverseGeocodeOptions{MinPopulation: 500_000})
+
+	t.Run("fast start", func(t *testing.T) {
+		g, err := NewGeobedFromRecords(fixtureCities, nil, WithInitProfile(FastStart))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.cellIndex != nil {
+			t.Error("cell index built during NewGeobed")
+		}
+		if len(g.Cities) != len(base.Cities) || len(g.nameIndex) != len(base.nameIndex) {
+			t.Errorf("loaded %d cities, %d keys; want %d, %d", len(g.Cities), len(g.nameIndex), len(base.Cities), len(base.nameIndex))
+		}
+
+		// The first lookups race to build the index.
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if got := g.ReverseGeocode(30.26, -97.74, ReverseGeocodeOptions{MinPopulation: 500_000}); got != want {
+					t.Errorf("ReverseGeocode = %q, want %q", got.City, want.City)
+				}
+			}()
+		}
+		wg.Wait()
+		if err := g.HealthCheck(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("low memory", func(t *testing.T) {
+		g, err := NewGeobedFromRecords(fixtureCities, nil, WithInitProfile(LowMemory))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for tier, idx := range g.tierCellIndex {
+			if idx != nil {
+				t.Errorf("tier %d index built", tier)
+			}
+		}
+		if got := g.ReverseGeocode(30.26, -97.74, ReverseGeocodeOptions{MinPopulation: 500_000}); got != want {
+			t.Errorf("ReverseGeocode = %q, want %q", got.City, want.City)
+		}
+		if g.MemoryUsage().CellIndex >= base.MemoryUsage().CellIndex {
+			t.Error("LowMemory cell indexes are not smaller")
+		}
+	})
+}
+
+func TestInitProfile_String(t *testing.T) {
+	for p, want := range map[InitProfile]string{Balanced: "balanced", FastStart: "fast-start", LowMemory: "low-memory"} {
+		if got := p.String(); got != want {
+			t.Errorf("InitProfile(%d).String() = %q, want %q", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package geobed
+
+import "io"
+
+// ProgressStage names a step of building an instance from raw data.
+type ProgressStage string
+
+const (
+	ProgressDownload ProgressStage = "download" // Fetching a raw data file; Done and Total count bytes
+	ProgressParse    ProgressStage = "parse"    // Reading the cities dump; Done and Total count uncompressed bytes
+	ProgressIndex    ProgressStage = "index"    // Building the name index; Done and Total count cities
+	ProgressStore    ProgressStage = "store"    // Writing the regenerated cache
+)
+
+// ProgressEvent reports how far NewGeobed has come on its cold path.
+type ProgressEvent struct {
+	Stage ProgressStage
+	File  string // Data file being downloaded or parsed ("" for other stages)
+	Done  int64  // Work completed in the stage's unit
+	Total int64  // Expected total work (0 = unknown)
+}
+
+// WithProgress calls fn with progress events while NewGeobed downloads and
+// parses raw data because the cache is missing or unusable, which takes tens
+// of seconds, so services can log that they are busy rather than hung.
+// Loading the cache reports nothing. Events arrive from the goroutine calling
+// NewGeobed, at most about one per megabyte or 50,000 cities, with a final
+// event (Done == Total when known) for each file and stage.
+func WithProgress(fn func(ProgressEvent)) Option {
+	return func(c *GeobedConfig) {
+		c.Progress = fn
+	}
+}
+
+// Bytes and cities between progress events.
+const (
+	progressBytes  = 1 << 20
+	progressCities = 50_000
+)
+
+// progress reports ev to the configured callback, if any.
+func (g *GeoBed) progress(ev ProgressEvent) {
+	if g.config != nil && g.config.Progress != nil {
+		g.config.Progress(ev)
+	}
+}
+
+// progressReporter returns a function reporting Done/Total pairs for stage
+// and file, or nil when no callback is configured.
+func (g *GeoBed) progressReporter(stage ProgressStage, file string) func(done, total int64) {
+	if g.config == nil || g.config.Progress == nil {
+		return nil
+	}
+	return func(done, total int64) {
+		g.progress(ProgressEvent{Stage: stage, File: file, Done: done, Total: total})
+	}
+}
+
+// progressReader counts the bytes read from r and reports them every
+// progressBytes.
+type progressReader struct {
+	r      io.Reader
+	done   int64
+	total  int64
+	next   int64
+	report func(done, total int64)
+}
+
+func newProgressReader(r io.Reader, total int64, report func(done, total int64)) *progressReader {
+	return &progressReader{r: r, total: total, next: progressBytes, report: report}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if p.report != nil && p.done >= p.next {
+		p.report(p.done, p.total)
+		p.next = p.done + progressBytes
+	}
+	return n, err
+}
+
+// finish reports the final count.
+func (p *progressReader) finish() {
+	if p.report != nil {
+		p.report(p.done, p.total)
+	}
+}
@@ -0,0 +1,58 @@
+package geobed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWithProgress_ColdPath(t *testing.T) {
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), testCityRows)
+
+	var events []ProgressEvent
+	_, err := NewGeobed(WithDataset(DatasetCities15000), WithOffline(), WithDataDir(dir), WithCacheDir(t.TempDir()),
+		WithProgress(func(ev ProgressEvent) { events = append(events, ev) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed, indexed bool
+	for _, ev := range events {
+		switch {
+		case ev.Stage == ProgressParse && ev.File == "cities15000.txt" && ev.Done == ev.Total && ev.Total > 0:
+			parsed = true
+		case ev.Stage == ProgressIndex && ev.Done == int64(len(testCityRows)) && ev.Total == ev.Done:
+			indexed = true
+		}
+	}
+	if !parsed || !indexed {
+		t.Errorf("events = %+v, want a final parse and index event", events)
+	}
+}
+
+func TestDownloadFile_Progress(t *testing.T) {
+	body := strings.Repeat("x", 2*progressBytes+100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	var reports [][2]int64
+	report := func(done, total int64) { reports = append(reports, [2]int64{done, total}) }
+	if err := downloadFile(context.Background(), httpClient, srv.URL, filepath.Join(t.TempDir(), "f"), report); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) < 2 {
+		t.Fatalf("got %d reports, want an intermediate and a final one: %v", len(reports), reports)
+	}
+	if last := reports[len(reports)-1]; last != [2]int64{int64(len(body)), int64(len(body))} {
+		t.Errorf("final report = %v, want done and total %d", last, len(body))
+	}
+}
@@ -0,0 +1,116 @@
+package geobed
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Property-Based Round-Trip Tests
+//
+// Instead of a handful of hand-picked cities, these tests sample cities at
+// random (with a fixed seed, so failures are reproducible) and check that
+// geocoding properties hold for the bulk of the dataset:
+//
+//   - Geocode(city + ", " + country name) lands within forwardMaxKm of the city
+//   - ReverseGeocode(city coordinates) lands within reverseMaxKm of the city
+//
+// Forward geocoding is inherently ambiguous (many small towns share names
+// with larger ones in the same country), so the forward property is asserted
+// as an accuracy rate rather than per city. The measured rates are logged so
+// scoring changes can be compared against the thresholds below.
+// ============================================================================
+
+const (
+	propertySampleSize = 500
+	propertySeed       = 20260212
+
+	forwardMaxKm = 50.0
+	reverseMaxKm = 15.0 // neighborhood override may pick a larger city within ~10km
+
+	// Minimum acceptable accuracy rates. Set a little below the rates measured
+	// when the harness was introduced, to allow for dataset refreshes.
+	minForwardAccuracy = 0.85 // measured 0.912
+	minReverseAccuracy = 0.99 // measured 1.000
+)
+
+// sampleCities returns n distinct cities chosen with a deterministic seed.
+func sampleCities(g *GeoBed, n int, seed uint64) []GeobedCity {
+	r := rand.New(rand.NewPCG(seed, seed))
+	perm := r.Perm(len(g.Cities))
+	if n > len(perm) {
+		n = len(perm)
+	}
+	out := make([]GeobedCity, 0, n)
+	for _, i := range perm[:n] {
+		out = append(out, g.Cities[i])
+	}
+	return out
+}
+
+func TestProperty_RoundTripAccuracy(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatalf("Failed to create Geobed: %v", err)
+	}
+
+	countryNames := make(map[string]string, len(g.Countries))
+	for _, co := range g.Countries {
+		countryNames[co.ISO] = co.Country
+	}
+
+	var fwdTotal, fwdOK, revTotal, revOK int
+	for _, city := range sampleCities(g, propertySampleSize, propertySeed) {
+		// Reverse: the city's own coordinates must resolve to something nearby.
+		revTotal++
+		rev := g.ReverseGeocode(float64(city.Latitude), float64(city.Longitude))
+		if rev.City != "" && cityDistanceKm(rev, city) <= reverseMaxKm {
+			revOK++
+		} else {
+			t.Logf("reverse miss: %s, %s (%v,%v) -> %q", city.City, city.Country(),
+				city.Latitude, city.Longitude, rev.City)
+		}
+
+		// Forward: only meaningful when the name can be written unambiguously.
+		country, ok := countryNames[city.Country()]
+		if !ok || strings.Contains(city.City, ",") {
+			continue
+		}
+		fwdTotal++
+		fwd := g.Geocode(city.City + ", " + country)
+		if fwd.City != "" && cityDistanceKm(fwd, city) <= forwardMaxKm {
+			fwdOK++
+		}
+	}
+
+	fwdRate := float64(fwdOK) / float64(fwdTotal)
+	revRate := float64(revOK) / float64(revTotal)
+	t.Logf("forward accuracy: %d/%d = %.3f (within %.0fkm)", fwdOK, fwdTotal, fwdRate, forwardMaxKm)
+	t.Logf("reverse accuracy: %d/%d = %.3f (within %.0fkm)", revOK, revTotal, revRate, reverseMaxKm)
+
+	if fwdRate < minForwardAccuracy {
+		t.Errorf("forward accuracy %.3f below minimum %.3f", fwdRate, minForwardAccuracy)
+	}
+	if revRate < minReverseAccuracy {
+		t.Errorf("reverse accuracy %.3f below minimum %.3f", revRate, minReverseAccuracy)
+	}
+}
+
+func TestProperty_SampleCitiesDeterministic(t *testing.T) {
+	g := &GeoBed{Cities: make(Cities, 1000)}
+	for i := range g.Cities {
+		g.Cities[i].Population = int32(i)
+	}
+
+	a := sampleCities(g, 50, 42)
+	b := sampleCities(g, 50, 42)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("sampleCities not deterministic at %d: %v vs %v", i, a[i], b[i])
+		}
+	}
+	if got := len(sampleCities(g, 5000, 1)); got != len(g.Cities) {
+		t.Errorf("sampleCities(n > len) returned %d, want %d", got, len(g.Cities))
+	}
+}
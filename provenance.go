@@ -0,0 +1,44 @@
+package geobed
+
+import "time"
+
+// Provenance identifies the dataset results come from, so geocodes stored
+// with it can later be recognised as made against an outdated snapshot.
+type Provenance struct {
+	Dataset Dataset   `json:"dataset"` // Geonames dump; "" for instances built from records
+	Date    time.Time `json:"date"`    // When the dataset was produced; see GeoBed.DatasetDate
+}
+
+// String returns the provenance as "cities1000@2026-02-12", without the
+// date part when it is unknown.
+func (p Provenance) String() string {
+	s := string(p.Dataset)
+	if s == "" {
+		s = "records"
+	}
+	if !p.Date.IsZero() {
+		s += "@" + p.Date.Format(time.DateOnly)
+	}
+	return s
+}
+
+// Outdated reports whether p names another dataset than current, or an
+// older snapshot of it.
+func (p Provenance) Outdated(current Provenance) bool {
+	return p.Dataset != current.Dataset || p.Date.Before(current.Date)
+}
+
+// Provenance returns the dataset and snapshot date g's results come from.
+func (g *GeoBed) Provenance() Provenance {
+	return Provenance{Dataset: g.dataset, Date: g.datasetDate}
+}
+
+// Provenance returns the dataset and snapshot date City comes from.
+func (r GeocodeResult) Provenance() Provenance {
+	return Provenance{Dataset: r.Dataset, Date: r.DatasetDate}
+}
+
+// Provenance returns the dataset and snapshot date the cities come from.
+func (r ReverseGeocodeResult) Provenance() Provenance {
+	return Provenance{Dataset: r.Dataset, Date: r.DatasetDate}
+}
@@ -0,0 +1,38 @@
+package geobed
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProvenance(t *testing.T) {
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), testCityRows)
+	g, err := NewGeobed(WithDataset(DatasetCities15000), WithOffline(), WithDataDir(dir), WithCacheDir(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := g.Provenance()
+	if want.Dataset != DatasetCities15000 || want.Date.IsZero() {
+		t.Fatalf("Provenance() = %+v, want cities15000 with a date", want)
+	}
+	r := g.GeocodeDetailed("London")
+	if got := r.Provenance(); got != want {
+		t.Errorf("GeocodeDetailed(London).Provenance() = %v, want %v", got, want)
+	}
+	london := r.City
+	if got := g.ReverseGeocodeDetailed(float64(london.Latitude), float64(london.Longitude)).Provenance(); got != want {
+		t.Errorf("ReverseGeocodeDetailed().Provenance() = %v, want %v", got, want)
+	}
+
+	old := Provenance{Dataset: DatasetCities15000, Date: want.Date.AddDate(0, -1, 0)}
+	if !old.Outdated(want) || want.Outdated(want) || !(Provenance{Dataset: DatasetCities1000, Date: want.Date}).Outdated(want) {
+		t.Error("Outdated() does not flag older snapshots and other datasets only")
+	}
+	if got := (Provenance{Dataset: DatasetCities1000, Date: time.Date(2026, time.February, 12, 0, 0, 0, 0, time.UTC)}).String(); got != "cities1000@2026-02-12" {
+		t.Errorf("String() = %q, want cities1000@2026-02-12", got)
+	}
+}
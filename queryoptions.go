@@ -0,0 +1,81 @@
+package geobed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// queryOptions constrains CheckOptions to the per-query option structs.
+type queryOptions interface {
+	GeocodeOptions | ReverseGeocodeOptions
+	Validate() error
+}
+
+// CheckOptions returns o unchanged if it is valid, and otherwise the zero
+// options and the error from o.Validate. Queries accept any options and
+// clamp or ignore nonsensical values, which hides mistakes in options built
+// from request parameters or configuration; checking them once where they
+// are built surfaces those mistakes instead:
+//
+//	opts, err := geobed.CheckOptions(geobed.GeocodeOptions{FuzzyDistance: n})
+//	if err != nil {
+//	    return err // e.g. geobed: invalid GeocodeOptions.FuzzyDistance: 5 is above 3
+//	}
+func CheckOptions[T queryOptions](o T) (T, error) {
+	if err := o.Validate(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return o, nil
+}
+
+// Validate reports the first invalid field of o as a *ConfigError, which
+// matches ErrInvalidConfig: a negative FuzzyDistance or one above the
+// maximum Geocode would clamp it to, FuzzyDistance with ExactCity (which
+// never matches fuzzily), or a Country that is not a two-letter code.
+func (o GeocodeOptions) Validate() error {
+	switch {
+	case o.FuzzyDistance < 0:
+		return &ConfigError{"GeocodeOptions.FuzzyDistance", fmt.Errorf("%d is negative", o.FuzzyDistance)}
+	case o.FuzzyDistance > maxFuzzyDistance:
+		return &ConfigError{"GeocodeOptions.FuzzyDistance", fmt.Errorf("%d is above %d", o.FuzzyDistance, maxFuzzyDistance)}
+	case o.ExactCity && o.FuzzyDistance > 0:
+		return &ConfigError{"GeocodeOptions.ExactCity", errors.New("exact matching ignores FuzzyDistance")}
+	case o.Country != "" && !isCountryCode(toUpper(strings.TrimSpace(o.Country))):
+		return &ConfigError{"GeocodeOptions.Country", fmt.Errorf("%q is not an ISO 3166-1 alpha-2 code", o.Country)}
+	}
+	return nil
+}
+
+// Validate reports the first invalid field of o as a *ConfigError, which
+// matches ErrInvalidConfig: negative or NaN values, an unknown
+// FallbackLevel, or Metro parameters with IncludeNeighborhoods, which
+// skips the metro override they tune.
+func (o ReverseGeocodeOptions) Validate() error {
+	m := o.Metro
+	switch {
+	case o.MinPopulation < 0:
+		return &ConfigError{"ReverseGeocodeOptions.MinPopulation", fmt.Errorf("%d is negative", o.MinPopulation)}
+	case m.MaxPopulation < 0:
+		return &ConfigError{"ReverseGeocodeOptions.Metro.MaxPopulation", fmt.Errorf("%d is negative", m.MaxPopulation)}
+	case m.Factor < 0:
+		return &ConfigError{"ReverseGeocodeOptions.Metro.Factor", fmt.Errorf("%d is negative", m.Factor)}
+	case m.RadiusKm < 0 || math.IsNaN(m.RadiusKm):
+		return &ConfigError{"ReverseGeocodeOptions.Metro.RadiusKm", fmt.Errorf("%v is not a distance", m.RadiusKm)}
+	case m.DecayKm < 0 || math.IsNaN(m.DecayKm):
+		return &ConfigError{"ReverseGeocodeOptions.Metro.DecayKm", fmt.Errorf("%v is not a distance", m.DecayKm)}
+	case o.IncludeNeighborhoods && m != (MetroOverride{}):
+		return &ConfigError{"ReverseGeocodeOptions.Metro", errors.New("IncludeNeighborhoods skips the metro override")}
+	case o.FallbackLevel < FallbackNone || o.FallbackLevel > FallbackContinent:
+		return &ConfigError{"ReverseGeocodeOptions.FallbackLevel", fmt.Errorf("unknown level %d", o.FallbackLevel)}
+	}
+	return nil
+}
+
+// isCountryCode reports whether s has the form of an upper-case ISO 3166-1
+// alpha-2 code.
+func isCountryCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
@@ -0,0 +1,61 @@
+package geobed
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCheckOptions(t *testing.T) {
+	geocode := []struct {
+		opts  GeocodeOptions
+		field string // "" = valid
+	}{
+		{GeocodeOptions{}, ""},
+		{GeocodeOptions{FuzzyDistance: 2, Country: " de "}, ""},
+		{GeocodeOptions{ExactCity: true, Country: "US"}, ""},
+		{GeocodeOptions{FuzzyDistance: -1}, "GeocodeOptions.FuzzyDistance"},
+		{GeocodeOptions{FuzzyDistance: 5}, "GeocodeOptions.FuzzyDistance"},
+		{GeocodeOptions{ExactCity: true, FuzzyDistance: 1}, "GeocodeOptions.ExactCity"},
+		{GeocodeOptions{Country: "Germany"}, "GeocodeOptions.Country"},
+	}
+	for _, tt := range geocode {
+		got, err := CheckOptions(tt.opts)
+		checkOptionsResult(t, tt.opts, got, err, tt.field)
+	}
+
+	reverse := []struct {
+		opts  ReverseGeocodeOptions
+		field string
+	}{
+		{ReverseGeocodeOptions{}, ""},
+		{ReverseGeocodeOptions{MinPopulation: 1000, Metro: MetroOverride{RadiusKm: 20}, FallbackLevel: FallbackCountry}, ""},
+		{ReverseGeocodeOptions{MinPopulation: -1}, "ReverseGeocodeOptions.MinPopulation"},
+		{ReverseGeocodeOptions{Metro: MetroOverride{Factor: -2}}, "ReverseGeocodeOptions.Metro.Factor"},
+		{ReverseGeocodeOptions{Metro: MetroOverride{RadiusKm: math.NaN()}}, "ReverseGeocodeOptions.Metro.RadiusKm"},
+		{ReverseGeocodeOptions{IncludeNeighborhoods: true, Metro: MetroOverride{RadiusKm: 5}}, "ReverseGeocodeOptions.Metro"},
+		{ReverseGeocodeOptions{FallbackLevel: 7}, "ReverseGeocodeOptions.FallbackLevel"},
+	}
+	for _, tt := range reverse {
+		got, err := CheckOptions(tt.opts)
+		checkOptionsResult(t, tt.opts, got, err, tt.field)
+	}
+}
+
+func checkOptionsResult[T comparable](t *testing.T, in, got T, err error, field string) {
+	t.Helper()
+	if field == "" {
+		if err != nil || got != in {
+			t.Errorf("CheckOptions(%+v) = %+v, %v; want the options back", in, got, err)
+		}
+		return
+	}
+	var ce *ConfigError
+	if !errors.As(err, &ce) || ce.Field != field || !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("CheckOptions(%+v) error = %v, want ConfigError for %s", in, err, field)
+	}
+	var zero T
+	if got != zero {
+		t.Errorf("CheckOptions(%+v) = %+v with an error, want zero options", in, got)
+	}
+}
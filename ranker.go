@@ -0,0 +1,126 @@
+package geobed
+
+import "strings"
+
+// ParsedQuery is a forward-geocoding query after country and state
+// extraction, as passed to a Ranker.
+type ParsedQuery struct {
+	Raw           string   `json:"raw"`            // Query as given, trimmed
+	Cleaned       string   `json:"cleaned"`        // Name part with country and state removed
+	Country       string   `json:"country"`        // ISO country code found in the query ("" if none)
+	Neighbours    []string `json:"neighbours"`     // ISO codes of countries bordering Country
+	State         string   `json:"state"`          // Region code found in the query ("" if none)
+	Abbrevs       []string `json:"abbrevs"`        // 2-3 letter tokens of the raw query (e.g., "TX", "NSW")
+	Names         []string `json:"names"`          // Space-separated tokens of Cleaned
+	FuzzyDistance int      `json:"fuzzy_distance"` // Edit distance allowed for typo tolerance (0 = exact)
+}
+
+// Ranker scores a candidate city for a forward-geocoding query. Geocode
+// returns the candidate with the highest positive score; ties go to the
+// more populous city, then to the earlier city in Cities. Candidates scoring
+// zero or less are never returned. Score is called concurrently and must
+// not modify the query's slices.
+type Ranker interface {
+	Score(q ParsedQuery, candidate GeobedCity) int
+}
+
+// WithRanker replaces the built-in scoring heuristic. Candidates are still
+// collected from the name index as usual; only their ranking changes. With
+// a custom ranker the "City, ST" shortcut, the population bonus, and the
+// neighbouring-country preference applied by the default heuristic are
+// skipped, so the ranker sees every candidate and its scores are used as
+// returned.
+func WithRanker(r Ranker) Option {
+	return func(c *GeobedConfig) {
+		c.Ranker = r
+	}
+}
+
+// ranker returns the configured ranker and whether it is a custom one.
+func (g *GeoBed) ranker() (Ranker, bool) {
+	if g.config != nil && g.config.Ranker != nil {
+		return g.config.Ranker, true
+	}
+	return DefaultRanker{}, false
+}
+
+// DefaultRanker is the built-in scoring heuristic. It rewards exact and
+// alternate-name matches and agreement with the country, state, and
+// abbreviations in the query, and penalizes a country mismatch. Custom
+// rankers can embed it and adjust its score.
+type DefaultRanker struct{}
+
+// Score implements Ranker.
+func (DefaultRanker) Score(q ParsedQuery, v GeobedCity) int {
+	score := 0
+	vCountry := v.Country()
+	vRegion := v.Region()
+
+	for _, av := range q.Abbrevs {
+		if len(av) == 2 && strings.EqualFold(vRegion, av) {
+			score += 5
+		}
+		if len(av) == 2 && strings.EqualFold(vCountry, av) {
+			score += 3
+		}
+		if len(av) == 3 && strings.EqualFold(vRegion, av) {
+			score += 4
+		}
+	}
+
+	if q.Country != "" {
+		if q.Country == vCountry {
+			score += 4
+		} else {
+			// Country mismatch penalty: when the user explicitly specified a
+			// country (e.g., "Bogota, Colombia"), wrong-country candidates should
+			// score lower to prevent same-name cities from winning via exact-match
+			// bonuses alone.
+			score -= 5
+		}
+	}
+
+	if q.State != "" && q.State == vRegion {
+		score += 4
+	}
+
+	// Alt name matching — split on commas, not whitespace
+	if v.CityAlt != "" {
+		for _, raw := range strings.Split(v.CityAlt, ",") {
+			altV := strings.TrimSpace(raw)
+			if altV == "" {
+				continue
+			}
+			if strings.EqualFold(altV, q.Cleaned) {
+				score += 3
+			}
+			if altV == q.Cleaned {
+				score += 5
+			}
+		}
+	}
+
+	// Exact match gets highest bonus
+	if strings.EqualFold(q.Cleaned, v.City) {
+		score += 7
+	} else if q.FuzzyDistance > 0 {
+		// Fuzzy matching with Levenshtein distance
+		for _, ns := range q.Names {
+			ns = strings.TrimSuffix(ns, ",")
+			if len(ns) > 2 && fuzzyMatch(ns, v.City, q.FuzzyDistance) {
+				score += 5
+			}
+		}
+	}
+
+	for _, ns := range q.Names {
+		ns = strings.TrimSuffix(ns, ",")
+		if strings.Contains(toLower(v.City), toLower(ns)) {
+			score += 2
+		}
+		if strings.EqualFold(v.City, ns) {
+			score += 1
+		}
+	}
+	return score
+}
@@ -0,0 +1,44 @@
+package geobed
+
+import "testing"
+
+// populationRanker prefers the smallest matching city, the opposite of the
+// default heuristic, to prove custom scores drive the result.
+type populationRanker struct{}
+
+func (populationRanker) Score(q ParsedQuery, c GeobedCity) int {
+	if !equalFoldCity(q.Cleaned, c) || (q.Country != "" && q.Country != c.Country()) {
+		return 0
+	}
+	return 1_000_000_000 - int(c.Population)
+}
+
+func equalFoldCity(name string, c GeobedCity) bool {
+	return toLower(name) == toLower(c.City)
+}
+
+// recordingRanker wraps DefaultRanker and records the parsed query.
+type recordingRanker struct {
+	DefaultRanker
+	last *ParsedQuery
+}
+
+func (r recordingRanker) Score(q ParsedQuery, c GeobedCity) int {
+	*r.last = q
+	return r.DefaultRanker.Score(q, c)
+}
+
+func TestDefaultRanker_Score(t *testing.T) {
+	austinTX := mustCity(t, geobedCityGob{City: "Austin", Country: "US", Region: "TX", Population: 900000})
+	austinMN := mustCity(t, geobedCityGob{City: "Austin", Country: "US", Region: "MN", Population: 25000})
+	q := ParsedQuery{Raw: "Austin, TX", Cleaned: "Austin", Country: "US", State: "TX", Abbrevs: []string{"TX"}, Names: []string{"Austin"}}
+
+	var r DefaultRanker
+	if tx, mn := r.Score(q, austinTX), r.Score(q, austinMN); tx <= mn {
+		t.Errorf("Score(Austin TX) = %d, Score(Austin MN) = %d; want TX higher", tx, mn)
+	}
+	q.Country = "FR"
+	if got := r.Score(q, austinMN); got >= r.Score(ParsedQuery{Cleaned: "Austin", Names: []string{"Austin"}}, austinMN) {
+		t.Errorf("country mismatch not penalized: %d", got)
+	}
+}
@@ -0,0 +1,97 @@
+package geobed
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CityRecord is a city given field by field, for building a GeoBed from
+// your own records with NewGeobedFromRecords.
+type CityRecord struct {
+	City       string  // City name (required)
+	CityAlt    string  // Alternate names (comma-separated)
+	Country    string  // ISO 3166-1 alpha-2 country code (e.g., "US")
+	Region     string  // Administrative region code (e.g., "TX")
+	Latitude   float64 // Latitude in degrees
+	Longitude  float64 // Longitude in degrees
+	Population int32   // Population count
+	GeonameID  int32   // Geonames record ID (0 if none), which GeobedCity.ID is derived from
+}
+
+// NewGeobedFromRecords builds a GeoBed from the given cities instead of the
+// embedded dataset, e.g. for small fixtures in tests. Countries supplies the
+// country metadata used to read country names in queries; when nil, the
+// embedded country data is used. The cities are reported with
+// SourceRecords and, unlike places, are used for reverse geocoding. All
+// options apply as they do for NewGeobed, and no cache is read or
+// written.
+func NewGeobedFromRecords(cities []CityRecord, countries []CountryInfo, opts ...Option) (*GeoBed, error) {
+	g, err := newGeobed(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if countries == nil {
+		if countries, err = loadGeobedCountryData(g.config.CacheDir); err != nil {
+			return nil, err
+		}
+	}
+	g.Countries = countries
+
+	g.Cities = make(Cities, 0, len(cities))
+	for i, r := range cities {
+		c, err := r.toCity(SourceRecords)
+		if err != nil {
+			return nil, fmt.Errorf("city record %d: %w", i, err)
+		}
+		g.Cities = append(g.Cities, c)
+	}
+	sort.SliceStable(g.Cities, func(i, j int) bool {
+		return compareCities(g.Cities[i], g.Cities[j]) < 0
+	})
+	g.rebuildNameIndex()
+
+	if err := g.finishInit(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Record returns c as a CityRecord, keeping its alternate names and record
+// ID, e.g. to rebuild an instance's cities with NewGeobedFromRecords in a
+// Manager.Update callback. The rebuilt city has the same ID but reports
+// SourceRecords, and Capital then finds it by name as for other records.
+func (c GeobedCity) Record() CityRecord {
+	return CityRecord{
+		City:       c.City,
+		CityAlt:    c.CityAlt,
+		Country:    c.Country(),
+		Region:     c.Region(),
+		Latitude:   float64(c.Latitude),
+		Longitude:  float64(c.Longitude),
+		Population: c.Population,
+		GeonameID:  c.geonameID,
+	}
+}
+
+// toCity validates r and converts it to a city from source.
+func (r CityRecord) toCity(source RecordSource) (GeobedCity, error) {
+	if strings.TrimSpace(r.City) == "" {
+		return GeobedCity{}, fmt.Errorf("empty name")
+	}
+	if !validCoordinates(r.Latitude, r.Longitude) {
+		return GeobedCity{}, fmt.Errorf("%s: invalid coordinates %v, %v", r.City, r.Latitude, r.Longitude)
+	}
+	return geobedCityGob{
+		City:       r.City,
+		CityAlt:    r.CityAlt,
+		Country:    r.Country,
+		Region:     r.Region,
+		Latitude:   float32(r.Latitude),
+		Longitude:  float32(r.Longitude),
+		Population: r.Population,
+		GeonameID:  r.GeonameID,
+		Source:     source,
+	}.toCity()
+}
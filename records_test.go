@@ -0,0 +1,55 @@
+package geobed
+
+import "testing"
+
+func TestNewGeobedFromRecords(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Zurich", CityAlt: "Zürich", Country: "CH", Region: "ZH", Latitude: 47.36667, Longitude: 8.55, Population: 341730},
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+	}, []CountryInfo{{ISO: "CH", Country: "Switzerland"}, {ISO: "US", Country: "United States"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cities are kept in the usual name order.
+	if g.Cities[0].City != "Austin" || g.Cities[0].Source() != SourceRecords {
+		t.Errorf("Cities[0] = %s (%v), want Austin (records)", g.Cities[0].City, g.Cities[0].Source())
+	}
+	if c := g.Geocode("zürich, Switzerland"); c.City != "Zurich" {
+		t.Errorf("Geocode(zürich, Switzerland) = %q, want Zurich", c.City)
+	}
+	if c := g.ReverseGeocode(30.27, -97.74); c.City != "Austin" {
+		t.Errorf("ReverseGeocode(Austin) = %q, want Austin", c.City)
+	}
+
+	for _, bad := range []CityRecord{
+		{City: " ", Latitude: 1, Longitude: 1},
+		{City: "Nowhere", Latitude: 1, Longitude: 181},
+	} {
+		if _, err := NewGeobedFromRecords([]CityRecord{bad}, nil); err == nil {
+			t.Errorf("NewGeobedFromRecords(%+v) error = nil", bad)
+		}
+	}
+}
+
+func TestGeobedCity_Record(t *testing.T) {
+	// Coordinates exactly representable as float32 survive the round trip.
+	rec := CityRecord{City: "Zurich", CityAlt: "Zürich,Zuerich", Country: "CH", Region: "ZH",
+		Latitude: 47.375, Longitude: 8.5, Population: 341730, GeonameID: 2657896}
+	g, err := NewGeobedFromRecords([]CityRecord{rec}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Cities[0].Record(); got != rec {
+		t.Errorf("Record() = %+v, want %+v", got, rec)
+	}
+
+	// Rebuilding from records keeps every field, including the ID.
+	g2, err := NewGeobedFromRecords([]CityRecord{g.Cities[0].Record()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g2.Cities[0], g.Cities[0]; got != want || got.ID() != "2657896" {
+		t.Errorf("rebuilt city = %+v (ID %s), want %+v", got, got.ID(), want)
+	}
+}
@@ -0,0 +1,48 @@
+package geobed
+
+// CitiesInRegion returns the cities of an admin1 division in name order,
+// e.g. to fill a city picker once a state or province is selected. admin1
+// is the division code as used by Geonames ("TX", "08"); a division name
+// ("Ontario") is resolved through the admin1 data of the data directory.
+// Supplemental places are left out unless WithReversePlaces is set, and
+// near-duplicates with WithDedupe. The country+region index is built on
+// first use; the returned slice is a copy.
+func (g *GeoBed) CitiesInRegion(country, admin1 string) []GeobedCity {
+	country = toUpper(country)
+	byRegion := g.citiesByRegion()
+	idxs, ok := byRegion[regionKey(country, toUpper(admin1))]
+	if !ok {
+		if cc, code := g.findAdminDivisionByName(country, admin1); cc != "" {
+			idxs = byRegion[regionKey(cc, code)]
+		}
+	}
+	out := make([]GeobedCity, 0, len(idxs))
+	d := g.deduper()
+	for _, idx := range idxs {
+		if d.keep(g.Cities[idx]) {
+			out = append(out, g.Cities[idx])
+		}
+	}
+	return out
+}
+
+// regionKey joins a country and admin1 code as in admin1CodesASCII.txt.
+func regionKey(country, admin1 string) string {
+	return country + "." + admin1
+}
+
+// citiesByRegion returns city indices per regionKey in Cities order,
+// building them on first use.
+func (g *GeoBed) citiesByRegion() map[string][]int {
+	g.byRegionOnce.Do(func() {
+		g.byRegion = make(map[string][]int)
+		for i, c := range g.Cities {
+			if c.Region() == "" || (c.source == SourceCustom && !g.config.ReversePlaces) {
+				continue
+			}
+			key := regionKey(c.Country(), c.Region())
+			g.byRegion[key] = append(g.byRegion[key], i)
+		}
+	})
+	return g.byRegion
+}
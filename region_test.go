@@ -0,0 +1,39 @@
+package geobed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCitiesInRegion(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Toronto", Country: "CA", Region: "08", Latitude: 43.7, Longitude: -79.42},
+		{City: "Ottawa", Country: "CA", Region: "08", Latitude: 45.41, Longitude: -75.7},
+		{City: "Montreal", Country: "CA", Region: "10", Latitude: 45.51, Longitude: -73.59},
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.27, Longitude: -97.74},
+	}, nil, WithDataDir("./geobed-data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		country, admin1 string
+		want            []string
+	}{
+		{"CA", "08", []string{"Ottawa", "Toronto"}},
+		{"ca", "Ontario", []string{"Ottawa", "Toronto"}},
+		{"us", "tx", []string{"Austin"}},
+		{"US", "08", nil},
+		{"CA", "Nowhere", nil},
+	}
+	for _, tt := range tests {
+		got := g.CitiesInRegion(tt.country, tt.admin1)
+		var names []string
+		for _, c := range got {
+			names = append(names, c.City)
+		}
+		if !slices.Equal(names, tt.want) {
+			t.Errorf("CitiesInRegion(%q, %q) = %v, want %v", tt.country, tt.admin1, names, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package geobed
+
+import (
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// coarseCellLevel is the S2 level of the coarse city index. Its cells are
+// at least ~47km wide, more than the ~30km reached by the neighbour search
+// around a level-10 cell, so a point with no city in its coarse cell or the
+// cells around it has no reverse-geocoding candidate either.
+const coarseCellLevel = 7
+
+// maxNearestRings bounds the search for the nearest city of a remote point
+// to roughly 128 coarse cells (several thousand km) in every direction.
+const maxNearestRings = 128
+
+// buildCoarseIndex indexes the reverse-geocodable cities by their
+// coarse cell.
+func (g *GeoBed) buildCoarseIndex() {
+	g.coarseIndex = make(map[s2.CellID][]int)
+	for cell, postings := range g.cellIndex {
+		parent := cell.Parent(coarseCellLevel)
+		g.coarseIndex[parent] = append(g.coarseIndex[parent], postings...)
+	}
+}
+
+// isRemote reports whether no reverse-geocodable city lies in the coarse
+// cell of ll or in the cells around it. It says nothing about land or sea:
+// the open ocean is remote, and so are ice caps and deep desert. Without an
+// index nothing is remote.
+func (g *GeoBed) isRemote(ll s2.LatLng) bool {
+	if g.coarseIndex == nil {
+		return false
+	}
+	cell := s2.CellIDFromLatLng(ll).Parent(coarseCellLevel)
+	for _, c := range g.cellAndNeighbors(cell) {
+		if len(g.coarseIndex[c]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// nearestCity returns the city of at least minPop inhabitants nearest
+// to ll, searching rings of coarse cells outwards from ll. Once a ring
+// holds a city, rings continue up to three times as far, since cells vary
+// in size and a later ring can hold a nearer city. The zero city is
+// returned when nothing lies within maxNearestRings.
+func (g *GeoBed) nearestCity(ll s2.LatLng, minPop int32) GeobedCity {
+	start := s2.CellIDFromLatLng(ll).Parent(coarseCellLevel)
+	seen := map[s2.CellID]bool{start: true}
+	ring := []s2.CellID{start}
+
+	best, bestDist := -1, math.Inf(1)
+	stop := maxNearestRings
+	for r := 0; r < stop && len(ring) > 0; r++ {
+		for _, cell := range ring {
+			for _, i := range g.coarseIndex[cell] {
+				c := g.Cities[i]
+				if c.Population < minPop {
+					continue
+				}
+				d := float64(ll.Distance(s2.LatLngFromDegrees(float64(c.Latitude), float64(c.Longitude))))
+				if d < bestDist || (d == bestDist && i < best) {
+					best, bestDist = i, d
+				}
+			}
+		}
+		if best >= 0 {
+			stop = min(stop, 3*(r+1)+1)
+		}
+
+		var next []s2.CellID
+		for _, cell := range ring {
+			for _, n := range cell.AllNeighbors(coarseCellLevel) {
+				if !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		ring = next
+	}
+
+	if best < 0 {
+		return GeobedCity{}
+	}
+	return g.Cities[best]
+}
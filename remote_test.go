@@ -0,0 +1,71 @@
+package geobed
+
+import (
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestRemote(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No city is remote from itself.
+	for i := 0; i < len(g.Cities); i += 997 {
+		c := g.Cities[i]
+		if g.isRemote(s2.LatLngFromDegrees(float64(c.Latitude), float64(c.Longitude))) {
+			t.Fatalf("isRemote(%s, %s) = true", c.City, c.Country())
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		lat, lng float64
+	}{
+		{"mid-Atlantic", 30, -40},
+		{"Point Nemo", -48.8767, -123.3933},
+		{"Indian Ocean", -30, 80},
+		{"Sahara", 23, 12},
+		{"Greenland ice sheet", 75, -40},
+	} {
+		r := g.ReverseGeocodeDetailed(tt.lat, tt.lng)
+		if !r.Remote || r.Locality.City != "" || r.Metro.City != "" {
+			t.Errorf("%s: ReverseGeocodeDetailed() = %+v, want empty and Remote", tt.name, r)
+		}
+		nearest := g.ReverseGeocodeDetailed(tt.lat, tt.lng, ReverseGeocodeOptions{NearestIfRemote: true})
+		if !nearest.Remote || nearest.Locality.City == "" || nearest.Locality != nearest.Metro {
+			t.Errorf("%s: with NearestIfRemote = %+v, want a city and Remote", tt.name, nearest)
+		}
+	}
+
+	// A few km off Nice, the nearest city is on the Côte d'Azur.
+	nearest := g.ReverseGeocode(43.55, 7.3, ReverseGeocodeOptions{NearestIfRemote: true})
+	if nearest.Country() != "FR" && nearest.Country() != "MC" {
+		t.Errorf("ReverseGeocode(43.55, 7.3, NearestIfRemote) = %s, %s; want a city on the French Riviera", nearest.City, nearest.Country())
+	}
+	if r := g.ReverseGeocodeDetailed(48.8566, 2.3522); r.Remote || r.Metro.City == "" {
+		t.Errorf("ReverseGeocodeDetailed(Paris) = %+v, want a city that is not remote", r)
+	}
+}
+
+// A remote point never has a city the cell search would have found.
+func TestRemote_NoCityInRange(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for lat := -80.0; lat <= 80; lat += 0.7 {
+		for lng := -180.0; lng < 180; lng += 0.7 {
+			ll := s2.LatLngFromDegrees(lat, lng)
+			if !g.isRemote(ll) {
+				continue
+			}
+			cell := s2.CellIDFromLatLng(ll).Parent(s2CellLevel)
+			if _, _, ok := g.pickReverse(ll, g.cellCandidates(cell, 0), MetroOverride{}); ok {
+				t.Fatalf("isRemote(%v, %v) = true but the cell search finds a city", lat, lng)
+			}
+		}
+	}
+}
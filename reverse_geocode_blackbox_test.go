@@ -7,7 +7,7 @@ import (
 )
 
 func TestReverseGeocode_KnownCities(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -136,7 +136,7 @@ func TestReverseGeocode_KnownCities(t *testing.T) {
 }
 
 func TestReverseGeocode_RemoteLocations(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -165,7 +165,7 @@ func TestReverseGeocode_RemoteLocations(t *testing.T) {
 }
 
 func TestReverseGeocode_InvalidCoordinates(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -204,7 +204,7 @@ func TestReverseGeocode_InvalidCoordinates(t *testing.T) {
 }
 
 func TestReverseGeocode_BoundaryCoordinates(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -243,7 +243,7 @@ func TestReverseGeocode_BoundaryCoordinates(t *testing.T) {
 }
 
 func TestReverseGeocode_NeighborhoodOverride(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -275,8 +275,41 @@ func TestReverseGeocode_NeighborhoodOverride(t *testing.T) {
 	})
 }
 
+func TestReverseGeocode_IncludeNeighborhoods(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatalf("Failed to create Geobed: %v", err)
+	}
+
+	tests := []struct {
+		name                string
+		lat, lng            float64
+		wantMetro, wantHood string
+	}{
+		{"Mitte", 52.5200, 13.4050, "Berlin", "Mitte"},
+		{"Bushwick", 40.6782, -73.9442, "New York City", "Bushwick"},
+		{"Paris_center", 48.8566, 2.3522, "Paris", "Paris"}, // no override needed
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.ReverseGeocode(tt.lat, tt.lng); got.City != tt.wantMetro {
+				t.Errorf("ReverseGeocode(%v, %v) = %q, want %q", tt.lat, tt.lng, got.City, tt.wantMetro)
+			}
+			got := g.ReverseGeocode(tt.lat, tt.lng, ReverseGeocodeOptions{IncludeNeighborhoods: true})
+			if got.City != tt.wantHood {
+				t.Errorf("ReverseGeocode(%v, %v, IncludeNeighborhoods) = %q, want %q", tt.lat, tt.lng, got.City, tt.wantHood)
+			}
+		})
+	}
+
+	if got := g.ReverseGeocode(0, -160, ReverseGeocodeOptions{IncludeNeighborhoods: true}); got.City != "" {
+		t.Errorf("remote location with IncludeNeighborhoods = %q, want empty", got.City)
+	}
+}
+
 func TestReverseGeocode_SmallOffsets(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -332,7 +365,7 @@ func TestReverseGeocode_SmallOffsets(t *testing.T) {
 }
 
 func TestReverseGeocode_Determinism(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -373,7 +406,7 @@ func TestReverseGeocode_Determinism(t *testing.T) {
 }
 
 func TestReverseGeocode_ConcurrentSafety(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -432,7 +465,7 @@ func TestReverseGeocode_ConcurrentSafety(t *testing.T) {
 }
 
 func TestReverseGeocode_EmptyResult(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -458,7 +491,7 @@ func TestReverseGeocode_EmptyResult(t *testing.T) {
 }
 
 func TestReverseGeocode_ResultFields(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -495,3 +528,31 @@ func TestReverseGeocode_ResultFields(t *testing.T) {
 	region := result.Region()
 	_ = region // Region can be empty, just verify it doesn't panic
 }
+
+func TestReverseGeocodeDetailed(t *testing.T) {
+	g, err := sharedGeobed()
+	if err != nil {
+		t.Fatalf("Failed to create Geobed: %v", err)
+	}
+
+	r := g.ReverseGeocodeDetailed(40.6782, -73.9442)
+	if r.Locality.City != "Bushwick" || r.Metro.City != "New York City" {
+		t.Errorf("ReverseGeocodeDetailed(Brooklyn) = %q (%q), want Bushwick (New York City)", r.Locality.City, r.Metro.City)
+	}
+
+	r = g.ReverseGeocodeDetailed(48.8566, 2.3522)
+	if r.Locality != r.Metro || r.Metro.City != "Paris" {
+		t.Errorf("ReverseGeocodeDetailed(Paris) = %q (%q), want Paris for both", r.Locality.City, r.Metro.City)
+	}
+
+	// Must agree with the single-result calls.
+	for _, p := range [][2]float64{{52.52, 13.405}, {51.513, -0.092}, {35.6762, 139.6503}, {0, -160}} {
+		r := g.ReverseGeocodeDetailed(p[0], p[1])
+		if metro := g.ReverseGeocode(p[0], p[1]); r.Metro != metro {
+			t.Errorf("%v: Metro = %q, ReverseGeocode = %q", p, r.Metro.City, metro.City)
+		}
+		if loc := g.ReverseGeocode(p[0], p[1], ReverseGeocodeOptions{IncludeNeighborhoods: true}); r.Locality != loc {
+			t.Errorf("%v: Locality = %q, IncludeNeighborhoods = %q", p, r.Locality.City, loc.City)
+		}
+	}
+}
@@ -5,7 +5,7 @@ import (
 )
 
 func TestRoundTrip(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -0,0 +1,46 @@
+package geobed
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// SampleCities returns up to n cities picked uniformly at random among
+// those passing every filter, e.g. for test fixtures and demo data. The
+// same seed returns the same cities for the same data, across runs and Go
+// versions. Cities are returned in the order of g.Cities.
+//
+// The sample is drawn in one pass over the cities, holding only the n
+// picks, so sampling a large dataset does not copy it.
+func (g *GeoBed) SampleCities(n int, seed int64, filter ...func(GeobedCity) bool) []GeobedCity {
+	if n <= 0 {
+		return nil
+	}
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+
+	// Reservoir sampling: the k-th eligible city replaces a pick with
+	// probability n/k.
+	picks := make([]int, 0, n)
+	k := 0
+cities:
+	for i, c := range g.Cities {
+		for _, keep := range filter {
+			if !keep(c) {
+				continue cities
+			}
+		}
+		k++
+		if len(picks) < n {
+			picks = append(picks, i)
+		} else if j := rng.IntN(k); j < n {
+			picks[j] = i
+		}
+	}
+
+	slices.Sort(picks)
+	out := make([]GeobedCity, len(picks))
+	for j, i := range picks {
+		out[j] = g.Cities[i]
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package geobed
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSampleCities(t *testing.T) {
+	var records []CityRecord
+	for i := range 100 {
+		country := "FR"
+		if i%2 == 0 {
+			country = "DE"
+		}
+		records = append(records, CityRecord{City: fmt.Sprintf("Town %02d", i), Country: country, Region: "01"})
+	}
+	g, err := NewGeobedFromRecords(records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := g.SampleCities(10, 42), g.SampleCities(10, 42)
+	if len(a) != 10 || !slices.Equal(a, b) {
+		t.Fatalf("SampleCities(10, 42) twice = %v and %v, want the same 10 cities", a, b)
+	}
+	if !slices.IsSortedFunc(a, func(x, y GeobedCity) int { return compareCaseInsensitive(x.City, y.City) }) {
+		t.Errorf("SampleCities(10, 42) = %v, want the order of Cities", a)
+	}
+	if c := g.SampleCities(10, 43); slices.Equal(a, c) {
+		t.Errorf("SampleCities(10, 43) = SampleCities(10, 42), want another sample")
+	}
+
+	german := g.SampleCities(80, 1, func(c GeobedCity) bool { return c.Country() == "DE" })
+	if len(german) != 50 {
+		t.Errorf("SampleCities(80, DE) returned %d cities, want all 50 German ones", len(german))
+	}
+	for _, c := range german {
+		if c.Country() != "DE" {
+			t.Errorf("SampleCities(80, DE) returned %s in %s", c.City, c.Country())
+		}
+	}
+	if got := g.SampleCities(0, 1); got != nil {
+		t.Errorf("SampleCities(0) = %v, want nil", got)
+	}
+}
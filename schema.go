@@ -0,0 +1,115 @@
+package geobed
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// geonamesSchemaVersion names the layout of the Geonames dump files the
+// parsers expect, as documented in the Geonames export readme.
+const geonamesSchemaVersion = "geonames-dump-v1"
+
+// schemaSampleLines is how many data rows of each raw file are checked
+// before parsing relies on the layout.
+const schemaSampleLines = 20
+
+// rawSchema describes the expected layout of a tab-separated raw data file.
+type rawSchema struct {
+	name    string                     // Human-readable file kind
+	columns int                        // Tab-separated columns per data row
+	header  string                     // Prefix of a comment line naming the columns ("" = none)
+	valid   func(fields []string) bool // Sanity check of a data row's key fields
+}
+
+var (
+	citiesSchema = rawSchema{
+		name:    "Geonames cities dump",
+		columns: 19,
+		valid: func(f []string) bool {
+			_, errID := strconv.ParseUint(f[0], 10, 32)
+			_, errLat := strconv.ParseFloat(f[4], 64)
+			_, errLng := strconv.ParseFloat(f[5], 64)
+			return errID == nil && errLat == nil && errLng == nil
+		},
+	}
+	countryInfoSchema = rawSchema{
+		name:    "Geonames countryInfo",
+		columns: 19,
+		header:  "#ISO\t",
+		valid:   func(f []string) bool { return len(f[0]) == 2 && f[0] == toUpper(f[0]) },
+	}
+	admin1Schema = rawSchema{
+		name:    "Geonames admin1CodesASCII",
+		columns: 4,
+		valid:   func(f []string) bool { return strings.Contains(f[0], ".") },
+	}
+)
+
+// schemaChecker validates the first schemaSampleLines data rows of a file as
+// they are read, so that a changed layout fails loudly instead of every row
+// being skipped as malformed.
+type schemaChecker struct {
+	schema  rawSchema
+	file    string
+	line    int // Lines seen so far
+	checked int // Data rows checked so far
+}
+
+func newSchemaChecker(s rawSchema, file string) *schemaChecker {
+	return &schemaChecker{schema: s, file: filepath.Base(file)}
+}
+
+// check validates the next line of the file. Comment lines are only checked
+// when they are the schema's header; empty lines are ignored.
+func (c *schemaChecker) check(text string) error {
+	c.line++
+	if c.checked >= schemaSampleLines || text == "" {
+		return nil
+	}
+	if text[0] == '#' {
+		if c.schema.header != "" && strings.HasPrefix(text, c.schema.header) {
+			return c.checkColumns(strings.Split(text, "\t"), "header")
+		}
+		return nil
+	}
+	c.checked++
+	fields := strings.Split(text, "\t")
+	if err := c.checkColumns(fields, "row"); err != nil {
+		return err
+	}
+	if !c.schema.valid(fields) {
+		return fmt.Errorf("%s line %d: %w: unexpected values %q for %s schema %s",
+			c.file, c.line, ErrSchemaMismatch, strings.Join(fields[:min(len(fields), 6)], "\t"), c.schema.name, geonamesSchemaVersion)
+	}
+	return nil
+}
+
+func (c *schemaChecker) checkColumns(fields []string, what string) error {
+	if len(fields) != c.schema.columns {
+		return fmt.Errorf("%s line %d: %w: %s has %d columns, want %d for %s schema %s",
+			c.file, c.line, ErrSchemaMismatch, what, len(fields), c.schema.columns, c.schema.name, geonamesSchemaVersion)
+	}
+	return nil
+}
+
+// validateRawFile checks the layout of the raw file at path against s.
+func validateRawFile(path string, s rawSchema) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	c := newSchemaChecker(s, path)
+	scanner := bufio.NewScanner(f)
+	for c.checked < schemaSampleLines && scanner.Scan() {
+		if err := c.check(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,67 @@
+package geobed
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  rawSchema
+		lines   []string
+		wantErr string
+	}{
+		{"cities", citiesSchema, testCityRows, ""},
+		{"cities extra column", citiesSchema, []string{testCityRows[0] + "\tnew"}, "row has 20 columns, want 19"},
+		{"cities shifted columns", citiesSchema, []string{"London\t2643743" + testCityRows[0][len("2643743\tLondon"):]}, "unexpected values"},
+		{"countryInfo header", countryInfoSchema, []string{"# comment", "#ISO\tISO3\tISO-Numeric"}, "header has 3 columns"},
+		{"countryInfo row", countryInfoSchema, []string{"#ISO" + strings.Repeat("\tx", 18), "gb" + strings.Repeat("\tx", 18)}, "unexpected values"},
+		{"admin1", admin1Schema, []string{"US.TX\tTexas\tTexas\t4736286", "", "CA.08\tOntario\tOntario\t6093943"}, ""},
+		{"admin1 missing column", admin1Schema, []string{"US.TX\tTexas\t4736286"}, "line 1: geobed: unexpected data file schema: row has 3 columns, want 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newSchemaChecker(tt.schema, "/data/file.txt")
+			var err error
+			for _, line := range tt.lines {
+				if err = c.check(line); err != nil {
+					break
+				}
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("check() error = %v", err)
+			case tt.wantErr != "" && (!errors.Is(err, ErrSchemaMismatch) || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("check() error = %v, want ErrSchemaMismatch containing %q", err, tt.wantErr)
+			case err != nil && (!strings.HasPrefix(err.Error(), "file.txt line ") || !strings.Contains(err.Error(), geonamesSchemaVersion)):
+				t.Errorf("check() error = %v, want the file name and schema version", err)
+			}
+		})
+	}
+}
+
+func TestNewGeobed_SchemaMismatch(t *testing.T) {
+	dir := t.TempDir()
+	copyMetadataFiles(t, dir)
+	rows := make([]string, len(testCityRows))
+	for i, r := range testCityRows {
+		rows[i] = r + "\tnew column"
+	}
+	writeCitiesZip(t, filepath.Join(dir, "cities15000.zip"), rows)
+
+	_, err := NewGeobed(WithDataset(DatasetCities15000), WithOffline(), WithDataDir(dir), WithCacheDir(t.TempDir()))
+	if !errors.Is(err, ErrSchemaMismatch) || !strings.Contains(err.Error(), "cities15000.txt line 1") {
+		t.Errorf("NewGeobed() error = %v, want ErrSchemaMismatch naming cities15000.txt", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "admin1CodesASCII.txt"), []byte("US.TX,Texas\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateRawFile(filepath.Join(dir, "admin1CodesASCII.txt"), admin1Schema); !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("validateRawFile() error = %v, want ErrSchemaMismatch", err)
+	}
+}
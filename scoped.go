@@ -0,0 +1,55 @@
+package geobed
+
+// countryNameKey keys the composite index: an interned country code and a
+// name-index key.
+type countryNameKey struct {
+	country uint16
+	name    string
+}
+
+// countryNameIndex returns the name index split by country, building it
+// from nameIndex on first use. It lets GeocodeOptions.Country fetch only
+// the same-named cities of one country instead of every candidate
+// worldwide.
+func (g *GeoBed) countryNameIndex() map[countryNameKey][]int {
+	g.byCountryNameOnce.Do(func() {
+		g.byCountryName = make(map[countryNameKey][]int, len(g.nameIndex))
+		for name, idxs := range g.nameIndex {
+			for _, idx := range idxs {
+				k := countryNameKey{g.Cities[idx].country, name}
+				g.byCountryName[k] = append(g.byCountryName[k], idx)
+			}
+		}
+	})
+	return g.byCountryName
+}
+
+// nameLookup returns the name-index lookup for queries restricted to the
+// given ISO country code: nameIndex itself when country is empty, and
+// otherwise the country's part of countryNameIndex. An unknown country
+// matches nothing.
+func (g *GeoBed) nameLookup(country string) func(key string) []int {
+	if country == "" {
+		return func(key string) []int { return g.nameIndex[key] }
+	}
+	cc, ok := countryInterner.find(country)
+	if !ok {
+		return func(string) []int { return nil }
+	}
+	idx := g.countryNameIndex()
+	return func(key string) []int { return idx[countryNameKey{cc, key}] }
+}
+
+// filterCountry removes the candidates outside country, if set, from set.
+// Fuzzy scans collect candidates from the whole index; this restricts them
+// afterwards.
+func (g *GeoBed) filterCountry(set map[int]bool, country string) {
+	if country == "" {
+		return
+	}
+	for idx := range set {
+		if g.Cities[idx].Country() != country {
+			delete(set, idx)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package geobed
+
+import "testing"
+
+func TestGeocode_CountryOption(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query   string
+		opts    GeocodeOptions
+		city    string
+		country string
+	}{
+		{"Paris", GeocodeOptions{Country: "US"}, "Paris", "US"},
+		{"Paris", GeocodeOptions{Country: "fr"}, "Paris", "FR"},
+		{"London", GeocodeOptions{Country: "CA"}, "London", "CA"},
+		{"London", GeocodeOptions{Country: "CA", ExactCity: true}, "London", "CA"},
+		{"Londn", GeocodeOptions{Country: "CA", FuzzyDistance: 1}, "London", "CA"},
+		{"Reykjavik", GeocodeOptions{Country: "US"}, "", ""},
+		{"Paris", GeocodeOptions{Country: "XX"}, "", ""},
+	}
+	for _, tt := range tests {
+		c := g.GeocodeDetailed(tt.query, tt.opts).City
+		if c.City != tt.city || c.Country() != tt.country {
+			t.Errorf("GeocodeDetailed(%q, %+v) = %q, %q; want %q, %q", tt.query, tt.opts, c.City, c.Country(), tt.city, tt.country)
+		}
+	}
+}
+
+func TestCountryNameIndex(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Paris", Country: "FR", Latitude: 48.85, Longitude: 2.35},
+		{City: "Paris", Country: "US", Region: "TX", Latitude: 33.66, Longitude: -95.56},
+		{City: "Paris", Country: "US", Region: "TN", Latitude: 36.3, Longitude: -88.33},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.nameLookup("US")("paris"); len(got) != 2 {
+		t.Errorf("nameLookup(US)(paris) = %v, want the 2 US cities", got)
+	}
+	if got := g.nameLookup("")("paris"); len(got) != 3 {
+		t.Errorf("nameLookup()(paris) = %v, want all 3 cities", got)
+	}
+}
@@ -1,11 +1,12 @@
 package geobed
 
 import (
+	"slices"
 	"testing"
 )
 
 func TestScoringDisambiguation(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to create Geobed: %v", err)
 	}
@@ -318,4 +319,35 @@ func TestScoringDisambiguation(t *testing.T) {
 			}
 		})
 	})
+
+	// ──────────────────────────────────────────────
+	// Neighbouring countries
+	// ──────────────────────────────────────────────
+
+	t.Run("NeighbouringCountry", func(t *testing.T) {
+		tests := []struct {
+			query       string
+			wantCountry string
+		}{
+			{"Geneva, France", "CH"},      // Genève, not Geneva NY
+			{"Basel, France", "CH"},       // Across the border from Saint-Louis
+			{"Salzburg, Germany", "AT"},   // Close to the German border
+			{"Springfield, Mexico", "US"}, // No Springfield in Mexico
+			{"Paris, France", "FR"},       // Exact country match, neighbours unused
+		}
+		for _, tt := range tests {
+			t.Run(tt.query, func(t *testing.T) {
+				if got := g.Geocode(tt.query).Country(); got != tt.wantCountry {
+					t.Errorf("Geocode(%q).Country() = %q, want %q", tt.query, got, tt.wantCountry)
+				}
+			})
+		}
+
+		// Distant candidates lose the bonus but stay candidates.
+		if !slices.ContainsFunc(g.GeocodeAll("Geneva, France"), func(c GeocodeCandidate) bool {
+			return c.City.Country() == "US"
+		}) {
+			t.Error("GeocodeAll(Geneva, France) dropped Geneva, US")
+		}
+	})
 }
@@ -0,0 +1,43 @@
+package geobed
+
+// RecordSource identifies the data set a city record came from.
+type RecordSource uint8
+
+const (
+	SourceUnknown  RecordSource = iota // Loaded from a cache generated before sources were recorded
+	SourceGeonames                     // Geonames cities1000
+	SourceMaxMind                      // MaxMind world cities
+	SourceCustom                       // Supplemental places (WithPlacesFile)
+	SourceRecords                      // Records passed to NewGeobedFromRecords
+)
+
+// String returns the lowercase source name, e.g. "geonames".
+func (s RecordSource) String() string {
+	switch s {
+	case SourceGeonames:
+		return "geonames"
+	case SourceMaxMind:
+		return "maxmind"
+	case SourceCustom:
+		return "custom"
+	case SourceRecords:
+		return "records"
+	default:
+		return "unknown"
+	}
+}
+
+// Source returns the data set the city came from.
+func (c GeobedCity) Source() RecordSource {
+	return c.source
+}
+
+// SourceCounts returns the number of loaded cities per data source, which
+// helps debug how data sets were merged.
+func (g *GeoBed) SourceCounts() map[RecordSource]int {
+	counts := make(map[RecordSource]int)
+	for _, c := range g.Cities {
+		counts[c.source]++
+	}
+	return counts
+}
@@ -0,0 +1,93 @@
+package geobed
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestRecordSource_String(t *testing.T) {
+	for src, want := range map[RecordSource]string{
+		SourceUnknown:   "unknown",
+		SourceGeonames:  "geonames",
+		SourceMaxMind:   "maxmind",
+		SourceCustom:    "custom",
+		SourceRecords:   "records",
+		RecordSource(9): "unknown",
+	} {
+		if got := src.String(); got != want {
+			t.Errorf("RecordSource(%d).String() = %q, want %q", src, got, want)
+		}
+	}
+}
+
+func TestRecordSource_Parsers(t *testing.T) {
+	gn, ok := parseGeonamesCityLine("4671654\tAustin\tAustin\t\t30.26715\t-97.74306\tP\tPPLA\tUS\t\tTX\t453\t\t\t961855\t149\t158\tAmerica/Chicago\t2019-07-03")
+	if !ok || gn.Source != SourceGeonames {
+		t.Errorf("Geonames line: ok=%v Source=%v, want geonames", ok, gn.Source)
+	}
+	mm, ok := parseMaxMindCityFields([]string{"us", "austin", "Austin", "TX", "678368", "30.2669444", "-97.7427778"})
+	if !ok || mm.Source != SourceMaxMind {
+		t.Errorf("MaxMind fields: ok=%v Source=%v, want maxmind", ok, mm.Source)
+	}
+	p, err := parsePlaceLine("Mitte\t\tDE\t16\t52.52\t13.40")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Source = SourceCustom
+	if c := mustCity(t, p); c.Source() != SourceCustom || !c.IsPlace() {
+		t.Errorf("place Source() = %v, IsPlace() = %v", c.Source(), c.IsPlace())
+	}
+}
+
+func TestRecordSource_SurvivesCache(t *testing.T) {
+	g := &GeoBed{
+		config: &GeobedConfig{CacheDir: t.TempDir()},
+		Cities: Cities{
+			mustCity(t, geobedCityGob{City: "A", Country: "US", Source: SourceGeonames}),
+			mustCity(t, geobedCityGob{City: "B", Country: "US", Source: SourceMaxMind}),
+		},
+		nameIndex: map[string][]int{"a": {0}, "b": {1}},
+	}
+	if err := g.store(); err != nil {
+		t.Fatal(err)
+	}
+	cities, err := loadCityCacheDir(g.config.CacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cities) != 2 || cities[0].Source() != SourceGeonames || cities[1].Source() != SourceMaxMind {
+		t.Errorf("sources after round trip: %v, %v", cities[0].Source(), cities[1].Source())
+	}
+
+	loaded := &GeoBed{Cities: cities}
+	counts := loaded.SourceCounts()
+	if counts[SourceGeonames] != 1 || counts[SourceMaxMind] != 1 || len(counts) != 2 {
+		t.Errorf("SourceCounts() = %v", counts)
+	}
+}
+
+func TestSourceCounts_EmbeddedCache(t *testing.T) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := g.SourceCounts()
+	if counts[SourceUnknown] != 0 || counts[SourceGeonames] == 0 {
+		t.Errorf("SourceCounts() = %v, want every embedded city attributed", counts)
+	}
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total != len(g.Cities) {
+		t.Errorf("SourceCounts() sums to %d, want %d", total, len(g.Cities))
+	}
+}
+
+func TestGeobedCity_SizeUnchanged(t *testing.T) {
+	// source fits in padding after geonameID; growing the struct costs
+	// ~1MB per 8 bytes across the full dataset.
+	if got := unsafe.Sizeof(GeobedCity{}); got != 56 {
+		t.Errorf("GeobedCity size = %d bytes, want 56", got)
+	}
+}
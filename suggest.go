@@ -0,0 +1,178 @@
+package geobed
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// SuggestOptions tunes Suggest.
+type SuggestOptions struct {
+	Limit        int  // Maximum number of cities returned (0 = 10)
+	MaxEdits     int  // Typos tolerated in the prefix (0 = exact prefix); capped at one per 3 runes typed
+	Alphabetical bool // Order equally close names by name (see WithCollation) rather than population
+}
+
+// defaultSuggestLimit is the number of suggestions when SuggestOptions.Limit
+// is unset.
+const defaultSuggestLimit = 10
+
+// Suggest returns cities whose primary or alternate name starts with
+// prefix, for autocomplete. With MaxEdits, names whose start is within that
+// many edits (insertions, deletions, substitutions) of prefix also match,
+// so "munick" suggests Munich. Closer names come first, then more populous
+// cities, or with Alphabetical, names in collation order. Near-duplicates
+// are left out with WithDedupe. Unlike Geocode with FuzzyDistance, no query
+// parsing or scoring takes place: prefix is matched against index names as
+// typed, normalized like NamesWithPrefix.
+//
+// Edits are capped at one per three runes of prefix, as a short prefix
+// with typos matches most of the index. The walk over the sorted index
+// keys shares work between keys with a common start and skips every key
+// under a start already too far from prefix, so it visits tens of
+// thousands of keys rather than the whole index and is several times
+// faster than Geocode with FuzzyDistance.
+func (g *GeoBed) Suggest(prefix string, opts SuggestOptions) []GeobedCity {
+	if g.config != nil && g.config.Normalizer != nil {
+		prefix = g.config.Normalizer.Normalize(prefix)
+	} else {
+		prefix = toLower(prefix)
+	}
+	q := []rune(prefix)
+	if len(q) == 0 {
+		return nil
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	maxEdits := min(max(opts.MaxEdits, 0), len(q)/3)
+
+	// Keep each city's smallest distance over all its names.
+	dist := make(map[int]int)
+	g.walkPrefixKeys(q, maxEdits, func(key string, d int) {
+		for _, idx := range g.nameIndex[key] {
+			if old, ok := dist[idx]; !ok || d < old {
+				dist[idx] = d
+			}
+		}
+	})
+
+	idxs := make([]int, 0, len(dist))
+	for idx := range dist {
+		idxs = append(idxs, idx)
+	}
+	var compare func(a, b string) int
+	if opts.Alphabetical {
+		compare = g.nameCompare()
+	}
+	sort.Slice(idxs, func(i, j int) bool {
+		a, b := idxs[i], idxs[j]
+		if dist[a] != dist[b] {
+			return dist[a] < dist[b]
+		}
+		if compare != nil {
+			if c := compare(g.Cities[a].City, g.Cities[b].City); c != 0 {
+				return c < 0
+			}
+		}
+		if g.Cities[a].Population != g.Cities[b].Population {
+			return g.Cities[a].Population > g.Cities[b].Population
+		}
+		return a < b
+	})
+
+	var out []GeobedCity
+	d := g.deduper()
+	for _, idx := range idxs {
+		c := g.Cities[idx]
+		if g.config != nil && g.config.ResultFilter != nil && !g.config.ResultFilter(c) {
+			continue
+		}
+		if !d.keep(c) {
+			continue
+		}
+		if out = append(out, c); len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// walkPrefixKeys calls fn for every sorted name index key that has a
+// start within maxEdits edits of q, with the smallest such distance. It
+// walks the keys as a trie: row t holds the edit distances between the
+// first t runes of the key and each prefix of q, so consecutive keys reuse
+// the rows of their common start. Distances down a row's column never drop
+// below the row's minimum, so once that minimum is no better than the best
+// distance found so far, or exceeds maxEdits, every key with that start has
+// the same result and the keys are skipped together.
+func (g *GeoBed) walkPrefixKeys(q []rune, maxEdits int, fn func(key string, dist int)) {
+	keys := g.sortedNameKeys()
+	m := len(q)
+	w := m + 1
+
+	// rows is a flat buffer of rows of w entries; row 0 compares the empty
+	// start with each prefix of q.
+	rows := make([]int, w, 16*w)
+	for j := range w {
+		rows[j] = j
+	}
+	best := []int{m} // best[t]: min over rows 0..t of the full-q distance
+	starts := g.sharedKeyStarts()
+
+	for i := 0; i < len(keys); {
+		// Rows beyond the start shared with the previous key, or beyond
+		// those computed for it, are recomputed; runes are only decoded as
+		// far as the walk goes down the key.
+		shared := min(int(starts[i]), len(best)-1)
+		rows, best = rows[:(shared+1)*w], best[:shared+1]
+		rest := keys[i]
+		for range shared {
+			_, n := utf8.DecodeRuneInString(rest)
+			rest = rest[n:]
+		}
+
+		stop, depth := -1, shared
+		for t := shared; rest != ""; t++ {
+			r, n := utf8.DecodeRuneInString(rest)
+			rest = rest[n:]
+			last := rows[t*w : (t+1)*w]
+			rows = append(rows, t+1)
+			low := t + 1
+			for j := 1; j <= m; j++ {
+				cost := 1
+				if q[j-1] == r {
+					cost = 0
+				}
+				d := min(last[j]+1, rows[len(rows)-1]+1, last[j-1]+cost)
+				rows = append(rows, d)
+				low = min(low, d)
+			}
+			best = append(best, min(best[t], rows[len(rows)-1]))
+			depth = t + 1
+			if low > maxEdits || low >= best[t+1] {
+				stop = t + 1
+				break
+			}
+		}
+
+		if stop < 0 {
+			if d := best[depth]; d <= maxEdits {
+				fn(keys[i], d)
+			}
+			i++
+			continue
+		}
+		// Every key with this start has the distance found so far.
+		next := i + 1
+		for next < len(keys) && int(starts[next]) >= stop {
+			next++
+		}
+		if d := best[stop]; d <= maxEdits {
+			for _, key := range keys[i:next] {
+				fn(key, d)
+			}
+		}
+		i = next
+	}
+}
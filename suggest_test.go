@@ -0,0 +1,95 @@
+package geobed
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/agnivade/levenshtein"
+)
+
+func TestSuggest(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Munich", CityAlt: "München", Country: "DE", Region: "02", Latitude: 48.13743, Longitude: 11.57549, Population: 1260391},
+		{City: "Munster", Country: "DE", Region: "07", Latitude: 51.96236, Longitude: 7.62571, Population: 270184},
+		{City: "Mumbai", Country: "IN", Region: "16", Latitude: 19.07283, Longitude: 72.88261, Population: 12691836},
+		{City: "Nice", Country: "FR", Region: "B8", Latitude: 43.70313, Longitude: 7.26608, Population: 338620},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(cities []GeobedCity) []string {
+		var out []string
+		for _, c := range cities {
+			out = append(out, c.City)
+		}
+		return out
+	}
+	tests := []struct {
+		prefix string
+		opts   SuggestOptions
+		want   []string
+	}{
+		{"Mun", SuggestOptions{}, []string{"Munich", "Munster"}},
+		{"mün", SuggestOptions{}, []string{"Munich"}},
+		{"munick", SuggestOptions{}, nil},
+		{"munick", SuggestOptions{MaxEdits: 1}, []string{"Munich"}},
+		{"mumich", SuggestOptions{MaxEdits: 1}, []string{"Munich"}},
+		{"mu", SuggestOptions{MaxEdits: 2}, []string{"Mumbai", "Munich", "Munster"}}, // No edits for short prefixes
+		{"Mun", SuggestOptions{Limit: 1}, []string{"Munich"}},
+		{"", SuggestOptions{}, nil},
+	}
+	for _, tt := range tests {
+		if got := names(g.Suggest(tt.prefix, tt.opts)); !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q, %+v) = %v, want %v", tt.prefix, tt.opts, got, tt.want)
+		}
+	}
+}
+
+// TestWalkPrefixKeys checks the pruned walk against the distance of every
+// start of every key, computed one by one.
+func TestWalkPrefixKeys(t *testing.T) {
+	var records []CityRecord
+	for _, name := range []string{"Munich", "Munster", "Mumbai", "Muncie", "Unna", "Nice", "Minsk", "Mûnich", "Amun", "Mu"} {
+		records = append(records, CityRecord{City: name, Country: "DE", Region: "02"})
+	}
+	g, err := NewGeobedFromRecords(records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range []string{"munick", "mun", "unich", "mumbia"} {
+		for maxEdits := range 3 {
+			got := map[string]int{}
+			g.walkPrefixKeys([]rune(q), maxEdits, func(key string, d int) { got[key] = d })
+
+			want := map[string]int{}
+			for _, key := range g.sortedNameKeys() {
+				k := []rune(key)
+				best := len(q)
+				for n := range len(k) + 1 {
+					best = min(best, levenshtein.ComputeDistance(q, string(k[:n])))
+				}
+				if best <= maxEdits {
+					want[key] = best
+				}
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("walkPrefixKeys(%q, %d) = %v, want %v", q, maxEdits, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkSuggest_Typo(b *testing.B) {
+	g, err := GetDefaultGeobed()
+	if err != nil {
+		b.Fatal(err)
+	}
+	g.Suggest("munich", SuggestOptions{}) // Sort the index keys
+	b.ResetTimer()
+	for b.Loop() {
+		g.Suggest("munick", SuggestOptions{MaxEdits: 1})
+	}
+}
@@ -0,0 +1,86 @@
+package geobed
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DisputedTerritory is an area whose country label is contested. Geonames
+// files its cities under Country; a TerritoryPolicy can report one of the
+// Claimants instead.
+type DisputedTerritory struct {
+	Name      string   // Policy key, e.g. "Crimea"
+	Country   string   // Country code in the Geonames data
+	Regions   []string // Region codes within Country (nil = the whole country)
+	Claimants []string // Other country codes a policy may report
+}
+
+// disputedTerritories is the policy table. Entries are kept to areas where
+// the label is a frequent compliance question; extend it as needed.
+var disputedTerritories = []DisputedTerritory{
+	{Name: "Crimea", Country: "UA", Regions: []string{"11", "20"}, Claimants: []string{"RU"}},
+	{Name: "Western Sahara", Country: "EH", Claimants: []string{"MA"}},
+	{Name: "Kosovo", Country: "XK", Claimants: []string{"RS"}},
+}
+
+// DisputedTerritories returns the territories a TerritoryPolicy can label.
+func DisputedTerritories() []DisputedTerritory {
+	return slices.Clone(disputedTerritories)
+}
+
+// TerritoryPolicy maps a DisputedTerritory name to the country code to
+// report for its cities: the territory's Geonames Country or one of its
+// Claimants. Territories not in the policy keep the Geonames code.
+type TerritoryPolicy map[string]string
+
+// WithTerritoryPolicy relabels the cities of disputed territories, e.g.
+// TerritoryPolicy{"Western Sahara": "MA"} makes Laayoune report country MA.
+// The relabeling applies to Country() in forward and reverse results and to
+// country matching in queries. Relabeled cities lose their region code, as
+// a Geonames admin1 code of one country names an unrelated division of
+// another (UA "11" is Crimea, RU "11" is not): Region() is empty and no
+// admin division is reported for them. NewGeobed fails if the policy names
+// an unknown territory or country.
+func WithTerritoryPolicy(p TerritoryPolicy) Option {
+	return func(c *GeobedConfig) {
+		c.Territories = p
+	}
+}
+
+// validate checks that every entry names a known territory and one of its
+// permitted country codes.
+func (p TerritoryPolicy) validate() error {
+	for name, code := range p {
+		i := slices.IndexFunc(disputedTerritories, func(t DisputedTerritory) bool { return t.Name == name })
+		if i < 0 {
+			return fmt.Errorf("territory policy: unknown territory %q", name)
+		}
+		t := disputedTerritories[i]
+		if code != t.Country && !slices.Contains(t.Claimants, code) {
+			return fmt.Errorf("territory policy: %q cannot be labeled %q (want %s or one of %v)", name, code, t.Country, t.Claimants)
+		}
+	}
+	return nil
+}
+
+// applyTerritoryPolicy relabels the loaded cities according to policy and
+// clears their region codes.
+func (g *GeoBed) applyTerritoryPolicy(p TerritoryPolicy) error {
+	for _, t := range disputedTerritories {
+		code, ok := p[t.Name]
+		if !ok || code == t.Country {
+			continue
+		}
+		to, err := internCountry(code)
+		if err != nil {
+			return err
+		}
+		for i := range g.Cities {
+			c := &g.Cities[i]
+			if c.Country() == t.Country && (t.Regions == nil || slices.Contains(t.Regions, c.Region())) {
+				c.country, c.region = to, 0
+			}
+		}
+	}
+	return nil
+}
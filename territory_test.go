@@ -0,0 +1,87 @@
+package geobed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerritoryPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		policy  TerritoryPolicy
+		wantErr string
+	}{
+		{nil, ""},
+		{TerritoryPolicy{"Crimea": "RU", "Kosovo": "XK"}, ""},
+		{TerritoryPolicy{"Atlantis": "GR"}, "unknown territory"},
+		{TerritoryPolicy{"Western Sahara": "FR"}, "cannot be labeled"},
+	}
+	for _, tt := range tests {
+		err := tt.policy.validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("validate(%v) = %v, want nil", tt.policy, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("validate(%v) = %v, want error containing %q", tt.policy, err, tt.wantErr)
+		}
+	}
+
+	if _, err := NewGeobed(WithTerritoryPolicy(TerritoryPolicy{"Atlantis": "GR"})); err == nil {
+		t.Error("NewGeobed with an invalid territory policy should fail")
+	}
+}
+
+// territoryCities holds cities in disputed territories and their neighbours.
+var territoryCities = []CityRecord{
+	{City: "Simferopol", Country: "UA", Region: "11", Latitude: 44.95719, Longitude: 34.11079, Population: 336460},
+	{City: "Sevastopol", Country: "UA", Region: "20", Latitude: 44.58883, Longitude: 33.5224, Population: 416263},
+	{City: "Kyiv", Country: "UA", Region: "12", Latitude: 50.45466, Longitude: 30.5238, Population: 2797553},
+	{City: "Laayoune", Country: "EH", Latitude: 27.1418, Longitude: -13.18797, Population: 217732},
+	{City: "Rabat", Country: "MA", Region: "07", Latitude: 34.01325, Longitude: -6.83255, Population: 1655753},
+	{City: "Pristina", Country: "XK", Region: "01", Latitude: 42.67272, Longitude: 21.16688, Population: 550000},
+}
+
+func TestWithTerritoryPolicy(t *testing.T) {
+	g, err := NewGeobedFromRecords(territoryCities, nil, WithTerritoryPolicy(TerritoryPolicy{
+		"Crimea":         "RU",
+		"Western Sahara": "MA",
+		"Kosovo":         "XK", // Same as Geonames: no change
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query, wantCountry string
+	}{
+		{"Simferopol", "RU"},
+		{"Sevastopol", "RU"},
+		{"Laayoune", "MA"},
+		{"Laayoune, Morocco", "MA"},
+		{"Pristina", "XK"},
+		{"Kyiv", "UA"}, // Rest of Ukraine unaffected
+	}
+	for _, tt := range tests {
+		if got := g.Geocode(tt.query); got.Country() != tt.wantCountry {
+			t.Errorf("Geocode(%q) = %s, %s; want country %s", tt.query, got.City, got.Country(), tt.wantCountry)
+		}
+	}
+	if got := g.ReverseGeocode(44.9521, 34.1024); got.City != "Simferopol" || got.Country() != "RU" {
+		t.Errorf("ReverseGeocode(Simferopol) = %s, %s; want Simferopol, RU", got.City, got.Country())
+	}
+	// UA admin1 codes mean other divisions under RU, so none is kept.
+	if got := g.Geocode("Simferopol"); got.Region() != "" || g.getAdminDivisionName(got.Country(), got.Region()) != "" {
+		t.Errorf("Geocode(Simferopol) region = %q, want none after relabeling", got.Region())
+	}
+
+	// Without a policy the Geonames labels are kept.
+	def, err := NewGeobedFromRecords(territoryCities, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := def.Geocode("Simferopol"); got.Country() != "UA" {
+		t.Errorf("default Geocode(Simferopol) country = %s, want UA", got.Country())
+	}
+	if got := def.Geocode("Laayoune"); got.Country() != "EH" {
+		t.Errorf("default Geocode(Laayoune) country = %s, want EH", got.Country())
+	}
+}
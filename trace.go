@@ -0,0 +1,84 @@
+package geobed
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"slices"
+)
+
+// WithScoreTrace writes a scoring trace for every forward geocode to w as
+// JSON lines, one ScoreTrace object per query. Traces record each scored
+// candidate and the final pick, so the ranker can be tuned offline against
+// labeled queries (e.g., the cases read by ReadEvalCases).
+//
+// Writes are serialized; w need not be safe for concurrent use. Write
+// errors are ignored so tracing never affects geocoding results. Tracing
+// costs an allocation per candidate and is meant for analysis runs, not
+// production traffic.
+func WithScoreTrace(w io.Writer) Option {
+	return func(c *GeobedConfig) {
+		c.ScoreTrace = w
+	}
+}
+
+// ScoreTrace is one line of the scoring trace enabled by WithScoreTrace.
+type ScoreTrace struct {
+	Query      ParsedQuery      `json:"query"`
+	Candidates []TraceCandidate `json:"candidates"` // Sorted by score, best first
+	Selected   string           `json:"selected"`   // ID of the returned city ("" = no result)
+	FastPath   bool             `json:"fast_path"`  // Resolved by the "City, ST" shortcut without scoring
+	Partial    bool             `json:"partial"`    // Deadline cut matching short
+}
+
+// TraceCandidate is a scored candidate in a ScoreTrace. Candidates scoring
+// zero are omitted, as they can never be returned.
+type TraceCandidate struct {
+	ID         string `json:"id"`
+	City       string `json:"city"`
+	Country    string `json:"country"`
+	Region     string `json:"region"`
+	Population int32  `json:"population"`
+	Score      int    `json:"score"`
+}
+
+// traceScores writes a ScoreTrace for one query if tracing is enabled.
+// scores maps city indices to final scores.
+func (g *GeoBed) traceScores(q ParsedQuery, scores map[int]int, selected int, fastPath, partial bool) {
+	if g.config == nil || g.config.ScoreTrace == nil {
+		return
+	}
+	t := ScoreTrace{Query: q, FastPath: fastPath, Partial: partial}
+	for k, score := range scores {
+		c := g.Cities[k]
+		t.Candidates = append(t.Candidates, TraceCandidate{
+			ID:         c.ID(),
+			City:       c.City,
+			Country:    c.Country(),
+			Region:     c.Region(),
+			Population: c.Population,
+			Score:      score,
+		})
+	}
+	slices.SortFunc(t.Candidates, func(a, b TraceCandidate) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Population, a.Population); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if selected >= 0 {
+		t.Selected = g.Cities[selected].ID()
+	}
+
+	b, err := json.Marshal(t)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	g.traceMu.Lock()
+	defer g.traceMu.Unlock()
+	g.config.ScoreTrace.Write(b)
+}
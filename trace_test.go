@@ -0,0 +1,55 @@
+package geobed
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWithScoreTrace(t *testing.T) {
+	var buf bytes.Buffer
+	g, err := NewGeobedFromRecords(fixtureCities, nil, WithScoreTrace(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paris := g.Geocode("Paris, France")
+	austin := g.Geocode("Austin, TX")
+	g.Geocode("Zxqwvbn")
+
+	var traces []ScoreTrace
+	sc := bufio.NewScanner(&buf)
+	sc.Buffer(nil, 1<<24)
+	for sc.Scan() {
+		var tr ScoreTrace
+		if err := json.Unmarshal(sc.Bytes(), &tr); err != nil {
+			t.Fatalf("invalid trace line %q: %v", sc.Text(), err)
+		}
+		traces = append(traces, tr)
+	}
+	if len(traces) != 3 {
+		t.Fatalf("got %d trace lines, want 3", len(traces))
+	}
+
+	p := traces[0]
+	if p.Query.Raw != "Paris, France" || p.Query.Country != "FR" || p.FastPath {
+		t.Errorf("Paris trace query = %+v, fast path %v", p.Query, p.FastPath)
+	}
+	if len(p.Candidates) < 2 || p.Selected != paris.ID() || p.Candidates[0].ID != paris.ID() {
+		t.Errorf("Paris trace: selected %q, top %+v; want %q first", p.Selected, p.Candidates[0], paris.ID())
+	}
+	for i := 1; i < len(p.Candidates); i++ {
+		if p.Candidates[i].Score > p.Candidates[i-1].Score {
+			t.Errorf("candidates not sorted by score at %d", i)
+			break
+		}
+	}
+
+	if a := traces[1]; !a.FastPath || a.Selected != austin.ID() {
+		t.Errorf("Austin trace: fast path %v, selected %q; want fast path selecting %q", a.FastPath, a.Selected, austin.ID())
+	}
+	if z := traces[2]; z.Selected != "" || len(z.Candidates) != 0 {
+		t.Errorf("no-match trace = %+v", z)
+	}
+}
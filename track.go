@@ -0,0 +1,76 @@
+package geobed
+
+// trackMinPoints is how many consecutive points a GPS track must spend at
+// another city before ReverseGeocodeTrack starts a new segment. Shorter
+// excursions, typically a trace running along a city boundary, stay part of
+// the current segment.
+const trackMinPoints = 3
+
+// TrackSegment is a run of consecutive track points attributed to one city.
+type TrackSegment struct {
+	City  GeobedCity // Zero value for points out of reverse geocoding range
+	Start int        // Index of the first point in the track
+	End   int        // Index after the last point
+}
+
+// ReverseGeocodeTrack summarizes a GPS track, points in the order they
+// were recorded, as the timeline of cities visited: each segment covers
+// the consecutive points ReverseGeocode places in its city. To avoid
+// flapping where a trace follows a city boundary, a new segment only
+// starts once trackMinPoints consecutive points agree on another city; it
+// then begins at the first of them, and shorter excursions are absorbed
+// into the surrounding segment. Points out of range or invalid form
+// segments with the zero city under the same rule. Cities are looked up as
+// by NearestCities.
+func (g *GeoBed) ReverseGeocodeTrack(points []LatLng, opts ...ReverseGeocodeOptions) []TrackSegment {
+	if len(points) == 0 {
+		return nil
+	}
+	cities := g.NearestCities(points, opts...)
+
+	segments := []TrackSegment{{City: cities[0]}}
+	cur := &segments[0]
+	runStart := 0 // First point of the latest run of equal cities
+	for i := 1; i < len(cities); i++ {
+		if cities[i] != cities[i-1] {
+			runStart = i
+		}
+		if cities[i] == cur.City || i-runStart+1 < trackMinPoints {
+			continue
+		}
+		cur.End = runStart
+		segments = append(segments, TrackSegment{City: cities[i], Start: runStart})
+		cur = &segments[len(segments)-1]
+	}
+	cur.End = len(points)
+	return segments
+}
+
+// BorderCrossing is a change of country along a track.
+type BorderCrossing struct {
+	From  string // ISO code of the country left
+	To    string // ISO code of the country entered
+	Index int    // First point in To
+}
+
+// BorderCrossings returns the country changes along a GPS track, e.g. for
+// roaming or telematics, in track order. It follows the segments of
+// ReverseGeocodeTrack, so brief excursions across a border do not count.
+// Stretches out of reverse geocoding range, such as a sea crossing, are
+// bridged: a ferry from Calais to Dover is one crossing from FR to GB,
+// entered at the first point placed in GB.
+func (g *GeoBed) BorderCrossings(points []LatLng, opts ...ReverseGeocodeOptions) []BorderCrossing {
+	var crossings []BorderCrossing
+	last := ""
+	for _, s := range g.ReverseGeocodeTrack(points, opts...) {
+		iso := s.City.Country()
+		if iso == "" {
+			continue
+		}
+		if last != "" && iso != last {
+			crossings = append(crossings, BorderCrossing{From: last, To: iso, Index: s.Start})
+		}
+		last = iso
+	}
+	return crossings
+}
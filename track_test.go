@@ -0,0 +1,79 @@
+package geobed
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestReverseGeocodeTrack(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Alpha", Country: "DE", Latitude: 0, Longitude: 0, Population: 50_000},
+		{City: "Beta", Country: "DE", Latitude: 0, Longitude: 0.2, Population: 50_000},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, far := LatLng{0, 0.01}, LatLng{0, 0.19}, LatLng{20, 20}
+
+	// A boundary excursion to Beta, a stay there, and a short return at
+	// the end; then a gap out of range.
+	track := []LatLng{a, a, a, a, b, a, a, b, b, b, b, a, a}
+	want := []trackSpan{{"Alpha", 0, 7}, {"Beta", 7, 13}}
+	checkTrack(t, g.ReverseGeocodeTrack(track), want)
+
+	track = []LatLng{b, b, far, far, far, {math.NaN(), 0}, b}
+	want = []trackSpan{{"Beta", 0, 2}, {"", 2, 7}}
+	checkTrack(t, g.ReverseGeocodeTrack(track), want)
+
+	if got := g.ReverseGeocodeTrack(nil); got != nil {
+		t.Errorf("ReverseGeocodeTrack(nil) = %v, want nil", got)
+	}
+}
+
+func TestBorderCrossings(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Calais", Country: "FR", Region: "32", Latitude: 50.95194, Longitude: 1.85635, Population: 75_961},
+		{City: "Dover", Country: "GB", Region: "ENG", Latitude: 51.12598, Longitude: 1.31257, Population: 31_022},
+		{City: "Lille", Country: "FR", Region: "32", Latitude: 50.63297, Longitude: 3.05858, Population: 234_475},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lille, calais, dover := LatLng{50.63, 3.06}, LatLng{50.95, 1.86}, LatLng{51.13, 1.31}
+
+	// Lille to Calais stays in France; the one point in Dover is too brief
+	// to count, the later stay is not.
+	track := []LatLng{lille, lille, lille, calais, calais, calais, dover, calais, calais, dover, dover, dover}
+	got := g.BorderCrossings(track)
+	if want := []BorderCrossing{{From: "FR", To: "GB", Index: 9}}; !slices.Equal(got, want) {
+		t.Errorf("BorderCrossings() = %+v, want %+v", got, want)
+	}
+	// Out-of-range stretches are bridged.
+	sea := LatLng{45, -20}
+	ferry := append(slices.Clone(track[:6]), sea, sea, sea, dover, dover, dover)
+	if got, want := g.BorderCrossings(ferry), []BorderCrossing{{From: "FR", To: "GB", Index: 9}}; !slices.Equal(got, want) {
+		t.Errorf("BorderCrossings() across the sea = %+v, want %+v", got, want)
+	}
+	if got := g.BorderCrossings(track[:6]); got != nil {
+		t.Errorf("BorderCrossings() within France = %+v, want none", got)
+	}
+}
+
+// trackSpan is an expected TrackSegment by city name.
+type trackSpan struct {
+	city       string
+	start, end int
+}
+
+func checkTrack(t *testing.T, got []TrackSegment, want []trackSpan) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("ReverseGeocodeTrack() = %+v, want %d segments", got, len(want))
+	}
+	for i, w := range want {
+		if s := got[i]; s.City.City != w.city || s.Start != w.start || s.End != w.end {
+			t.Errorf("segment %d = %q [%d, %d), want %q [%d, %d)", i, s.City.City, s.Start, s.End, w.city, w.start, w.end)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package geobed
+
+import "fmt"
+
+// CountryRules switches off query-parsing heuristics for one country. The
+// zero value keeps every heuristic.
+type CountryRules struct {
+	// NoAdminCodes stops 2-3 character query tokens from being read as the
+	// country's admin1 codes. Useful for countries whose Geonames codes are
+	// numeric ("08" is Ontario) and so collide with house numbers and
+	// postal fragments rather than what users write.
+	NoAdminCodes bool `json:"noAdminCodes"`
+
+	// NoAdminNames stops query parts from being read as the names of the
+	// country's admin divisions, e.g. where division names double as
+	// common city names.
+	NoAdminNames bool `json:"noAdminNames"`
+}
+
+// CountryTuning maps ISO 3166 alpha-2 country codes to the rules for that
+// country. The JSON tags let deployments keep the table in a configuration
+// file:
+//
+//	{"CA": {"noAdminCodes": true}, "GB": {"noAdminNames": true}}
+type CountryTuning map[string]CountryRules
+
+// WithCountryTuning applies per-country parsing rules, so heuristics that
+// misfire for a country's data can be switched off without code changes.
+// NewGeobed fails if a key is not an uppercase two-letter country code.
+func WithCountryTuning(t CountryTuning) Option {
+	return func(c *GeobedConfig) {
+		c.Tuning = t
+	}
+}
+
+// validate checks that every key looks like an ISO 3166 alpha-2 code.
+func (t CountryTuning) validate() error {
+	for cc := range t {
+		if len(cc) != 2 || cc[0] < 'A' || cc[0] > 'Z' || cc[1] < 'A' || cc[1] > 'Z' {
+			return fmt.Errorf("country tuning: %q is not an ISO 3166 alpha-2 code", cc)
+		}
+	}
+	return nil
+}
+
+// countryRules returns the configured rules for country.
+func (g *GeoBed) countryRules(country string) CountryRules {
+	if g.config == nil {
+		return CountryRules{}
+	}
+	return g.config.Tuning[country]
+}
@@ -0,0 +1,50 @@
+package geobed
+
+import "testing"
+
+func TestCountryTuning_Validate(t *testing.T) {
+	if err := (CountryTuning{"CA": {NoAdminCodes: true}}).validate(); err != nil {
+		t.Errorf("validate() = %v, want nil", err)
+	}
+	for _, cc := range []string{"ca", "CAN", ""} {
+		if err := (CountryTuning{cc: {}}).validate(); err == nil {
+			t.Errorf("validate() accepted key %q", cc)
+		}
+	}
+}
+
+func TestCountryTuning(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Toronto", Country: "CA", Region: "08", Latitude: 43.70643, Longitude: -79.39864, Population: 2600000},
+		{City: "London", Country: "CA", Region: "08", Latitude: 42.98339, Longitude: -81.23304, Population: 346765},
+		{City: "London", Country: "GB", Region: "ENG", Latitude: 51.50853, Longitude: -0.12574, Population: 8961989},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parse := func(q string) ParsedLocation {
+		t.Helper()
+		loc, err := g.ParseLocation(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loc
+	}
+
+	// Geonames codes Canadian provinces numerically; Ontario is "08".
+	if loc := parse("Toronto, 08, Canada"); loc.Country != "CA" || loc.Admin != "08" {
+		t.Fatalf("ParseLocation(Toronto, 08, Canada) = %+v, want CA/08", loc)
+	}
+	if loc := parse("London, Ontario"); loc.Country != "CA" || loc.Admin == "" {
+		t.Fatalf("ParseLocation(London, Ontario) = %+v, want CA and an admin", loc)
+	}
+
+	g.config.Tuning = CountryTuning{"CA": {NoAdminCodes: true, NoAdminNames: true}}
+	if loc := parse("Toronto, 08, Canada"); loc.Admin != "" {
+		t.Errorf("ParseLocation(Toronto, 08, Canada) with NoAdminCodes = %+v, want no admin", loc)
+	}
+	if loc := parse("London, Ontario"); loc.Admin != "" {
+		t.Errorf("ParseLocation(London, Ontario) with NoAdminNames = %+v, want no admin", loc)
+	}
+}
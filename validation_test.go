@@ -14,17 +14,17 @@ func TestValidation(t *testing.T) {
 
 // TestDataIntegrity checks that the loaded data meets minimum thresholds.
 func TestDataIntegrity(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to load geobed: %v", err)
 	}
 
-	if len(g.Cities) < minCityCount {
-		t.Errorf("City count %d is below minimum %d", len(g.Cities), minCityCount)
+	if g.CityCount() < minCityCount {
+		t.Errorf("City count %d is below minimum %d", g.CityCount(), minCityCount)
 	}
 
-	if len(g.Countries) < minCountryCount {
-		t.Errorf("Country count %d is below minimum %d", len(g.Countries), minCountryCount)
+	if g.CountryCountLoaded() < minCountryCount {
+		t.Errorf("Country count %d is below minimum %d", g.CountryCountLoaded(), minCountryCount)
 	}
 
 	// Check that we have cities on all continents (basic global coverage)
@@ -47,7 +47,7 @@ func TestDataIntegrity(t *testing.T) {
 
 // TestKnownCitiesGeocode validates that well-known cities geocode correctly.
 func TestKnownCitiesGeocode(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to load geobed: %v", err)
 	}
@@ -67,7 +67,7 @@ func TestKnownCitiesGeocode(t *testing.T) {
 
 // TestKnownCoordsReverseGeocode validates that known coordinates reverse geocode correctly.
 func TestKnownCoordsReverseGeocode(t *testing.T) {
-	g, err := NewGeobed()
+	g, err := sharedGeobed()
 	if err != nil {
 		t.Fatalf("Failed to load geobed: %v", err)
 	}
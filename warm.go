@@ -0,0 +1,31 @@
+package geobed
+
+// Warm builds the indexes that are otherwise built on first use and
+// geocodes queries once, so the first user requests after start-up do not
+// pay for them. Call it before serving traffic, for example with the most
+// frequent queries from recent logs:
+//
+//   - the S2 cell index when WithInitProfile(FastStart) defers it;
+//   - the length buckets used by fuzzy scans, the sorted keys used by
+//     NamesWithPrefix, the bounding boxes that check query hints, and the
+//     population rankings used by major-city lookups and LargestCities, the
+//     region index of CitiesInRegion, and the per-country name index used
+//     by GeocodeOptions.Country;
+//   - the parsed-query cache (WithQueryCache) and, when opts sets a
+//     FuzzyDistance, the fuzzy scan cache (WithFuzzyCache) for queries.
+//
+// opts are passed to GeocodeDetailed for every query. Results are discarded.
+func (g *GeoBed) Warm(queries []string, opts ...GeocodeOptions) {
+	g.ensureCellIndex()
+	g.keysByLength()
+	g.sortedNameKeys()
+	g.hintBounds("US", "")
+	g.citiesByPopulation()
+	g.citiesByCountry()
+	g.citiesByRegion()
+	g.countryNameIndex()
+
+	for _, q := range queries {
+		g.GeocodeDetailed(q, opts...)
+	}
+}
@@ -0,0 +1,29 @@
+package geobed
+
+import "testing"
+
+func TestWarm(t *testing.T) {
+	g, err := NewGeobedFromRecords([]CityRecord{
+		{City: "Austin", Country: "US", Region: "TX", Latitude: 30.26715, Longitude: -97.74306, Population: 961855},
+		{City: "Pittsburgh", Country: "US", Region: "PA", Latitude: 40.44062, Longitude: -79.99589, Population: 302407},
+	}, nil, WithInitProfile(FastStart), WithQueryCache(8), WithFuzzyCache(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Warm([]string{"Austin, TX", "Pittsburg"}, GeocodeOptions{FuzzyDistance: 1})
+
+	if g.cellIndex == nil || g.keysByLen == nil || g.nameKeys == nil || g.bounds == nil || g.byPop == nil {
+		t.Error("Warm left a lazily built index unbuilt")
+	}
+	if st := g.QueryCacheStats(); st.Entries != 2 {
+		t.Errorf("QueryCacheStats().Entries = %d after Warm, want 2", st.Entries)
+	}
+	if st := g.FuzzyCacheStats(); st.Entries == 0 {
+		t.Error("FuzzyCacheStats().Entries = 0 after a fuzzy Warm")
+	}
+
+	g.Geocode("Austin, TX")
+	if st := g.QueryCacheStats(); st.Hits != 1 {
+		t.Errorf("QueryCacheStats().Hits = %d after a warmed query, want 1", st.Hits)
+	}
+}
@@ -0,0 +1,88 @@
+package geobed
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWarmStart(t *testing.T) {
+	dir := t.TempDir()
+	g, err := NewGeobed(WithWarmStart(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := g.warmStartPath()
+	if filepath.Dir(path) != dir {
+		t.Fatalf("warmStartPath() = %q, want a file in %q", path, dir)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		var w GeoBed
+		if err := w.loadWarmStart(path); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(w.Cities, g.Cities) {
+			t.Error("Cities differ after round trip")
+		}
+		if !reflect.DeepEqual(w.Countries, g.Countries) {
+			t.Error("Countries differ after round trip")
+		}
+		if !reflect.DeepEqual(w.nameIndex, g.nameIndex) {
+			t.Error("name index differs after round trip")
+		}
+	})
+
+	t.Run("corrupt snapshot", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, bad := range [][]byte{nil, []byte("not a snapshot"), data[:len(data)/2], append(data, 0)} {
+			bp := filepath.Join(t.TempDir(), "bad.warm")
+			if err := os.WriteFile(bp, bad, 0644); err != nil {
+				t.Fatal(err)
+			}
+			var w GeoBed
+			if err := w.loadWarmStart(bp); !errors.Is(err, errWarmStartCorrupt) {
+				t.Errorf("loadWarmStart(%d bytes) error = %v, want errWarmStartCorrupt", len(bad), err)
+			}
+			if w.Cities != nil || w.nameIndex != nil {
+				t.Error("loadWarmStart changed the instance on error")
+			}
+		}
+	})
+
+	t.Run("falls back and rewrites", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("garbage"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		g2, err := NewGeobed(WithWarmStart(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := g2.Geocode("Austin, TX"); c.City != "Austin" {
+			t.Errorf("Geocode(Austin, TX) = %q after fallback", c.City)
+		}
+		var w GeoBed
+		if err := w.loadWarmStart(path); err != nil {
+			t.Errorf("snapshot not rewritten: %v", err)
+		}
+	})
+}
+
+func TestWarmStartDisabled(t *testing.T) {
+	g := &GeoBed{config: defaultConfig()}
+	if p := g.warmStartPath(); p != "" {
+		t.Errorf("warmStartPath() = %q without WithWarmStart", p)
+	}
+	WithWarmStart("")(g.config)
+	if want := filepath.Join(os.TempDir(), "geobed-warm"); g.config.WarmStartDir != want {
+		t.Errorf("WithWarmStart(\"\") dir = %q, want %q", g.config.WarmStartDir, want)
+	}
+}